@@ -0,0 +1,516 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	configcmd "pomodoro-cli/cmd/pomodoro/internal/config"
+	exportcmd "pomodoro-cli/cmd/pomodoro/internal/export"
+	goalcmd "pomodoro-cli/cmd/pomodoro/internal/goal"
+	initcmd "pomodoro-cli/cmd/pomodoro/internal/init"
+	joincmd "pomodoro-cli/cmd/pomodoro/internal/join"
+	plancmd "pomodoro-cli/cmd/pomodoro/internal/plan"
+	"pomodoro-cli/cmd/pomodoro/internal/serve"
+	"pomodoro-cli/cmd/pomodoro/internal/start"
+	teamservercmd "pomodoro-cli/cmd/pomodoro/internal/teamserver"
+	"pomodoro-cli/internal/cli"
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/export"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/team"
+	"pomodoro-cli/internal/teamserver"
+	"pomodoro-cli/internal/ui"
+)
+
+// errReported は内容を表示済みのエラー（main はメッセージを出さずに終了する）
+var errReported = errors.New("error already reported")
+
+// globalOptions はすべてのコマンドで使える設定の上書きなどのフラグ
+type globalOptions struct {
+	workDuration, shortBreak, longBreak time.Duration
+	sessions                            int
+	task, tags, profile, configPath     string
+	noSound, noNotify                   bool
+	noAutoBreak, noAutoWork             bool
+	timewarriorLive, strict             bool
+}
+
+// newRootCommand はコマンドツリーを作成する
+func newRootCommand() *cli.Command {
+	var opts globalOptions
+
+	root := &cli.Command{
+		Name:        "pomodoro",
+		Summary:     "A pomodoro timer for the terminal",
+		Description: "A pomodoro timer for the terminal.\nWithout a command, starts the timer.",
+		Version:     version,
+		Args:        cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.Duration(&opts.workDuration, "work", "w", 0, "Work duration (e.g., 25m)").Inherited(),
+			cli.Duration(&opts.shortBreak, "short-break", "s", 0, "Short break duration (e.g., 5m)").Inherited(),
+			cli.Duration(&opts.longBreak, "long-break", "l", 0, "Long break duration (e.g., 15m)").Inherited(),
+			cli.Int(&opts.sessions, "sessions", "n", 0, "Sessions until long break (e.g., 4)").Inherited(),
+			cli.String(&opts.profile, "profile", "p", "", "Configuration profile (e.g., deep)").
+				Placeholder("NAME").Completion(profileNames).Inherited(),
+			cli.String(&opts.configPath, "config", "", "", "Config file to use (or POMODORO_CONFIG)").
+				Placeholder("FILE").Inherited(),
+			cli.String(&opts.task, "task", "t", "", "Task name recorded with sessions").
+				Placeholder("NAME").Completion(taskNames).Inherited(),
+			cli.String(&opts.tags, "tags", "", "", "Comma-separated tags (e.g., docs,review)").
+				Placeholder("TAGS").Inherited(),
+			cli.Bool(&opts.noSound, "no-sound", "", "Disable notification sound").Inherited(),
+			cli.Bool(&opts.noNotify, "no-notify", "", "Disable system notifications").Inherited(),
+			cli.Bool(&opts.noAutoBreak, "no-auto-break", "", "Disable auto-start breaks").Inherited(),
+			cli.Bool(&opts.noAutoWork, "no-auto-work", "", "Disable auto-start work").Inherited(),
+			cli.Bool(&opts.timewarriorLive, "timewarrior", "", "Track work sessions in Timewarrior").Inherited(),
+			cli.Bool(&opts.strict, "strict", "", "Require a confirmation phrase to pause, skip or reset work").Inherited(),
+		},
+	}
+
+	// runStart はタイマーを起動する（ルートと start で共通）
+	runStart := func([]string) error {
+		cfg, err := opts.load(false)
+		if err != nil {
+			return err
+		}
+		return start.Run(cfg, start.Options{Task: opts.task})
+	}
+	root.Run = runStart
+
+	root.Add(
+		&cli.Command{
+			Name:    "start",
+			Summary: "Start the pomodoro timer (default)",
+			Args:    cli.NoArgs,
+			Run:     runStart,
+		},
+		newConfigCommand(&opts),
+		newInitCommand(&opts),
+		&cli.Command{
+			Name:    "goal",
+			Summary: "Show daily/weekly goal progress and streak",
+			Args:    cli.NoArgs,
+			Run: func([]string) error {
+				cfg, err := opts.load(false)
+				if err != nil {
+					return err
+				}
+				return goalcmd.Run(cfg)
+			},
+		},
+		newPlanCommand(&opts),
+		newExportCommand(&opts),
+		newServeCommand(&opts),
+		newHostCommand(&opts),
+		newJoinCommand(&opts),
+		newServerCommand(),
+	)
+	root.Add(cli.CompletionCommands(root)...)
+	root.Add(cli.DocCommands(root)...)
+	return root
+}
+
+// newConfigCommand は config コマンドとそのサブコマンドを作成する
+func newConfigCommand(opts *globalOptions) *cli.Command {
+	var explain bool
+	cmd := &cli.Command{
+		Name:    "config",
+		Summary: "Show, check and edit the configuration",
+		Description: `Show the configuration, including the profile and command-line flags.
+Subcommands check, get, set, unset, path and edit work with the config file.`,
+		Args: cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.Bool(&explain, "explain", "", "Show where each value comes from"),
+		},
+		Run: func([]string) error {
+			cfg, err := opts.load(false)
+			if err != nil {
+				return err
+			}
+			return configcmd.Show(cfg, explain)
+		},
+	}
+
+	// 設定ファイルを直すためのサブコマンドは設定に問題があっても実行する
+	tolerant := func(run func(args []string) error) func([]string) error {
+		return func(args []string) error {
+			if _, err := opts.load(true); err != nil {
+				return err
+			}
+			return run(args)
+		}
+	}
+	return cmd.Add(
+		&cli.Command{
+			Name:    "check",
+			Summary: "Validate the config file and all profiles",
+			Args:    cli.NoArgs,
+			Run:     tolerant(func([]string) error { return configcmd.Check() }),
+		},
+		&cli.Command{
+			Name:     "get",
+			Usage:    "KEY",
+			Summary:  "Print a configuration value",
+			Args:     cli.ExactArgs(1),
+			Complete: completeConfigKey,
+			Run: func(args []string) error {
+				cfg, err := opts.load(false)
+				if err != nil {
+					return err
+				}
+				return configcmd.Get(cfg, args[0])
+			},
+		},
+		&cli.Command{
+			Name:     "set",
+			Usage:    "KEY VALUE",
+			Summary:  "Set a value in the config file (comments are kept)",
+			Args:     cli.ExactArgs(2),
+			Complete: completeConfigKey,
+			Run: tolerant(func(args []string) error {
+				return configcmd.Set(args[0], args[1])
+			}),
+		},
+		&cli.Command{
+			Name:     "unset",
+			Usage:    "KEY",
+			Summary:  "Remove a value from the config file (back to the default)",
+			Args:     cli.ExactArgs(1),
+			Complete: completeConfigKey,
+			Run: tolerant(func(args []string) error {
+				return configcmd.Unset(args[0])
+			}),
+		},
+		&cli.Command{
+			Name:    "path",
+			Summary: "Print the path of the config file",
+			Args:    cli.NoArgs,
+			Run:     tolerant(func([]string) error { return configcmd.Path() }),
+		},
+		&cli.Command{
+			Name:    "edit",
+			Summary: "Open the config file in $EDITOR and validate it",
+			Args:    cli.NoArgs,
+			Run:     tolerant(func([]string) error { return configcmd.Edit() }),
+		},
+	)
+}
+
+// newInitCommand は init コマンドを作成する
+func newInitCommand(opts *globalOptions) *cli.Command {
+	var format string
+	formats := make([]string, len(config.Formats))
+	for i, f := range config.Formats {
+		formats[i] = string(f)
+	}
+	return &cli.Command{
+		Name:        "init",
+		Summary:     "Create the config file interactively",
+		Description: "Create the config file interactively.\nWith --profile, create or edit that profile instead.",
+		Args:        cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.String(&format, "format", "f", "", "Config file format ("+strings.Join(formats, ", ")+")").
+				Placeholder("FORMAT").Values(formats...),
+		},
+		Run: func([]string) error {
+			if _, err := opts.load(true); err != nil {
+				return err
+			}
+			return initcmd.Run(opts.profile, format)
+		},
+	}
+}
+
+// newPlanCommand は plan コマンドを作成する
+func newPlanCommand(opts *globalOptions) *cli.Command {
+	var planOpts plancmd.Options
+	return &cli.Command{
+		Name:    "plan",
+		Summary: "Print a timetable of sessions and breaks without starting the timer",
+		Description: `Print a timetable of sessions and breaks without starting the timer.
+Uses the configured durations, so profiles and flags such as -w apply.
+
+  pomodoro plan --start 13:00 --until 18:00
+  pomodoro plan --count 8 --format ics -o afternoon.ics`,
+		Args: cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.String(&planOpts.Start, "start", "", "", "Start time (HH:MM, YYYY-MM-DD HH:MM or now; default now)").
+				Placeholder("TIME"),
+			cli.String(&planOpts.Until, "until", "u", "", "Plan work sessions that end by this time").
+				Placeholder("TIME"),
+			cli.Int(&planOpts.Count, "count", "c", 0, "Number of work sessions to plan"),
+			cli.String(&planOpts.Format, "format", "f", "text", "Output format ("+strings.Join(plancmd.Formats, ", ")+")").
+				Placeholder("FORMAT").Values(plancmd.Formats...),
+			cli.String(&planOpts.Output, "output", "o", "", "Write the ICS to a file instead of stdout").
+				Placeholder("FILE"),
+		},
+		Run: func([]string) error {
+			cfg, err := opts.load(false)
+			if err != nil {
+				return err
+			}
+			return plancmd.Run(cfg, opts.task, planOpts)
+		},
+	}
+}
+
+// newExportCommand は export コマンドを作成する
+func newExportCommand(opts *globalOptions) *cli.Command {
+	var exportOpts exportcmd.Options
+	formats := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		formats[i] = string(f)
+	}
+	return &cli.Command{
+		Name:    "export",
+		Summary: "Export history (" + strings.Join(formats, ", ") + ")",
+		Args:    cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.String(&exportOpts.Format, "format", "f", string(export.FormatCSV), "Output format ("+strings.Join(formats, ", ")+")").
+				Placeholder("FORMAT").Values(formats...),
+			cli.String(&exportOpts.Since, "since", "", "", "Only sessions started at or after this time (e.g., 2026-01-05, 7d)").
+				Placeholder("TIME"),
+			cli.String(&exportOpts.Until, "until", "", "", "Only sessions started before this time (dates are inclusive)").
+				Placeholder("TIME"),
+			cli.String(&exportOpts.Output, "output", "o", "", "Write to a file instead of stdout").
+				Placeholder("FILE"),
+		},
+		Run: func([]string) error {
+			if _, err := opts.load(false); err != nil {
+				return err
+			}
+			return exportcmd.Run(exportOpts)
+		},
+	}
+}
+
+// newServeCommand は serve コマンドを作成する
+func newServeCommand(opts *globalOptions) *cli.Command {
+	var serveOpts serve.Options
+	return &cli.Command{
+		Name:    "serve",
+		Summary: "Serve the HTTP API",
+		Args:    cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.String(&serveOpts.Listen, "listen", "", serve.DefaultListen, "Address to listen on").
+				Placeholder("ADDR"),
+			cli.Bool(&serveOpts.Metrics, "metrics", "", "Expose Prometheus metrics on /metrics"),
+		},
+		Run: func([]string) error {
+			cfg, err := opts.load(false)
+			if err != nil {
+				return err
+			}
+			return serve.Run(cfg, serveOpts)
+		},
+	}
+}
+
+// newHostCommand は host コマンドを作成する
+func newHostCommand(opts *globalOptions) *cli.Command {
+	var listen string
+	return &cli.Command{
+		Name:    "host",
+		Summary: "Start the timer and share it with teammates on the network",
+		Description: `Start the timer and share it with teammates on the network.
+Teammates run pomodoro join ADDR to see the same countdown. They can propose
+to pause or skip, and you approve or decline each proposal.`,
+		Args: cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.String(&listen, "listen", "", team.DefaultListen, "Address to listen on").
+				Placeholder("ADDR"),
+		},
+		Run: func([]string) error {
+			cfg, err := opts.load(false)
+			if err != nil {
+				return err
+			}
+			return start.Run(cfg, start.Options{Task: opts.task, Listen: listen})
+		},
+	}
+}
+
+// newJoinCommand は join コマンドを作成する
+func newJoinCommand(opts *globalOptions) *cli.Command {
+	var joinOpts joincmd.Options
+	return &cli.Command{
+		Name:    "join",
+		Usage:   "ADDR",
+		Summary: "Join a teammate's shared timer",
+		Description: `Join a teammate's shared timer started with pomodoro host.
+ADDR is host[:port] (port 7626 by default). Pause and skip keys send a
+proposal to the host. Completed sessions are recorded in your own history.`,
+		Args: cli.ExactArgs(1),
+		Flags: []*cli.Flag{
+			cli.String(&joinOpts.Name, "name", "", "", "Name shown to the host (default $USER)").
+				Placeholder("NAME"),
+		},
+		Run: func(args []string) error {
+			cfg, err := opts.load(false)
+			if err != nil {
+				return err
+			}
+			joinOpts.Addr = args[0]
+			return joincmd.Run(cfg, joinOpts)
+		},
+	}
+}
+
+// newServerCommand は server コマンドとそのサブコマンドを作成する
+func newServerCommand() *cli.Command {
+	var serverOpts teamservercmd.Options
+	cmd := &cli.Command{
+		Name:    "server",
+		Summary: "Run a team history server that members upload sessions to",
+		Description: `Run a team history server. Members set team_server and team_token in
+their config, and completed work sessions are uploaded to it (kept locally
+while the server is unreachable). GET /api/totals?period=day|week&date=YYYY-MM-DD
+returns the team totals. Add members with pomodoro server add-user NAME.`,
+		Args: cli.NoArgs,
+		Flags: []*cli.Flag{
+			cli.String(&serverOpts.Listen, "listen", "", teamserver.DefaultListen, "Address to listen on").
+				Placeholder("ADDR"),
+			cli.String(&serverOpts.Data, "data", "", "", "Data directory (default: server in the config directory)").
+				Placeholder("DIR").Inherited(),
+		},
+		Run: func([]string) error {
+			return teamservercmd.Run(serverOpts)
+		},
+	}
+	return cmd.Add(
+		&cli.Command{
+			Name:    "add-user",
+			Usage:   "NAME",
+			Summary: "Register a member and print their token (renews an existing token)",
+			Args:    cli.ExactArgs(1),
+			Run: func(args []string) error {
+				return teamservercmd.AddUser(serverOpts, args[0])
+			},
+		},
+	)
+}
+
+// ----------------------------------------------------------------------------
+// 設定の読み込み
+// ----------------------------------------------------------------------------
+
+// load は設定を読み込み、コマンドラインのフラグで上書きする
+// 設定ファイルがなければデフォルト設定を使い、問題があれば tolerate でない限り中止する
+// --config や POMODORO_CONFIG で明示したファイルがない場合も中止する
+func (o *globalOptions) load(tolerate bool) (*config.Config, error) {
+	config.SetPath(o.configPath)
+	cfg, loadErr := config.LoadProfile(o.profile)
+	for _, warning := range cfg.Warnings {
+		ui.ShowWarning(warning)
+	}
+	if loadErr != nil && !tolerate {
+		if !errors.Is(loadErr, fs.ErrNotExist) {
+			ui.ShowConfigProblems(config.Problems(loadErr))
+			return nil, errReported
+		}
+		if path := config.ExplicitPath(); path != "" {
+			ui.ShowError("Config file not found: " + path)
+			return nil, errReported
+		}
+	}
+	o.apply(cfg)
+	return cfg, nil
+}
+
+// apply はフラグによる上書きを適用する（環境変数よりも優先する）
+func (o *globalOptions) apply(cfg *config.Config) {
+	fromFlag := func(key, name string) {
+		cfg.SetSource(key, config.Source{Kind: config.SourceFlag, Name: name})
+	}
+	if o.workDuration > 0 {
+		cfg.WorkDuration = o.workDuration
+		fromFlag("work_duration", "--work")
+	}
+	if o.shortBreak > 0 {
+		cfg.ShortBreakDuration = o.shortBreak
+		fromFlag("short_break_duration", "--short-break")
+	}
+	if o.longBreak > 0 {
+		cfg.LongBreakDuration = o.longBreak
+		fromFlag("long_break_duration", "--long-break")
+	}
+	if o.sessions > 0 {
+		cfg.SessionsUntilLong = o.sessions
+		fromFlag("sessions_until_long_break", "--sessions")
+	}
+	if o.tags != "" {
+		cfg.Tags = splitTags(o.tags)
+		fromFlag("tags", "--tags")
+	}
+	if o.noSound {
+		cfg.SoundEnabled = false
+		fromFlag("sound_enabled", "--no-sound")
+	}
+	if o.noNotify {
+		cfg.NotifyEnabled = false
+		fromFlag("notify_enabled", "--no-notify")
+	}
+	if o.noAutoBreak {
+		cfg.AutoStartBreaks = false
+		fromFlag("auto_start_breaks", "--no-auto-break")
+	}
+	if o.noAutoWork {
+		cfg.AutoStartWork = false
+		fromFlag("auto_start_work", "--no-auto-work")
+	}
+	if o.timewarriorLive {
+		cfg.TimewarriorLive = true
+		fromFlag("timewarrior_live", "--timewarrior")
+	}
+	if o.strict {
+		cfg.Strict = true
+		fromFlag("strict", "--strict")
+	}
+}
+
+// splitTags はカンマ区切りのタグを分割する（空の要素は除く）
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ----------------------------------------------------------------------------
+// 補完候補
+// ----------------------------------------------------------------------------
+
+// profileNames は設定ファイルに定義されたプロファイル名を返す
+func profileNames() []string {
+	cfg, _ := config.ReadBase()
+	return slices.Sorted(maps.Keys(cfg.Profiles))
+}
+
+// taskNames は履歴に記録されたタスク名を最近使った順に返す
+func taskNames() []string {
+	path, err := history.Path()
+	if err != nil {
+		return nil
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		return nil
+	}
+	return store.Tasks()
+}
+
+// completeConfigKey は最初の位置引数を設定キーから補完する
+func completeConfigKey(args []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	cfg, _ := config.ReadBase()
+	return cfg.Keys()
+}
@@ -1,11 +1,175 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+
 	internalconfig "pomodoro-cli/internal/config"
 	"pomodoro-cli/internal/ui"
 )
 
-// Run はconfigコマンドを実行する
-func Run(cfg *internalconfig.Config) {
+// Show は現在の設定（プロファイルとコマンドラインの指定を含む）を表示する
+// explain が true なら各値がデフォルト・設定ファイル・プロジェクト・プロファイル・
+// 環境変数・フラグのどれから来たかを表示する
+func Show(cfg *internalconfig.Config, explain bool) error {
+	if explain {
+		return showExplain(cfg)
+	}
 	ui.ShowConfig(cfg)
+	return nil
+}
+
+// showExplain は各設定値とその出どころを表示する
+func showExplain(cfg *internalconfig.Config) error {
+	path, err := internalconfig.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	settings, err := cfg.Explain()
+	if err != nil {
+		return err
+	}
+	ui.ShowConfigExplain(cfg, path, settings)
+	return nil
+}
+
+// Check は設定ファイルとすべてのプロファイルを検証する
+// 問題があればエラーを返す（終了コードは非ゼロになる）
+func Check() error {
+	path, err := internalconfig.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+
+	// 検証だけなので、古い形式のファイルも移行して書き換えない
+	_, err = internalconfig.ReadBase()
+	if errors.Is(err, fs.ErrNotExist) {
+		ui.ShowConfigMissing(path)
+		return nil
+	}
+	problems := internalconfig.Problems(err)
+	ui.ShowConfigCheck(path, problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("configuration has %d problem(s)", len(problems))
+	}
+	return nil
+}
+
+// Get は現在の設定（プロファイルとコマンドラインの指定を含む）の値を表示する
+func Get(cfg *internalconfig.Config, key string) error {
+	value, err := cfg.Get(key)
+	if err != nil {
+		return err
+	}
+	ui.ShowConfigValue(value)
+	return nil
+}
+
+// Set は設定ファイルの値を1つ書き換える（TOML/YAMLのコメントは残る）
+func Set(key, value string) error {
+	path, err := internalconfig.Set(key, value)
+	if err != nil {
+		if problems := internalconfig.Problems(err); len(problems) > 1 {
+			ui.ShowConfigProblems(problems)
+			return errors.New("config was not changed")
+		}
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	ui.ShowConfigSet(key, value, path)
+	return nil
+}
+
+// Unset は設定ファイルから値を取り除き、デフォルト値に戻す
+func Unset(key string) error {
+	path, removed, err := internalconfig.Unset(key)
+	if err != nil {
+		if problems := internalconfig.Problems(err); len(problems) > 1 {
+			ui.ShowConfigProblems(problems)
+			return errors.New("config was not changed")
+		}
+		return fmt.Errorf("failed to unset %s: %w", key, err)
+	}
+	ui.ShowConfigUnset(key, path, removed)
+	return nil
+}
+
+// Path は使用中の設定ファイルのパスを表示する（まだなければ作成される場所）
+func Path() error {
+	path, err := internalconfig.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	ui.ShowConfigPath(path)
+	return nil
+}
+
+// Edit はエディタで設定ファイルを開き、保存後に検証する
+// 問題があれば編集し直すか尋ね、やめた場合は編集前の内容に戻す
+func Edit() error {
+	path, err := internalconfig.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	original, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	// 設定ファイルがなければデフォルト設定を書き出してから開く
+	if !existed {
+		if err := internalconfig.Default().SaveTo(path); err != nil {
+			return fmt.Errorf("failed to create config: %w", err)
+		}
+	}
+	restore := func() {
+		if existed {
+			_ = internalconfig.WriteFile(path, original)
+		} else {
+			_ = os.Remove(path)
+		}
+	}
+
+	for {
+		if err := runEditor(path); err != nil {
+			restore()
+			return fmt.Errorf("failed to run editor: %w", err)
+		}
+
+		_, err := internalconfig.LoadBase()
+		if errors.Is(err, fs.ErrNotExist) {
+			ui.ShowConfigMissing(path)
+			return nil
+		}
+		problems := internalconfig.Problems(err)
+		ui.ShowConfigCheck(path, problems)
+		if len(problems) == 0 {
+			return nil
+		}
+		if !ui.Confirm("Edit again?", true) {
+			restore()
+			return errors.New("config was not changed")
+		}
+	}
+}
+
+// runEditor は $VISUAL か $EDITOR（どちらもなければ vi）で path を開く
+// "code --wait" のように引数を含むエディタの指定にも対応する
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
 }
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"pomodoro-cli/internal/export"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/ui"
+)
+
+// Options はexportコマンドのオプション
+type Options struct {
+	// Format は出力形式（csv, json, ics, timew, org）
+	Format string
+	// Since と Until は対象とするセッションの開始日時の範囲（空なら制限なし）
+	Since string
+	Until string
+	// Output は出力先のファイル（空なら標準出力）
+	Output string
+}
+
+// Run は履歴を指定された形式でエクスポートする
+func Run(opts Options) error {
+	format, err := export.ParseFormat(opts.Format)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	since, err := export.ParseSince(opts.Since, now)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := export.ParseUntil(opts.Until, now)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	path, err := history.Path()
+	if err != nil {
+		return fmt.Errorf("failed to get history path: %w", err)
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	for _, warning := range store.Warnings() {
+		ui.ShowWarning(warning)
+	}
+	records := export.Filter(store.Records(), since, until)
+
+	if opts.Output == "" {
+		return export.Write(os.Stdout, format, records, now)
+	}
+	f, err := os.Create(opts.Output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := export.Write(f, format, records, now); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,29 @@
+package goal
+
+import (
+	"fmt"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/goal"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/ui"
+)
+
+// Run は目標の進捗と連続日数を表示する
+func Run(cfg *config.Config) error {
+	path, err := history.Path()
+	if err != nil {
+		return fmt.Errorf("failed to get history path: %w", err)
+	}
+	store, err := history.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	for _, warning := range store.Warnings() {
+		ui.ShowWarning(warning)
+	}
+
+	ui.ShowGoal(goal.Compute(store.Records(), cfg, time.Now()))
+	return nil
+}
@@ -1,41 +1,88 @@
 package init
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"pomodoro-cli/internal/config"
 	"pomodoro-cli/internal/ui"
 )
 
 // Run は対話的に設定ファイルを作成する
-func Run() error {
-	path, err := config.ConfigPath()
+// profile を指定すると、そのプロファイルを作成・編集する
+// formatName で保存する形式（toml, yaml, json）を選べる（空なら現在の設定ファイルの形式）
+func Run(profile, formatName string) error {
+	currentPath, err := config.ConfigPath()
 	if err != nil {
 		return fmt.Errorf("failed to get config path: %w", err)
 	}
+	path := currentPath
+	if formatName != "" {
+		format, err := config.ParseFormat(formatName)
+		if err != nil {
+			return err
+		}
+		// 明示した設定ファイルの形式は拡張子で決まるので変えられない
+		if explicit := config.ExplicitPath(); explicit != "" && config.FormatOf(explicit) != format {
+			return fmt.Errorf("--format %s does not match %s; choose a config file with a .%s extension", format, explicit, format)
+		}
+		if config.ExplicitPath() == "" {
+			if path, err = config.PathFor(format); err != nil {
+				return fmt.Errorf("failed to get config path: %w", err)
+			}
+		}
+	}
 
 	// 現在の設定を読み込む（なければデフォルト）
-	// エラーは無視: config.Loadはファイルがない場合でもデフォルト値を返すため、
+	// エラーは無視: config.LoadBaseはファイルがない場合でもデフォルト値を返すため、
 	// initコマンドではエラーの有無に関わらず処理を継続する
-	current, _ := config.Load()
+	base, _ := config.LoadBase()
+	current := base
+	if profile != "" {
+		// 新しいプロファイルは基本設定から始める
+		merged, err := base.WithProfile(profile)
+		if err != nil && !errors.Is(err, config.ErrUnknownProfile) {
+			return fmt.Errorf("failed to load profile: %w", err)
+		}
+		current = merged
+	}
 	defaults := config.Default()
 
-	ui.ShowInitHeader()
-
-	cfg := &config.Config{
-		WorkDuration:       ui.PromptDuration("Work duration", current.WorkDuration, ui.FormatDuration(defaults.WorkDuration)),
-		ShortBreakDuration: ui.PromptDuration("Short break duration", current.ShortBreakDuration, ui.FormatDuration(defaults.ShortBreakDuration)),
-		LongBreakDuration:  ui.PromptDuration("Long break duration", current.LongBreakDuration, ui.FormatDuration(defaults.LongBreakDuration)),
-		SessionsUntilLong:  ui.PromptInt("Sessions until long break", current.SessionsUntilLong, defaults.SessionsUntilLong),
-		AutoStartBreaks:    ui.PromptBool("Auto-start breaks", current.AutoStartBreaks, defaults.AutoStartBreaks),
-		AutoStartWork:      ui.PromptBool("Auto-start work", current.AutoStartWork, defaults.AutoStartWork),
-		SoundEnabled:       ui.PromptBool("Enable sound", current.SoundEnabled, defaults.SoundEnabled),
-		NotifyEnabled:      ui.PromptBool("Enable notifications", current.NotifyEnabled, defaults.NotifyEnabled),
+	ui.ShowInitHeader(profile)
+
+	cfg := *current
+	cfg.WorkDuration = ui.PromptDuration("Work duration", current.WorkDuration, ui.FormatDuration(defaults.WorkDuration))
+	cfg.ShortBreakDuration = ui.PromptDuration("Short break duration", current.ShortBreakDuration, ui.FormatDuration(defaults.ShortBreakDuration))
+	cfg.LongBreakDuration = ui.PromptDuration("Long break duration", current.LongBreakDuration, ui.FormatDuration(defaults.LongBreakDuration))
+	cfg.SessionsUntilLong = ui.PromptInt("Sessions until long break", current.SessionsUntilLong, defaults.SessionsUntilLong)
+	cfg.AutoStartBreaks = ui.PromptBool("Auto-start breaks", current.AutoStartBreaks, defaults.AutoStartBreaks)
+	cfg.AutoStartWork = ui.PromptBool("Auto-start work", current.AutoStartWork, defaults.AutoStartWork)
+	cfg.SoundEnabled = ui.PromptBool("Enable sound", current.SoundEnabled, defaults.SoundEnabled)
+	cfg.NotifyEnabled = ui.PromptBool("Enable notifications", current.NotifyEnabled, defaults.NotifyEnabled)
+	cfg.DailyGoal = ui.PromptInt("Daily goal (pomodoros, 0 to disable)", current.DailyGoal, 8)
+	cfg.WeeklyGoal = ui.PromptInt("Weekly goal (pomodoros, 0 to disable)", current.WeeklyGoal, 40)
+
+	if profile != "" {
+		if err := base.SetProfile(profile, &cfg); err != nil {
+			return fmt.Errorf("failed to update profile: %w", err)
+		}
+		cfg = *base
 	}
 
-	if err := cfg.Save(); err != nil {
+	if err := cfg.SaveTo(path); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
+	// 形式を変えた場合は、優先されてしまわないよう古いファイルを退避する
+	if path != currentPath {
+		if _, err := os.Stat(currentPath); err == nil {
+			backup := currentPath + ".bak"
+			if err := os.Rename(currentPath, backup); err != nil {
+				return fmt.Errorf("failed to move old config: %w", err)
+			}
+			ui.ShowWarning(fmt.Sprintf("moved %s to %s", currentPath, backup))
+		}
+	}
 
 	ui.ShowConfigCreated(path)
 	return nil
@@ -20,7 +20,7 @@ import (
 func TestRunはデフォルト値で設定ファイルを作成する(t *testing.T) {
 	withTempHome(t, func(tmpHome string) {
 		withStdinInput(t, "\n\n\n\n\n\n\n\n", func() {
-			err := Run()
+			err := Run("", "")
 			if err != nil {
 				t.Fatalf("Run() error = %v", err)
 			}
@@ -50,7 +50,7 @@ func TestRunはカスタム値を保存する(t *testing.T) {
 	withTempHome(t, func(tmpHome string) {
 		input := "30m\n10m\n20m\n6\nn\nn\ny\ny\n"
 		withStdinInput(t, input, func() {
-			err := Run()
+			err := Run("", "")
 			if err != nil {
 				t.Fatalf("Run() error = %v", err)
 			}
@@ -99,7 +99,7 @@ func TestRunは既存の設定値をEnterで維持する(t *testing.T) {
 		}
 
 		withStdinInput(t, "\n\n\n\n\n\n\n\n", func() {
-			err := Run()
+			err := Run("", "")
 			if err != nil {
 				t.Fatalf("Run() error = %v", err)
 			}
@@ -122,6 +122,39 @@ func TestRunは既存の設定値をEnterで維持する(t *testing.T) {
 	})
 }
 
+func TestRunはプロファイルに基本設定との差分だけを保存する(t *testing.T) {
+	withTempHome(t, func(tmpHome string) {
+		if err := config.Default().Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		withStdinInput(t, "50m\n10m\n\n\n\n\n\n\n\n\n", func() {
+			if err := Run("deep", ""); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+		})
+
+		base, err := config.LoadBase()
+		if err != nil {
+			t.Fatalf("LoadBase() error = %v", err)
+		}
+		if base.WorkDuration != 25*time.Minute {
+			t.Errorf("base WorkDuration = %v, want 25m", base.WorkDuration)
+		}
+		if got := base.Profiles["deep"]; len(got) != 2 {
+			t.Errorf("profile deep = %v, want work and short break durations only", got)
+		}
+
+		deep, err := config.LoadProfile("deep")
+		if err != nil {
+			t.Fatalf("LoadProfile() error = %v", err)
+		}
+		if deep.WorkDuration != 50*time.Minute || deep.ShortBreakDuration != 10*time.Minute {
+			t.Errorf("deep durations = %v/%v, want 50m/10m", deep.WorkDuration, deep.ShortBreakDuration)
+		}
+	})
+}
+
 // =============================================================================
 // Config Format - 設定ファイルのフォーマット
 // =============================================================================
@@ -129,7 +162,7 @@ func TestRunは既存の設定値をEnterで維持する(t *testing.T) {
 func TestRunは正しいJSON形式で設定を保存する(t *testing.T) {
 	withTempHome(t, func(tmpHome string) {
 		withStdinInput(t, "\n\n\n\n\n\n\n\n", func() {
-			err := Run()
+			err := Run("", "")
 			if err != nil {
 				t.Fatalf("Run() error = %v", err)
 			}
@@ -172,6 +205,9 @@ func TestRunは正しいJSON形式で設定を保存する(t *testing.T) {
 func withTempHome(t *testing.T, fn func(tmpHome string)) {
 	t.Helper()
 	tmpDir := t.TempDir()
+	// 設定ファイルの場所を変える環境変数があればテスト中は無効にする
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("POMODORO_CONFIG", "")
 	origHome := os.Getenv("HOME")
 	if err := os.Setenv("HOME", tmpDir); err != nil {
 		t.Fatalf("failed to set HOME: %v", err)
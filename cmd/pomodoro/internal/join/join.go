@@ -0,0 +1,277 @@
+package join
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"pomodoro-cli/cmd/pomodoro/internal/start"
+	"pomodoro-cli/internal/activity"
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/goal"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/team"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// Options は join コマンドのオプション
+type Options struct {
+	// Addr はホストのアドレス（ポートを省略すると 7626）
+	Addr string
+	// Name はホストに表示する名前（空ならユーザー名）
+	Name string
+}
+
+// proposer はホストに提案を送り、時計のずれを返す（テストで差し替える）
+type proposer interface {
+	Propose(action string) error
+	Offset() time.Duration
+}
+
+// participant はホストのタイマーを表示し、完了したセッションを自分の履歴に記録する
+type participant struct {
+	cfg      *config.Config
+	store    *history.Store
+	bindings keys.Bindings
+	client   proposer
+	state    *team.State
+	progress goal.Progress
+	// activities は休憩の過ごし方を選ぶ
+	activities *activity.Picker
+	// upload は記録をチームサーバーへ送る（設定がなければ何もしない）
+	upload func(history.Record)
+	// recorded は記録済みのセッションの開始時刻（同じ完了を二重に記録しない）
+	recorded map[time.Time]bool
+}
+
+func newParticipant(cfg *config.Config, store *history.Store, bindings keys.Bindings, client proposer) *participant {
+	p := &participant{
+		cfg: cfg, store: store, bindings: bindings, client: client,
+		activities: activity.NewPicker(cfg.BreakActivities),
+		upload:     func(history.Record) {},
+		recorded:   make(map[time.Time]bool),
+	}
+	p.refreshProgress()
+	return p
+}
+
+// Run はホストに参加してタイマーを表示する
+func Run(cfg *config.Config, opts Options) error {
+	historyPath, err := history.Path()
+	if err != nil {
+		return fmt.Errorf("failed to get history path: %w", err)
+	}
+	store, err := history.Open(historyPath)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	for _, warning := range store.Warnings() {
+		ui.ShowWarning(warning)
+	}
+	bindings, err := keys.NewBindings(cfg.Keybindings)
+	if err != nil {
+		return fmt.Errorf("invalid keybindings: %w", err)
+	}
+	addr := Address(opts.Addr)
+	name := opts.Name
+	if name == "" {
+		name = defaultName()
+	}
+
+	if err := ui.InitInput(); err != nil {
+		return fmt.Errorf("failed to initialize input: %w", err)
+	}
+	defer ui.RestoreInput()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	client := team.Join(addr, name)
+	defer client.Close()
+	p := newParticipant(cfg, store, bindings, client)
+	upload, stopUpload := start.UploadToTeam(cfg)
+	defer stopUpload()
+	p.upload = upload
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigChan:
+			ui.ShowExit()
+			return nil
+		case key := <-ui.KeyChan():
+			if p.handleKey(key) {
+				ui.ShowExit()
+				return nil
+			}
+		case msg := <-client.Messages():
+			p.handleMessage(msg, time.Now())
+		case conn := <-client.Connections():
+			if conn.Connected {
+				ui.ShowConnected(addr)
+			} else {
+				ui.ShowDisconnected(conn.Err)
+			}
+		case <-ticker.C:
+			p.render(time.Now())
+		}
+	}
+}
+
+// Address はポートを省略したアドレスにデフォルトのポートを付ける
+func Address(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	_, port, _ := net.SplitHostPort(team.DefaultListen)
+	return net.JoinHostPort(addr, port)
+}
+
+// defaultName はホストに表示する名前（ユーザー名、なければホスト名）を返す
+func defaultName() string {
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	if host, err := os.Hostname(); err == nil {
+		return host
+	}
+	return "guest"
+}
+
+// handleKey は一時停止・スキップをホストに提案する（終了時 true を返す）
+func (p *participant) handleKey(key keys.Key) bool {
+	switch p.bindings.Action(key) {
+	case keys.ActionPause:
+		p.propose(team.ActionPause)
+	case keys.ActionSkip:
+		p.propose(team.ActionSkip)
+	case keys.ActionGoal:
+		p.refreshProgress()
+		ui.ShowGoalInSession(p.progress)
+	case keys.ActionQuit:
+		return true
+	}
+	return false
+}
+
+// propose はホストに提案を送る
+func (p *participant) propose(action string) {
+	if err := p.client.Propose(action); err != nil {
+		ui.ShowError("Failed to propose: " + err.Error())
+		return
+	}
+	ui.ShowProposalSent(action)
+}
+
+// handleMessage はホストからのメッセージを処理する
+func (p *participant) handleMessage(msg team.Message, now time.Time) {
+	switch msg.Type {
+	case team.MsgState:
+		p.reconcile(msg.State, now)
+		p.state = msg.State
+	case team.MsgEvent:
+		p.reconcile(msg.State, now)
+		p.state = msg.State
+		if msg.State == nil {
+			return
+		}
+		switch msg.Event {
+		case timer.EventStarted.String():
+			ui.ShowStartSession(msg.State.Session)
+		case timer.EventCompleted.String():
+			p.complete(msg.State, now)
+		}
+	case team.MsgDecision:
+		ui.ShowProposalDecided(msg.Name, msg.Action, msg.Approved)
+	}
+}
+
+// reconcile は前に受け取った状態と比べて、取りこぼした完了を記録する
+// 再接続している間にセッションが終わり、ホストが次のセッションを始めていても記録できるようにする
+func (p *participant) reconcile(next *team.State, now time.Time) {
+	last := p.state
+	if last == nil || next == nil {
+		return
+	}
+	if next.StartedAt.Equal(last.StartedAt) {
+		if next.Timer == timer.StateCompleted {
+			p.complete(next, now)
+		}
+		return
+	}
+	if !p.finished(last, next, now) {
+		return
+	}
+	missed := *last
+	missed.Timer = timer.StateCompleted
+	missed.Remaining = 0
+	missed.EndsAt = time.Time{}
+	if missed.Session == timer.SessionWork && last.Timer != timer.StateCompleted {
+		missed.CompletedWork++
+	}
+	p.complete(&missed, now)
+}
+
+// finished は次のセッション next が始まる前に、前のセッション last が完了していたかを返す
+// 作業セッションはホストの完了数が増えたか、休憩は終了予定時刻を過ぎていたかで判断する
+// （途中でスキップや停止をしたセッションは記録しない）
+func (p *participant) finished(last, next *team.State, now time.Time) bool {
+	if last.Timer == timer.StateCompleted {
+		return true
+	}
+	if last.Session == timer.SessionWork {
+		return next.CompletedWork > last.CompletedWork
+	}
+	return last.Timer == timer.StateRunning && last.RemainingAt(now, p.client.Offset()) == 0
+}
+
+// complete はホストで完了したセッションを自分の履歴に記録して知らせる
+func (p *participant) complete(state *team.State, now time.Time) {
+	if p.recorded[state.StartedAt] {
+		return
+	}
+	p.recorded[state.StartedAt] = true
+
+	session := state.SessionAt(now, 0)
+	record := history.NewRecord(session, history.OutcomeCompleted, now)
+	if err := p.store.Append(record); err != nil {
+		ui.ShowError("Failed to record history: " + err.Error())
+	}
+	p.upload(record)
+	p.refreshProgress()
+	// 次の休憩はホストが決めるので、ホストの完了数と自分の設定から見込む
+	next := (&timer.PomodoroState{CurrentSession: &session, CompletedWork: state.CompletedWork}).NextSessionType(p.cfg.SessionsUntilLong)
+	suggestion := start.SuggestBreak(p.activities, next)
+	ui.ShowSessionComplete(session.Type, p.progress, suggestion)
+	if p.cfg.NotifyEnabled {
+		if err := ui.NotifySessionComplete(session.Type, suggestion); err != nil {
+			ui.ShowError("Notification failed: " + err.Error())
+		}
+	}
+	if p.cfg.SoundEnabled {
+		if err := ui.PlaySound(); err != nil {
+			ui.ShowError("Sound playback failed: " + err.Error())
+		}
+	}
+}
+
+// render はホストの時計とのずれを補正した残り時間を表示する
+func (p *participant) render(now time.Time) {
+	if p.state == nil {
+		return
+	}
+	session := p.state.SessionAt(now, p.client.Offset())
+	ui.RenderTimer(&session, p.state.Timer, p.progress)
+}
+
+// refreshProgress は履歴から目標の進捗を再計算する
+func (p *participant) refreshProgress() {
+	p.progress = goal.Compute(p.store.Records(), p.cfg, time.Now())
+}
@@ -0,0 +1,174 @@
+package join
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/team"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// テスト用のヘルパー
+// =============================================================================
+
+// fakeClient は送った提案を記録する proposer
+type fakeClient struct {
+	proposals []string
+	err       error
+}
+
+func (f *fakeClient) Propose(action string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.proposals = append(f.proposals, action)
+	return nil
+}
+
+func (f *fakeClient) Offset() time.Duration { return 0 }
+
+func newTestParticipant(t *testing.T, client proposer) *participant {
+	t.Helper()
+	cfg := config.Default()
+	cfg.NotifyEnabled = false
+	cfg.SoundEnabled = false
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("history.Open() error = %v", err)
+	}
+	return newParticipant(cfg, store, keys.DefaultBindings(), client)
+}
+
+// =============================================================================
+// participant
+// =============================================================================
+
+func Testホストで完了した作業セッションを一度だけ記録する(t *testing.T) {
+	p := newTestParticipant(t, &fakeClient{})
+	now := time.Now()
+	state := &team.State{
+		Timer:     timer.StateCompleted,
+		Session:   timer.SessionWork,
+		Task:      "docs",
+		Duration:  25 * time.Minute,
+		StartedAt: now.Add(-25 * time.Minute),
+	}
+	completed := team.Message{Type: team.MsgEvent, Event: timer.EventCompleted.String(), State: state}
+
+	var uploaded []history.Record
+	p.upload = func(r history.Record) { uploaded = append(uploaded, r) }
+
+	p.handleMessage(completed, now)
+	// 再接続などで同じ完了が再び届いても記録しない
+	p.handleMessage(completed, now)
+
+	records := p.store.Records()
+	if len(records) != 1 {
+		t.Fatalf("records = %d, want 1", len(records))
+	}
+	if !records[0].IsCompletedWork() || records[0].Task != "docs" {
+		t.Errorf("record = %+v", records[0])
+	}
+	if p.progress.Today != 1 {
+		t.Errorf("progress today = %d, want 1", p.progress.Today)
+	}
+	if len(uploaded) != 1 {
+		t.Errorf("uploaded = %d records, want 1", len(uploaded))
+	}
+}
+
+func Test再接続の間に完了したセッションを状態から記録する(t *testing.T) {
+	p := newTestParticipant(t, &fakeClient{})
+	now := time.Now()
+	started := now.Add(-30 * time.Minute)
+	running := &team.State{
+		Timer:     timer.StateRunning,
+		Session:   timer.SessionWork,
+		Task:      "docs",
+		Duration:  25 * time.Minute,
+		Remaining: 25 * time.Minute,
+		StartedAt: started,
+		EndsAt:    started.Add(25 * time.Minute),
+	}
+	p.handleMessage(team.Message{Type: team.MsgState, State: running}, started)
+
+	// 切断中に作業セッションが完了し、ホストが休憩を始めていた
+	breakStarted := started.Add(25 * time.Minute)
+	shortBreak := &team.State{
+		Timer:         timer.StateRunning,
+		Session:       timer.SessionShortBreak,
+		Duration:      5 * time.Minute,
+		Remaining:     5 * time.Minute,
+		StartedAt:     breakStarted,
+		CompletedWork: 1,
+		EndsAt:        breakStarted.Add(5 * time.Minute),
+	}
+	p.handleMessage(team.Message{Type: team.MsgState, State: shortBreak}, now)
+	p.handleMessage(team.Message{Type: team.MsgState, State: shortBreak}, now)
+
+	records := p.store.Records()
+	if len(records) != 1 || !records[0].IsCompletedWork() || records[0].Task != "docs" || !records[0].StartedAt.Equal(started) {
+		t.Fatalf("records = %+v, want the missed work session", records)
+	}
+
+	// 終了予定時刻を過ぎた休憩は完了として記録する
+	work := &team.State{Timer: timer.StateRunning, Session: timer.SessionWork, Duration: 25 * time.Minute,
+		Remaining: 25 * time.Minute, StartedAt: now.Add(time.Minute), CompletedWork: 1}
+	p.handleMessage(team.Message{Type: team.MsgState, State: work}, now.Add(time.Minute))
+	records = p.store.Records()
+	if len(records) != 2 || records[1].Type != timer.SessionShortBreak || records[1].Outcome != history.OutcomeCompleted {
+		t.Fatalf("records = %+v, want the finished break", records)
+	}
+
+	// 途中でスキップした作業セッションは記録しない（完了数が増えていない）
+	next := *work
+	next.StartedAt = now.Add(2 * time.Minute)
+	p.handleMessage(team.Message{Type: team.MsgState, State: &next}, now.Add(2*time.Minute))
+	if got := len(p.store.Records()); got != 2 {
+		t.Errorf("records = %d, want 2 (the skipped work session is not recorded)", got)
+	}
+}
+
+func Test一時停止とスキップのキーはホストに提案する(t *testing.T) {
+	client := &fakeClient{}
+	p := newTestParticipant(t, client)
+
+	p.handleKey("space")
+	p.handleKey("s")
+	if len(client.proposals) != 2 || client.proposals[0] != team.ActionPause || client.proposals[1] != team.ActionSkip {
+		t.Errorf("proposals = %v, want [pause skip]", client.proposals)
+	}
+	if p.handleKey("r") {
+		t.Error("リセットで終了した")
+	}
+	if !p.handleKey("q") {
+		t.Error("q で終了しない")
+	}
+
+	client.err = errors.New("not connected")
+	p.handleKey("space")
+	if len(client.proposals) != 2 {
+		t.Errorf("接続していないのに提案を記録した: %v", client.proposals)
+	}
+}
+
+func TestAddressはポートを省略するとデフォルトのポートを付ける(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.20":      "192.168.1.20:7626",
+		"desk.local:9000":   "desk.local:9000",
+		"::1":               "[::1]:7626",
+		"[fe80::1]:7626":    "[fe80::1]:7626",
+		"pomodoro.internal": "pomodoro.internal:7626",
+	}
+	for input, want := range tests {
+		if got := Address(input); got != want {
+			t.Errorf("Address(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/export"
+	"pomodoro-cli/internal/plan"
+	"pomodoro-cli/internal/ui"
+)
+
+// Formats は plan コマンドの出力形式
+var Formats = []string{"text", "ics"}
+
+// Options はplanコマンドのオプション
+type Options struct {
+	// Start は開始時刻（"13:00" など、空なら現在時刻）
+	Start string
+	// Until は終了時刻（"18:00" など、空なら Count で決める）
+	Until string
+	// Count は作業セッションの数
+	Count int
+	// Format は出力形式（text または ics）
+	Format string
+	// Output は出力先のファイル（空なら標準出力）
+	Output string
+}
+
+// Run は設定の時間でセッションの予定表を作って表示する（タイマーは動かさない）
+// task はICSの予定の件名に使う
+func Run(cfg *config.Config, task string, opts Options) error {
+	now := time.Now()
+	start, err := plan.ParseClock(opts.Start, now)
+	if err != nil {
+		return fmt.Errorf("invalid --start: %w", err)
+	}
+	var until time.Time
+	if opts.Until != "" {
+		if until, err = plan.ParseClock(opts.Until, now); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if opts.Count < 0 {
+		return fmt.Errorf("invalid --count: must not be negative")
+	}
+
+	p, err := plan.Build(cfg, plan.Options{Start: start, Until: until, Count: opts.Count})
+	if err != nil {
+		if errors.Is(err, plan.ErrNoLimit) {
+			return fmt.Errorf("specify --until or --count")
+		}
+		return err
+	}
+
+	switch opts.Format {
+	case "", "text":
+		if opts.Output != "" {
+			return fmt.Errorf("--output needs --format ics")
+		}
+		ui.ShowPlan(p)
+		return nil
+	case "ics":
+		return writeOutput(opts.Output, func(w io.Writer) error {
+			return export.WritePlanICS(w, p, task, cfg.Tags, now)
+		})
+	default:
+		return fmt.Errorf("unknown format: %q", opts.Format)
+	}
+}
+
+// writeOutput は path（空なら標準出力）に書き込む
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,135 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"pomodoro-cli/cmd/pomodoro/internal/start"
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/metrics"
+	"pomodoro-cli/internal/server"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// DefaultListen はデフォルトの待ち受けアドレス
+const DefaultListen = "127.0.0.1:7625"
+
+// shutdownTimeout は終了時に処理中のリクエストを待つ時間
+const shutdownTimeout = 5 * time.Second
+
+// Options はserveコマンドのオプション
+type Options struct {
+	// Listen は待ち受けアドレス
+	Listen string
+	// Metrics は /metrics でPrometheusのメトリクスを公開する
+	Metrics bool
+}
+
+// Run はHTTP APIサーバーを起動する
+func Run(cfg *config.Config, opts Options) error {
+	tokenPath, err := server.TokenPath()
+	if err != nil {
+		return fmt.Errorf("failed to get token path: %w", err)
+	}
+	token, err := server.LoadOrCreateToken(tokenPath)
+	if err != nil {
+		return fmt.Errorf("failed to load token: %w", err)
+	}
+
+	historyPath, err := history.Path()
+	if err != nil {
+		return fmt.Errorf("failed to get history path: %w", err)
+	}
+	store, err := history.Open(historyPath)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	for _, warning := range store.Warnings() {
+		ui.ShowWarning(warning)
+	}
+
+	t := timer.New(cfg)
+	events, unsubscribe := t.Subscribe()
+	defer unsubscribe()
+	upload, stopUpload := start.UploadToTeam(cfg)
+	defer stopUpload()
+	go advance(t, cfg, store, upload, events)
+	if cfg.TimewarriorLive {
+		detach := t.Attach(start.TrackTimewarrior)
+		defer detach()
+	}
+
+	api := server.New(t, cfg, token)
+	if opts.Metrics {
+		collector := metrics.NewCollector()
+		metricEvents, unsubscribeMetrics := t.Subscribe()
+		defer unsubscribeMetrics()
+		go collector.Run(metricEvents)
+		api.EnableMetrics(collector)
+	}
+
+	ln, err := net.Listen("tcp", opts.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	// SSEのような長時間の接続も終了時にキャンセルされるようにする
+	baseCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := &http.Server{
+		Handler:           api.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	srv.RegisterOnShutdown(cancel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelShutdown()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	ui.ShowServing(ln.Addr().String(), tokenPath)
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error: %w", err)
+	}
+	t.Stop()
+	return nil
+}
+
+// advance は終了したセッションを履歴に記録し、
+// 完了時は設定に従って次のセッションを開始する
+func advance(t *timer.Timer, cfg *config.Config, store *history.Store, upload func(history.Record), events <-chan timer.Event) {
+	for event := range events {
+		switch event.Type {
+		case timer.EventStopped:
+			recordEvent(store, upload, event, history.OutcomeSkipped)
+		case timer.EventCompleted:
+			recordEvent(store, upload, event, history.OutcomeCompleted)
+			nextType := t.State().NextSessionType(cfg.SessionsUntilLong)
+			if start.ShouldAutoStart(cfg, nextType) {
+				t.Start(nextType)
+			}
+		}
+	}
+}
+
+// recordEvent はイベントのセッションを履歴に記録してチームサーバーへ送る
+func recordEvent(store *history.Store, upload func(history.Record), event timer.Event, outcome history.Outcome) {
+	record := history.NewRecord(event.Session, outcome, event.At)
+	if err := store.Append(record); err != nil {
+		ui.ShowError("Failed to record history: " + err.Error())
+	}
+	upload(record)
+}
@@ -0,0 +1,79 @@
+package start
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/idle"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// awayState は離席を検出して一時停止したときの記録
+type awayState struct {
+	// since は最後に操作した時刻、pausedAt は一時停止した時刻
+	since, pausedAt time.Time
+}
+
+// checkIdle は離席を検出したら作業セッションを一時停止し、戻ってきたら離席中の時間の扱いを決める
+func (r *runner) checkIdle(now time.Time) {
+	if r.idle == nil {
+		return
+	}
+	// コマンドパレットの入力中は、戻ったときの確認で入力欄を置き換えないように閉じるまで待つ
+	if r.away != nil && r.paletteOpen {
+		return
+	}
+	change, err := r.idle.Poll(now)
+	if err != nil {
+		// 取得できない環境では検出をやめる（タイマーは動かし続ける）
+		ui.ShowIdleUnavailable(r.idle.Provider().Name(), err)
+		r.idle = nil
+		return
+	}
+	switch change {
+	case idle.Away:
+		state := r.t.State()
+		if r.away != nil || state.TimerState != timer.StateRunning || state.CurrentSession.Type != timer.SessionWork {
+			return
+		}
+		r.t.PauseAway()
+		r.away = &awayState{since: r.idle.Since(), pausedAt: now}
+		ui.ShowAway(now.Sub(r.away.since))
+	case idle.Back:
+		if r.away != nil {
+			r.comeBack(now)
+		}
+	}
+}
+
+// comeBack は離席から戻ったときに idle_action に従って離席中の時間を扱う
+// "ask" なら作業時間として数えるかを尋ねる
+func (r *runner) comeBack(now time.Time) {
+	away := *r.away
+	r.away = nil
+	idleFor := now.Sub(away.since)
+	counted := away.pausedAt.Sub(away.since)
+
+	switch r.cfg.IdleAction {
+	case "keep":
+		r.resolveIdle(idleFor, counted, true)
+	case "discard":
+		r.resolveIdle(idleFor, counted, false)
+	default:
+		prompt := fmt.Sprintf("Welcome back! Count the %s you were away as focus time? [y/N]: ", ui.FormatDuration(idleFor))
+		r.openPrompt(prompt, func(line string) (bool, error) {
+			answer := strings.ToLower(strings.TrimSpace(line))
+			r.resolveIdle(idleFor, counted, answer == "y" || answer == "yes")
+			return false, nil
+		})
+	}
+}
+
+// resolveIdle は離席中の時間をセッションに反映して再開する
+func (r *runner) resolveIdle(idleFor, counted time.Duration, keep bool) {
+	r.t.ResolveIdle(idleFor, counted, keep)
+	r.t.ResumeAway()
+	ui.ShowIdleResolved(idleFor, keep)
+}
@@ -0,0 +1,154 @@
+package start
+
+import (
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/idle"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Idle Detection - 離席の検出
+// =============================================================================
+
+// fakeIdle は指定したアイドル時間を返す Provider
+type fakeIdle struct {
+	idle time.Duration
+}
+
+func (f *fakeIdle) Name() string { return "fake" }
+
+func (f *fakeIdle) IdleTime(time.Time) (time.Duration, error) { return f.idle, nil }
+
+func TestRunnerは離席すると作業セッションを一時停止する(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	provider := &fakeIdle{}
+	r.idle = idle.NewDetector(provider, 5*time.Minute)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	now := time.Now()
+	r.checkIdle(now)
+	if tmr.State().TimerState != timer.StateRunning {
+		t.Fatal("操作中なのに一時停止した")
+	}
+
+	provider.idle = 6 * time.Minute
+	r.checkIdle(now.Add(time.Minute))
+	if tmr.State().TimerState != timer.StatePaused || r.away == nil {
+		t.Fatal("離席しても一時停止しない")
+	}
+	if got := tmr.State().CurrentSession.Interruptions; got != 0 {
+		t.Errorf("Interruptions = %d, want 0 (leaving is not an interruption)", got)
+	}
+}
+
+func TestRunnerはコマンドパレットの入力中は戻ったときの確認を開かない(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	provider := &fakeIdle{idle: 6 * time.Minute}
+	r.idle = idle.NewDetector(provider, 5*time.Minute)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	now := time.Now()
+	r.checkIdle(now)
+	r.openPalette()
+	r.handleKeyInput("t")
+	provider.idle = 0
+	r.checkIdle(now.Add(time.Minute))
+	if r.prompt != ":" || string(r.palette) != "t" {
+		t.Fatalf("prompt = %q, palette = %q, want the command palette kept", r.prompt, string(r.palette))
+	}
+
+	// パレットを閉じたら確認する
+	r.handleKeyInput("esc")
+	r.checkIdle(now.Add(2 * time.Minute))
+	if !r.paletteOpen || r.prompt == ":" {
+		t.Fatal("パレットを閉じても戻ったときの確認を開かない")
+	}
+	typeLine(r, "y")
+	session := tmr.State().CurrentSession
+	if tmr.State().TimerState != timer.StateRunning || session.PausedTotal != 0 || session.Interruptions != 0 {
+		t.Errorf("state = %v, PausedTotal = %v, Interruptions = %d, want running without pause or interruption",
+			tmr.State().TimerState, session.PausedTotal, session.Interruptions)
+	}
+}
+
+func TestRunnerは戻ったときに離席時間を除くか尋ねる(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		keep   bool
+	}{
+		{"除く", "", false},
+		{"数える", "y", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			tmr := timer.New(cfg)
+			r := newTestRunner(t, tmr, cfg)
+			tmr.Start(timer.SessionWork)
+			defer tmr.Stop()
+
+			// 10 分進んだところで離席したことにする
+			tmr.ResolveIdle(10*time.Minute, 0, true)
+			now := time.Now()
+			tmr.Pause()
+			r.away = &awayState{since: now.Add(-6 * time.Minute), pausedAt: now.Add(-time.Minute)}
+			before := tmr.State().CurrentSession.Remaining
+
+			r.handleKeyInput("x")
+			if !r.paletteOpen {
+				t.Fatal("戻ったときに尋ねない")
+			}
+			typeLine(r, tt.answer)
+
+			state := tmr.State()
+			if state.TimerState != timer.StateRunning {
+				t.Error("答えたあとに再開しない")
+			}
+			session := state.CurrentSession
+			if tt.keep {
+				if session.IdleKept < 16*time.Minute || session.Remaining >= before {
+					t.Errorf("IdleKept = %v, Remaining = %v (before %v)", session.IdleKept, session.Remaining, before)
+				}
+			} else {
+				// 一時停止までに数えた 5 分を戻す
+				if session.IdleDiscarded < 6*time.Minute || session.Remaining < before+5*time.Minute-time.Second {
+					t.Errorf("IdleDiscarded = %v, Remaining = %v (before %v)", session.IdleDiscarded, session.Remaining, before)
+				}
+			}
+		})
+	}
+}
+
+func TestRunnerはidle_actionが決まっていれば尋ねない(t *testing.T) {
+	cfg := config.Default()
+	cfg.IdleAction = "discard"
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	provider := &fakeIdle{idle: 6 * time.Minute}
+	r.idle = idle.NewDetector(provider, 5*time.Minute)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	now := time.Now()
+	r.checkIdle(now)
+	provider.idle = 0
+	r.checkIdle(now.Add(time.Minute))
+
+	state := tmr.State()
+	if r.paletteOpen || r.away != nil || state.TimerState != timer.StateRunning {
+		t.Fatalf("paletteOpen = %v, away = %v, state = %v", r.paletteOpen, r.away, state.TimerState)
+	}
+	if state.CurrentSession.IdleDiscarded == 0 {
+		t.Error("離席時間を除いた記録がない")
+	}
+}
@@ -0,0 +1,50 @@
+package start
+
+import (
+	"pomodoro-cli/internal/activity"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// SuggestBreak は次の休憩の過ごし方を選ぶ（休憩でないか提案がなければ空）
+func SuggestBreak(picker *activity.Picker, next timer.SessionType) string {
+	if next == timer.SessionWork {
+		return ""
+	}
+	breakType, err := next.MarshalText()
+	if err != nil {
+		return ""
+	}
+	a, ok := picker.Pick(string(breakType))
+	if !ok {
+		return ""
+	}
+	return a.Text
+}
+
+// newGuide は設定の guided_break に対応するガイドを返す（表示しなければ nil）
+func newGuide(name string) *activity.Guide {
+	g, ok := activity.FindGuide(name)
+	if !ok {
+		return nil
+	}
+	return &g
+}
+
+// renderGuide は短い休憩の最初にタイマーの行の代わりにガイドを表示する（表示した場合 true を返す）
+// 一時停止中はタイマーの行に戻し、ガイドが終わったら一度だけ知らせる
+func (r *runner) renderGuide(state *timer.PomodoroState) bool {
+	s := state.CurrentSession
+	if r.guide == nil || s == nil || s.Type != timer.SessionShortBreak ||
+		state.TimerState != timer.StateRunning || r.guided.Equal(s.StartedAt) {
+		return false
+	}
+	step, round, left, ok := r.guide.At(s.Duration - s.Remaining)
+	if !ok {
+		ui.ShowGuideDone(*r.guide)
+		r.guided = s.StartedAt
+		return false
+	}
+	ui.RenderGuide(*r.guide, step, round, left)
+	return true
+}
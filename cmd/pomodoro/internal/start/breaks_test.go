@@ -0,0 +1,98 @@
+package start
+
+import (
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/activity"
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// 休憩の過ごし方
+// =============================================================================
+
+func TestSuggestBreakは次の休憩の種類の一覧から選ぶ(t *testing.T) {
+	picker := activity.NewPicker(map[string][]string{
+		"short_break": {"Drink water"},
+		"long_break":  {"Take a walk"},
+	})
+
+	if got := SuggestBreak(picker, timer.SessionShortBreak); got != "Drink water" {
+		t.Errorf("short break = %q, want Drink water", got)
+	}
+	if got := SuggestBreak(picker, timer.SessionLongBreak); got != "Take a walk" {
+		t.Errorf("long break = %q, want Take a walk", got)
+	}
+	if got := SuggestBreak(picker, timer.SessionWork); got != "" {
+		t.Errorf("work = %q, want no suggestion", got)
+	}
+}
+
+func Test休憩の一覧が空なら提案しない(t *testing.T) {
+	picker := activity.NewPicker(map[string][]string{"short_break": {}})
+	if got := SuggestBreak(picker, timer.SessionShortBreak); got != "" {
+		t.Errorf("SuggestBreak() = %q, want no suggestion", got)
+	}
+}
+
+// =============================================================================
+// ガイド
+// =============================================================================
+
+func shortBreakState(startedAt time.Time, elapsed time.Duration) *timer.PomodoroState {
+	return &timer.PomodoroState{
+		TimerState: timer.StateRunning,
+		CurrentSession: &timer.Session{
+			Type:      timer.SessionShortBreak,
+			Duration:  5 * time.Minute,
+			Remaining: 5*time.Minute - elapsed,
+			StartedAt: startedAt,
+		},
+	}
+}
+
+func Test短い休憩の最初にガイドを表示して終わったらタイマーに戻る(t *testing.T) {
+	cfg := config.Default()
+	cfg.GuidedBreak = "4-7-8"
+	r := newTestRunner(t, timer.New(cfg), cfg)
+	startedAt := time.Now()
+
+	if !r.renderGuide(shortBreakState(startedAt, 10*time.Second)) {
+		t.Error("the guide should be shown at the start of a short break")
+	}
+	paused := shortBreakState(startedAt, 10*time.Second)
+	paused.TimerState = timer.StatePaused
+	if r.renderGuide(paused) {
+		t.Error("the timer should be shown while paused")
+	}
+	if r.renderGuide(shortBreakState(startedAt, 2*time.Minute)) {
+		t.Error("the guide should end after its rounds")
+	}
+	if !r.guided.Equal(startedAt) {
+		t.Errorf("guided = %v, want %v", r.guided, startedAt)
+	}
+
+	// 次の短い休憩ではまた表示する
+	if !r.renderGuide(shortBreakState(startedAt.Add(time.Hour), 0)) {
+		t.Error("the guide should be shown again in the next short break")
+	}
+}
+
+func Testガイドは長い休憩や設定がなければ表示しない(t *testing.T) {
+	cfg := config.Default()
+	cfg.GuidedBreak = "box"
+	r := newTestRunner(t, timer.New(cfg), cfg)
+	long := shortBreakState(time.Now(), 0)
+	long.CurrentSession.Type = timer.SessionLongBreak
+	if r.renderGuide(long) {
+		t.Error("the guide should only be shown in short breaks")
+	}
+
+	off := config.Default()
+	off.GuidedBreak = "off"
+	if newTestRunner(t, timer.New(off), off).renderGuide(shortBreakState(time.Now(), 0)) {
+		t.Error("the guide should not be shown when guided_break is off")
+	}
+}
@@ -0,0 +1,90 @@
+package start
+
+import (
+	"context"
+	"time"
+
+	"pomodoro-cli/internal/chatstatus"
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// publishTimeout は1回のステータス更新（再試行を含む）にかける時間の上限
+const publishTimeout = 30 * time.Second
+
+// startChatStatus は作業セッションの間だけチャットのステータスを表示する（不要なら何もしない）
+// 返された関数は購読を解除してステータスを消す
+func startChatStatus(t *timer.Timer, cfg *config.Config) (stop func()) {
+	if cfg.ChatStatus == "" || cfg.ChatStatus == "off" {
+		return func() {}
+	}
+	publisher, err := chatstatus.New(cfg.ChatStatus, cfg.ChatStatusURL, cfg.ChatStatusToken)
+	if err != nil {
+		ui.ShowError("Chat status unavailable: " + err.Error())
+		return func() {}
+	}
+	return attachChatStatus(t, publisher)
+}
+
+// attachChatStatus はタイマーイベントに合わせてステータスを更新する
+func attachChatStatus(t *timer.Timer, publisher chatstatus.Publisher) (stop func()) {
+	s := &statusPublisher{publisher: publisher}
+	detach := t.Attach(func(events <-chan timer.Event) {
+		for event := range events {
+			if err := s.handle(event); err != nil {
+				ui.ShowError("Chat status failed: " + err.Error())
+			}
+		}
+	})
+	return func() {
+		detach()
+		if err := s.clear(); err != nil {
+			ui.ShowError("Chat status failed: " + err.Error())
+		}
+	}
+}
+
+// statusPublisher は作業中のステータスを表示しているかを覚えておく
+type statusPublisher struct {
+	publisher chatstatus.Publisher
+	focusing  bool
+}
+
+// handle は作業セッションの開始・再開で終了予定時刻を表示し、一時停止・停止・完了で消す
+// 実行中に延長したときは終了予定時刻を表示し直す
+func (s *statusPublisher) handle(event timer.Event) error {
+	if event.Session.Type != timer.SessionWork {
+		return nil
+	}
+	if event.Type == timer.EventExtended && event.TimerState != timer.StateRunning {
+		return nil
+	}
+	switch event.Type {
+	case timer.EventStarted, timer.EventResumed, timer.EventExtended:
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		until := event.At.Add(event.Session.Remaining)
+		if err := s.publisher.Set(ctx, chatstatus.Focusing(until)); err != nil {
+			return err
+		}
+		s.focusing = true
+	case timer.EventPaused, timer.EventStopped, timer.EventCompleted:
+		return s.clear()
+	}
+	return nil
+}
+
+// clear は表示中のステータスを消す
+func (s *statusPublisher) clear() error {
+	if !s.focusing {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := s.publisher.Clear(ctx); err != nil {
+		return err
+	}
+	s.focusing = false
+	return nil
+}
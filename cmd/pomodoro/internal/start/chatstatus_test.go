@@ -0,0 +1,96 @@
+package start
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/chatstatus"
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Chat Status - チャットのステータス
+// =============================================================================
+
+// fakePublisher は設定したステータスを記録する Publisher
+type fakePublisher struct {
+	calls []string
+}
+
+func (f *fakePublisher) Name() string { return "fake" }
+
+func (f *fakePublisher) Set(_ context.Context, status chatstatus.Status) error {
+	f.calls = append(f.calls, "set "+status.Text)
+	return nil
+}
+
+func (f *fakePublisher) Clear(context.Context) error {
+	f.calls = append(f.calls, "clear")
+	return nil
+}
+
+func TestStatusPublisherは作業中だけステータスを表示する(t *testing.T) {
+	publisher := &fakePublisher{}
+	s := &statusPublisher{publisher: publisher}
+	at := time.Date(2024, 1, 15, 14, 0, 0, 0, time.Local)
+	work := timer.Session{Type: timer.SessionWork, Remaining: 25 * time.Minute}
+
+	for _, event := range []timer.Event{
+		{Type: timer.EventStarted, Session: work, At: at},
+		{Type: timer.EventCompleted, Session: work, At: at.Add(25 * time.Minute)},
+		{Type: timer.EventStarted, Session: timer.Session{Type: timer.SessionShortBreak}},
+		{Type: timer.EventCompleted, Session: timer.Session{Type: timer.SessionShortBreak}},
+		{Type: timer.EventStarted, Session: work, At: at.Add(30 * time.Minute)},
+	} {
+		if err := s.handle(event); err != nil {
+			t.Fatalf("handle(%v) error = %v", event.Type, err)
+		}
+	}
+
+	expected := []string{"set focusing until 14:25", "clear", "set focusing until 14:55"}
+	if !reflect.DeepEqual(publisher.calls, expected) {
+		t.Errorf("calls = %q, want %q", publisher.calls, expected)
+	}
+}
+
+func TestStatusPublisherは実行中に延長すると終了予定時刻を表示し直す(t *testing.T) {
+	publisher := &fakePublisher{}
+	s := &statusPublisher{publisher: publisher}
+	at := time.Date(2024, 1, 15, 14, 0, 0, 0, time.Local)
+	work := timer.Session{Type: timer.SessionWork, Remaining: 25 * time.Minute}
+	extended := timer.Session{Type: timer.SessionWork, Remaining: 30 * time.Minute}
+
+	for _, event := range []timer.Event{
+		{Type: timer.EventStarted, Session: work, At: at},
+		{Type: timer.EventExtended, Session: extended, At: at, TimerState: timer.StateRunning},
+		{Type: timer.EventPaused, Session: extended, At: at},
+		{Type: timer.EventExtended, Session: extended, At: at, TimerState: timer.StatePaused},
+	} {
+		if err := s.handle(event); err != nil {
+			t.Fatalf("handle(%v) error = %v", event.Type, err)
+		}
+	}
+
+	expected := []string{"set focusing until 14:25", "set focusing until 14:30", "clear"}
+	if !reflect.DeepEqual(publisher.calls, expected) {
+		t.Errorf("calls = %q, want %q", publisher.calls, expected)
+	}
+}
+
+func TestAttachChatStatusは終了時にステータスを消す(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	publisher := &fakePublisher{}
+	stop := attachChatStatus(tmr, publisher)
+
+	tmr.Start(timer.SessionWork)
+	stop()
+	tmr.Stop()
+
+	if len(publisher.calls) != 2 || publisher.calls[1] != "clear" {
+		t.Errorf("calls = %q, want set then clear", publisher.calls)
+	}
+}
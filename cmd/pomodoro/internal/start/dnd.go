@@ -0,0 +1,74 @@
+package start
+
+import (
+	"path/filepath"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/dnd"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// recoverDND は前回の実行がクラッシュして止めたままにした通知を元に戻す
+func recoverDND(statePath string) {
+	restored, err := dnd.Recover(statePath, dnd.New)
+	if err != nil {
+		ui.ShowError("Failed to restore notifications: " + err.Error())
+		return
+	}
+	if restored {
+		ui.ShowDNDRestored()
+	}
+}
+
+// startDND は作業セッションの間だけ通知を止める（不要なら何もしない）
+// 返された関数は購読を解除して通知を元の状態に戻す
+func startDND(t *timer.Timer, cfg *config.Config) (stop func()) {
+	dir, err := config.Dir()
+	if err != nil {
+		ui.ShowError("Do not disturb unavailable: " + err.Error())
+		return func() {}
+	}
+	statePath := filepath.Join(dir, dnd.StateFile)
+	recoverDND(statePath)
+	if cfg.DND == "" || cfg.DND == "off" {
+		return func() {}
+	}
+	provider, err := dnd.New(cfg.DND)
+	if err != nil {
+		ui.ShowError("Do not disturb unavailable: " + err.Error())
+		return func() {}
+	}
+	return attachDND(t, dnd.NewController(provider, statePath))
+}
+
+// attachDND は作業セッションの開始で通知を止め、停止・完了で元に戻す
+func attachDND(t *timer.Timer, controller *dnd.Controller) (stop func()) {
+	detach := t.Attach(func(events <-chan timer.Event) {
+		for event := range events {
+			if err := handleDNDEvent(controller, event); err != nil {
+				ui.ShowError("Do not disturb failed: " + err.Error())
+			}
+		}
+	})
+	return func() {
+		detach()
+		if err := controller.End(); err != nil {
+			ui.ShowError("Failed to restore notifications: " + err.Error())
+		}
+	}
+}
+
+// handleDNDEvent は1件のイベントに合わせて通知を止めるか元に戻す
+func handleDNDEvent(controller *dnd.Controller, event timer.Event) error {
+	if event.Session.Type != timer.SessionWork {
+		return nil
+	}
+	switch event.Type {
+	case timer.EventStarted:
+		return controller.Begin()
+	case timer.EventStopped, timer.EventCompleted:
+		return controller.End()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package start
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/dnd"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Do Not Disturb - 通知の抑制
+// =============================================================================
+
+// fakeDND はメモリ上でおやすみモードを切り替える Provider
+type fakeDND struct {
+	enabled bool
+}
+
+func (f *fakeDND) Name() string { return "fake" }
+
+func (f *fakeDND) Enabled() (bool, error) { return f.enabled, nil }
+
+func (f *fakeDND) Set(enabled bool) error {
+	f.enabled = enabled
+	return nil
+}
+
+func TestHandleDNDEventは作業セッションの間だけ通知を止める(t *testing.T) {
+	provider := &fakeDND{}
+	c := dnd.NewController(provider, filepath.Join(t.TempDir(), dnd.StateFile))
+
+	steps := []struct {
+		event timer.Event
+		want  bool
+	}{
+		{timer.Event{Type: timer.EventStarted, Session: timer.Session{Type: timer.SessionWork}}, true},
+		{timer.Event{Type: timer.EventPaused, Session: timer.Session{Type: timer.SessionWork}}, true},
+		{timer.Event{Type: timer.EventCompleted, Session: timer.Session{Type: timer.SessionWork}}, false},
+		{timer.Event{Type: timer.EventStarted, Session: timer.Session{Type: timer.SessionShortBreak}}, false},
+		{timer.Event{Type: timer.EventStarted, Session: timer.Session{Type: timer.SessionWork}}, true},
+		{timer.Event{Type: timer.EventStopped, Session: timer.Session{Type: timer.SessionWork}}, false},
+	}
+	for i, step := range steps {
+		if err := handleDNDEvent(c, step.event); err != nil {
+			t.Fatalf("step %d: error = %v", i, err)
+		}
+		if provider.enabled != step.want {
+			t.Errorf("step %d (%v %v): enabled = %v, want %v", i, step.event.Type, step.event.Session.Type, provider.enabled, step.want)
+		}
+	}
+}
+
+func TestAttachDNDは終了時に通知を元に戻す(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	provider := &fakeDND{}
+	stop := attachDND(tmr, dnd.NewController(provider, filepath.Join(t.TempDir(), dnd.StateFile)))
+
+	tmr.Start(timer.SessionWork)
+	stop()
+	tmr.Stop()
+	if provider.enabled {
+		t.Error("終了しても通知が止まったまま")
+	}
+}
@@ -0,0 +1,56 @@
+package start
+
+import (
+	"fmt"
+	"strings"
+
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/team"
+	"pomodoro-cli/internal/ui"
+)
+
+// hostNotices は参加者の知らせを受け取るチャネルを返す（ホストでなければ nil）
+func (r *runner) hostNotices() <-chan team.Notice {
+	if r.host == nil {
+		return nil
+	}
+	return r.host.Notices()
+}
+
+// handleNotice は参加者の接続・切断を表示し、提案を承認するか尋ねる
+func (r *runner) handleNotice(n team.Notice) {
+	switch n.Kind {
+	case team.Joined:
+		ui.ShowMemberJoined(n.Name, r.host.Members())
+	case team.Left:
+		ui.ShowMemberLeft(n.Name, r.host.Members())
+	case team.Proposed:
+		r.proposals = append(r.proposals, n)
+		r.askProposal()
+	}
+}
+
+// askProposal は入力欄が空いていれば次の提案を承認するか尋ねる
+func (r *runner) askProposal() {
+	if r.paletteOpen || len(r.proposals) == 0 {
+		return
+	}
+	n := r.proposals[0]
+	r.proposals = r.proposals[1:]
+	prompt := fmt.Sprintf("%s proposes to %s. Approve? [y/N]: ", n.Name, n.Action)
+	r.openPrompt(prompt, func(line string) (bool, error) {
+		answer := strings.ToLower(strings.TrimSpace(line))
+		approved := answer == "y" || answer == "yes"
+		r.host.Decide(n, approved)
+		ui.ShowProposalDecided(n.Name, n.Action, approved)
+		if approved {
+			// ホストが承認したアクションは strict モードの確認を求めない
+			action := keys.ActionPause
+			if n.Action == team.ActionSkip {
+				action = keys.ActionSkip
+			}
+			return r.doAction(action), nil
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,90 @@
+package start
+
+import (
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/team"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Team Host - チームのホスト
+// =============================================================================
+
+func newTestHost(t *testing.T, r *runner) {
+	t.Helper()
+	host, err := team.Listen("127.0.0.1:0", r.t.State)
+	if err != nil {
+		t.Fatalf("team.Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = host.Close() })
+	r.host = host
+}
+
+func Test参加者の提案を承認するとアクションを実行する(t *testing.T) {
+	cfg := config.Default()
+	cfg.Strict = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	newTestHost(t, r)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleNotice(team.Notice{Kind: team.Proposed, Name: "alice", Action: team.ActionSkip})
+	if !r.paletteOpen {
+		t.Fatal("提案を承認するか尋ねない")
+	}
+	// strict モードでもホストが承認したアクションは確認を求めない
+	typeLine(r, "y")
+	if tmr.State().CurrentSession.Type != timer.SessionShortBreak {
+		t.Error("承認してもスキップしない")
+	}
+}
+
+func Test参加者の提案を却下すると何もしない(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	newTestHost(t, r)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleNotice(team.Notice{Kind: team.Proposed, Name: "alice", Action: team.ActionPause})
+	r.handleNotice(team.Notice{Kind: team.Proposed, Name: "bob", Action: team.ActionSkip})
+	typeLine(r, "n")
+	if tmr.State().TimerState != timer.StateRunning {
+		t.Error("却下したのに一時停止した")
+	}
+
+	// 尋ねている間に届いた提案は次に尋ねる
+	r.askProposal()
+	if !r.paletteOpen || len(r.proposals) != 0 {
+		t.Fatalf("paletteOpen = %v, proposals = %v", r.paletteOpen, r.proposals)
+	}
+	typeLine(r, "")
+	if tmr.State().CurrentSession.Type != timer.SessionWork {
+		t.Error("空の答えで承認した")
+	}
+}
+
+func Test参加者にホストの状態が届く(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	newTestHost(t, r)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	client := team.Join(r.host.Addr().String(), "carol")
+	defer client.Close()
+	select {
+	case msg := <-client.Messages():
+		if msg.State == nil || msg.State.Session != timer.SessionWork {
+			t.Errorf("message = %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("参加者に状態が届かない")
+	}
+}
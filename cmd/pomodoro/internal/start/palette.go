@@ -0,0 +1,107 @@
+package start
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/ui"
+)
+
+// minExtend はコマンドパレットの extend で延長できる最小の時間
+const minExtend = time.Minute
+
+// openPalette はコマンドパレットを開く
+func (r *runner) openPalette() {
+	r.openPrompt(":", r.runCommand)
+}
+
+// openPrompt はタイマーの行に入力欄を開く
+// Enter で入力した文字列を submit に渡し、Esc で何もせずに閉じる
+func (r *runner) openPrompt(prompt string, submit func(line string) (bool, error)) {
+	r.paletteOpen = true
+	r.palette = r.palette[:0]
+	r.prompt = prompt
+	r.submit = submit
+	ui.RenderPalette(r.prompt, "")
+}
+
+// handlePaletteKey は入力欄を開いている間のキー入力を処理する（終了時 true を返す）
+func (r *runner) handlePaletteKey(key keys.Key) bool {
+	switch key {
+	case "esc", "ctrl+c":
+		r.paletteOpen = false
+		ui.ClosePalette()
+		return false
+	case "enter":
+		line := string(r.palette)
+		r.paletteOpen = false
+		ui.ClosePalette()
+		exit, err := r.submit(line)
+		if err != nil {
+			ui.ShowCommandError(err)
+		}
+		return exit
+	case "backspace":
+		if len(r.palette) > 0 {
+			r.palette = r.palette[:len(r.palette)-1]
+		}
+	default:
+		if ch, ok := key.Rune(); ok {
+			r.palette = append(r.palette, ch)
+		}
+	}
+	ui.RenderPalette(r.prompt, string(r.palette))
+	return false
+}
+
+// runCommand はコマンドパレットに入力されたコマンドを実行する（終了時 true を返す）
+// "task NAME" と "extend DURATION" のほかに、アクションの名前（skip、goal など）を受け付ける
+func (r *runner) runCommand(line string) (bool, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "":
+		return false, nil
+	case "task":
+		r.t.SetTask(arg)
+		ui.ShowTaskSet(arg)
+		return false, nil
+	case "extend":
+		d, err := parseExtend(arg)
+		if err != nil {
+			return false, err
+		}
+		if !r.t.Extend(d) {
+			return false, errors.New("extend: no session is running")
+		}
+		ui.ShowExtended(d, r.t.State().CurrentSession.Remaining)
+		return false, nil
+	}
+
+	action, err := keys.ParseAction(name)
+	if err != nil || action == keys.ActionNone || action == keys.ActionCommand {
+		return false, fmt.Errorf("unknown command %q (use task, extend, pause, skip, reset, goal, help or quit)", name)
+	}
+	if arg != "" {
+		return false, fmt.Errorf("%s: unexpected argument %q", name, arg)
+	}
+	return r.handleAction(action), nil
+}
+
+// parseExtend は extend の引数の時間を解釈する
+func parseExtend(arg string) (time.Duration, error) {
+	if arg == "" {
+		return 0, errors.New("extend: specify a duration such as 5m")
+	}
+	d, err := time.ParseDuration(arg)
+	if err != nil {
+		return 0, fmt.Errorf("extend: invalid duration %q (use a value like 5m)", arg)
+	}
+	if d < minExtend {
+		return 0, fmt.Errorf("extend: duration must be at least %s", ui.FormatDuration(minExtend))
+	}
+	return d, nil
+}
@@ -0,0 +1,126 @@
+package start
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Command Palette - コマンドパレット
+// =============================================================================
+
+func Testコロンキーで開いたパレットに入力したコマンドを実行する(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	typeCommand(r, "task write docs")
+
+	if r.paletteOpen {
+		t.Error("Enter後もパレットが開いている")
+	}
+	if got := tmr.State().CurrentSession.Task; got != "write docs" {
+		t.Errorf("Task = %q, want %q", got, "write docs")
+	}
+}
+
+func Testパレットを開いている間はキーをアクションとして扱わない(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleKeyInput(":")
+	for _, k := range []keys.Key{"s", "q", "space", "x", "backspace"} {
+		if r.handleKeyInput(k) {
+			t.Fatalf("handleKeyInput(%q) = true, want false", k)
+		}
+	}
+	if got := string(r.palette); got != "sq " {
+		t.Errorf("palette = %q, want %q", got, "sq ")
+	}
+	if tmr.State().TimerState != timer.StateRunning || tmr.State().CurrentSession.Type != timer.SessionWork {
+		t.Error("パレットへの入力でタイマーが操作された")
+	}
+
+	// Esc で何も実行せずに閉じる
+	r.handleKeyInput("esc")
+	if r.paletteOpen {
+		t.Error("Esc後もパレットが開いている")
+	}
+}
+
+func TestExtendコマンドでWorkセッションを延長する(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	if _, err := r.runCommand("extend 5m"); err != nil {
+		t.Fatalf("runCommand() error = %v", err)
+	}
+	if got := tmr.State().CurrentSession.Duration; got != 30*time.Minute {
+		t.Errorf("Duration = %v, want 30m", got)
+	}
+}
+
+func Testパレットのアクション名はキーと同じ操作をする(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	if _, err := r.runCommand("skip"); err != nil {
+		t.Fatalf("runCommand(skip) error = %v", err)
+	}
+	if got := tmr.State().CurrentSession.Type; got != timer.SessionShortBreak {
+		t.Errorf("スキップ後のセッション = %v, want SessionShortBreak", got)
+	}
+	if exit, err := r.runCommand("quit"); err != nil || !exit {
+		t.Errorf("runCommand(quit) = %v, %v, want true, nil", exit, err)
+	}
+}
+
+func Testパレットは不正なコマンドをエラーにする(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	tests := []struct {
+		line, want string
+	}{
+		{"dance", "unknown command"},
+		{"command", "unknown command"},
+		{"extend", "specify a duration"},
+		{"extend soon", "invalid duration"},
+		{"extend 10s", "at least 1m"},
+		{"skip now", "unexpected argument"},
+	}
+	for _, tt := range tests {
+		_, err := r.runCommand(tt.line)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("runCommand(%q) error = %v, want %q", tt.line, err, tt.want)
+		}
+	}
+	if tmr.State().CurrentSession.Type != timer.SessionWork {
+		t.Error("不正なコマンドでセッションが変わった")
+	}
+}
+
+// typeCommand はコマンドパレットを開いて line を入力し、Enter を押す
+func typeCommand(r *runner, line string) {
+	r.handleKeyInput(":")
+	typeLine(r, line)
+}
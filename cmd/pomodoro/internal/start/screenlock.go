@@ -0,0 +1,87 @@
+package start
+
+import (
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/lock"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// watchScreenLock は画面ロックの監視を始める（不要なら何もしない）
+// 返された関数で監視をやめる。logind に接続できなければ警告を表示して続ける
+func (r *runner) watchScreenLock(connect func() (lock.Watcher, error)) (stop func()) {
+	enabled := r.cfg.ScreenLock != "" && r.cfg.ScreenLock != "off"
+	if !enabled && !r.cfg.LockOnLongBreak {
+		return func() {}
+	}
+	watcher, err := connect()
+	if err != nil {
+		ui.ShowError("Screen lock awareness unavailable: " + err.Error())
+		return func() {}
+	}
+	r.lock = watcher
+	detach := func() {}
+	if r.cfg.LockOnLongBreak {
+		detach = r.t.Attach(r.lockOnLongBreak)
+	}
+	return func() {
+		detach()
+		if err := watcher.Close(); err != nil {
+			ui.ShowError("Failed to stop watching the screen lock: " + err.Error())
+		}
+	}
+}
+
+// lockOnLongBreak は長い休憩が始まったら画面をロックする
+func (r *runner) lockOnLongBreak(events <-chan timer.Event) {
+	for event := range events {
+		if event.Type != timer.EventStarted || event.Session.Type != timer.SessionLongBreak {
+			continue
+		}
+		if err := r.lock.Lock(); err != nil {
+			ui.ShowError("Failed to lock the screen: " + err.Error())
+		}
+	}
+}
+
+// lockEvents は画面ロックの変化を受け取るチャネルを返す（監視していなければ nil）
+func (r *runner) lockEvents() <-chan lock.Event {
+	if r.lock == nil {
+		return nil
+	}
+	return r.lock.Events()
+}
+
+// handleLock は作業中に画面がロックされたら screen_lock に従って一時停止または中断し、
+// ロックが解除されたら再開する
+func (r *runner) handleLock(event lock.Event) {
+	switch event {
+	case lock.Locked:
+		state := r.t.State()
+		if r.lockPaused || state.TimerState != timer.StateRunning || state.CurrentSession.Type != timer.SessionWork {
+			return
+		}
+		switch r.cfg.ScreenLock {
+		case "pause":
+			r.t.Pause()
+			ui.ShowScreenLocked(false)
+		case "interrupt":
+			// 中断したポモドーロは数えず、ロック解除後に最初からやり直す
+			r.recordStopped(history.OutcomeInterrupted)
+			r.t.Stop()
+			r.t.Start(timer.SessionWork)
+			r.t.Pause()
+			ui.ShowScreenLocked(true)
+		default:
+			return
+		}
+		r.lockPaused = true
+	case lock.Unlocked:
+		if !r.lockPaused {
+			return
+		}
+		r.lockPaused = false
+		r.t.Resume()
+		ui.ShowResumed()
+	}
+}
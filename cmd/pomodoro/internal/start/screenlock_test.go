@@ -0,0 +1,146 @@
+package start
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/lock"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Screen Lock - 画面ロック
+// =============================================================================
+
+// fakeWatcher は画面ロックの変化をテストから送る Watcher
+type fakeWatcher struct {
+	events chan lock.Event
+	locks  chan struct{}
+	closed bool
+}
+
+func newFakeWatcher() *fakeWatcher {
+	return &fakeWatcher{events: make(chan lock.Event, 1), locks: make(chan struct{}, 1)}
+}
+
+func (f *fakeWatcher) Events() <-chan lock.Event { return f.events }
+
+func (f *fakeWatcher) Lock() error {
+	f.locks <- struct{}{}
+	return nil
+}
+
+func (f *fakeWatcher) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestHandleLockは画面ロック中の作業セッションを一時停止する(t *testing.T) {
+	cfg := config.Default()
+	cfg.ScreenLock = "pause"
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleLock(lock.Locked)
+	if tmr.State().TimerState != timer.StatePaused {
+		t.Fatal("画面をロックしても一時停止しない")
+	}
+	r.handleLock(lock.Unlocked)
+	if tmr.State().TimerState != timer.StateRunning {
+		t.Error("ロックを解除しても再開しない")
+	}
+	if len(r.store.Records()) != 0 {
+		t.Errorf("records = %+v, want none", r.store.Records())
+	}
+}
+
+func TestHandleLockはinterruptなら中断として記録してやり直す(t *testing.T) {
+	cfg := config.Default()
+	cfg.ScreenLock = "interrupt"
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleLock(lock.Locked)
+	records := r.store.Records()
+	if len(records) != 1 || records[0].Outcome != history.OutcomeInterrupted {
+		t.Fatalf("records = %+v, want one interrupted record", records)
+	}
+	state := tmr.State()
+	if state.TimerState != timer.StatePaused || state.CurrentSession.Remaining != cfg.WorkDuration {
+		t.Errorf("state = %v, remaining = %v, want a fresh paused work session", state.TimerState, state.CurrentSession.Remaining)
+	}
+
+	r.handleLock(lock.Unlocked)
+	if tmr.State().TimerState != timer.StateRunning {
+		t.Error("ロックを解除しても再開しない")
+	}
+}
+
+func TestHandleLockは休憩中と手動の一時停止中は何もしない(t *testing.T) {
+	cfg := config.Default()
+	cfg.ScreenLock = "pause"
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionShortBreak)
+	defer tmr.Stop()
+
+	r.handleLock(lock.Locked)
+	if tmr.State().TimerState != timer.StateRunning {
+		t.Error("休憩中に一時停止した")
+	}
+
+	tmr.Start(timer.SessionWork)
+	tmr.Pause()
+	r.handleLock(lock.Locked)
+	r.handleLock(lock.Unlocked)
+	if tmr.State().TimerState != timer.StatePaused {
+		t.Error("手動で一時停止したセッションをロック解除で再開した")
+	}
+}
+
+func TestWatchScreenLockは長い休憩の開始時に画面をロックする(t *testing.T) {
+	cfg := config.Default()
+	cfg.LockOnLongBreak = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	watcher := newFakeWatcher()
+	stop := r.watchScreenLock(func() (lock.Watcher, error) { return watcher, nil })
+	defer tmr.Stop()
+
+	tmr.Start(timer.SessionWork)
+	tmr.Start(timer.SessionLongBreak)
+	select {
+	case <-watcher.locks:
+	case <-time.After(time.Second):
+		t.Fatal("長い休憩の開始時に画面をロックしない")
+	}
+	stop()
+	if !watcher.closed {
+		t.Error("監視を止めても Close しない")
+	}
+	select {
+	case <-watcher.locks:
+		t.Error("作業セッションの開始時にも画面をロックした")
+	default:
+	}
+}
+
+func TestWatchScreenLockは接続できなくても続ける(t *testing.T) {
+	cfg := config.Default()
+	cfg.ScreenLock = "pause"
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+
+	stop := r.watchScreenLock(func() (lock.Watcher, error) { return nil, errors.New("no system bus") })
+	stop()
+	if r.lock != nil || r.lockEvents() != nil {
+		t.Error("接続できないのに監視している")
+	}
+}
@@ -7,13 +7,98 @@ import (
 	"syscall"
 	"time"
 
+	"pomodoro-cli/internal/activity"
 	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/goal"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/idle"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/lock"
+	"pomodoro-cli/internal/team"
 	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/timewarrior"
 	"pomodoro-cli/internal/ui"
 )
 
+// runner は start コマンド実行中の状態を保持する
+type runner struct {
+	t         *timer.Timer
+	cfg       *config.Config
+	store     *history.Store
+	bindings  keys.Bindings
+	progress  goal.Progress
+	prevState timer.TimerState
+
+	// paletteOpen はコマンドパレットなどの入力欄に入力中かどうか
+	// palette は入力中の文字列、submit は Enter で呼ぶ処理
+	paletteOpen bool
+	palette     []rune
+	prompt      string
+	submit      func(line string) (bool, error)
+
+	// idle は離席の検出（無効なら nil）、terminal はキー入力の時刻、away は離席中の記録
+	idle     *idle.Detector
+	terminal *idle.Terminal
+	away     *awayState
+
+	// lock は画面ロックの監視（無効なら nil）、lockPaused は画面のロックで止めている間 true
+	lock       lock.Watcher
+	lockPaused bool
+
+	// host はチームのホストとして参加者に配信する（ホストでなければ nil）
+	// proposals は承認するか尋ねていない参加者の提案
+	host      *team.Host
+	proposals []team.Notice
+
+	// upload は記録をチームサーバーへ送る（設定がなければ何もしない）
+	upload func(history.Record)
+
+	// activities は休憩の過ごし方を選ぶ
+	// guide は短い休憩に表示するガイド（表示しなければ nil）、guided はガイドを終えた休憩の開始時刻
+	activities *activity.Picker
+	guide      *activity.Guide
+	guided     time.Time
+}
+
+// Options は start コマンドのオプション
+type Options struct {
+	// Task はセッションに記録するタスク名
+	Task string
+	// Listen が空でなければこのアドレスでチームのホストになる
+	Listen string
+}
+
+// newRunner は新しいrunnerを作成する
+func newRunner(t *timer.Timer, cfg *config.Config, store *history.Store, bindings keys.Bindings) *runner {
+	r := &runner{
+		t: t, cfg: cfg, store: store, bindings: bindings,
+		terminal:   idle.NewTerminal(time.Now()),
+		upload:     func(history.Record) {},
+		activities: activity.NewPicker(cfg.BreakActivities),
+		guide:      newGuide(cfg.GuidedBreak),
+	}
+	r.refreshProgress()
+	return r
+}
+
 // Run はタイマーを実行する
-func Run(cfg *config.Config) error {
+func Run(cfg *config.Config, opts Options) error {
+	historyPath, err := history.Path()
+	if err != nil {
+		return fmt.Errorf("failed to get history path: %w", err)
+	}
+	store, err := history.Open(historyPath)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	for _, warning := range store.Warnings() {
+		ui.ShowWarning(warning)
+	}
+	bindings, err := keys.NewBindings(cfg.Keybindings)
+	if err != nil {
+		return fmt.Errorf("invalid keybindings: %w", err)
+	}
+
 	if err := ui.InitInput(); err != nil {
 		return fmt.Errorf("failed to initialize input: %w", err)
 	}
@@ -23,91 +108,239 @@ func Run(cfg *config.Config) error {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	t := timer.New(cfg)
-	var prevState timer.TimerState
+	t.SetTask(opts.Task)
+	r := newRunner(t, cfg, store, bindings)
+	if cfg.IdleDetection != "" && cfg.IdleDetection != "off" {
+		provider, err := idle.New(cfg.IdleDetection, r.terminal)
+		if err != nil {
+			return err
+		}
+		// auto でシステムのアイドル時間を取得できなければ検出しない
+		if provider != nil {
+			r.idle = idle.NewDetector(provider, cfg.IdleThresholdOrDefault())
+		}
+	}
+	defer startDND(t, cfg)()
+	defer startChatStatus(t, cfg)()
+	upload, stopUpload := UploadToTeam(cfg)
+	defer stopUpload()
+	r.upload = upload
+	defer r.watchScreenLock(func() (lock.Watcher, error) { return lock.NewLogind() })()
+	if cfg.TimewarriorLive {
+		detach := t.Attach(TrackTimewarrior)
+		// 終了時に作業中のインターバルを止めてから購読を解除する
+		defer detach()
+		defer t.Stop()
+	}
 
-	ui.ShowWelcome(cfg.WorkDuration, cfg.ShortBreakDuration, cfg.LongBreakDuration)
+	if opts.Listen != "" {
+		host, err := team.Listen(opts.Listen, t.State)
+		if err != nil {
+			return err
+		}
+		// 終了するときなので、待ち受けを閉じるエラーは使わない
+		defer func() { _ = host.Close() }()
+		defer t.Attach(host.Run)()
+		r.host = host
+	}
+
+	ui.ShowWelcome(cfg.WorkDuration, cfg.ShortBreakDuration, cfg.LongBreakDuration, r.progress, bindings)
 	t.Start(timer.SessionWork)
 	ui.ShowStartSession(timer.SessionWork)
+	if r.host != nil {
+		ui.ShowHosting(r.host.Addr().String())
+	}
 
 	ticker := time.NewTicker(50 * time.Millisecond)
 	defer ticker.Stop()
 
+	lockEvents := r.lockEvents()
 	for {
 		select {
 		case <-sigChan:
-			ui.ShowExit()
+			r.quit()
 			return nil
 		case key := <-ui.KeyChan():
-			if handleKeyInput(t, cfg, key) {
+			if r.handleKeyInput(key) {
 				return nil
 			}
-			state := t.State()
-			ui.RenderTimer(state.CurrentSession, state.TimerState)
+			r.render(t.State())
+		case event, ok := <-lockEvents:
+			if !ok {
+				lockEvents = nil
+				continue
+			}
+			r.handleLock(event)
+		case n := <-r.hostNotices():
+			r.handleNotice(n)
+			r.render(t.State())
 		case <-ticker.C:
 			state := t.State()
-			ui.RenderTimer(state.CurrentSession, state.TimerState)
-			handleSessionComplete(t, cfg, state, &prevState)
+			r.render(state)
+			r.handleSessionComplete(state)
+			r.checkIdle(time.Now())
+			r.askProposal()
 		}
 	}
 }
 
-// handleKeyInput はキー入力を処理する（終了時 true を返す）
-func handleKeyInput(t *timer.Timer, cfg *config.Config, key ui.KeyEvent) bool {
-	state := t.State()
-	switch key {
-	case ui.KeySpace:
+// render はタイマーの行を表示する（コマンドパレットを開いている間は入力欄を表示する）
+func (r *runner) render(state *timer.PomodoroState) {
+	if r.paletteOpen {
+		ui.RenderPalette(r.prompt, string(r.palette))
+		return
+	}
+	if r.renderGuide(state) {
+		return
+	}
+	ui.RenderTimer(state.CurrentSession, state.TimerState, r.progress)
+}
+
+// handleKeyInput はキーに割り当てられたアクションを実行する（終了時 true を返す）
+// コマンドパレットを開いている間はキーを入力として扱う
+func (r *runner) handleKeyInput(key keys.Key) bool {
+	now := time.Now()
+	r.terminal.Touch(now)
+	// 離席して一時停止している間の最初のキーは戻ってきた合図として扱う
+	if r.away != nil && !r.paletteOpen {
+		r.comeBack(now)
+		return false
+	}
+	if r.paletteOpen {
+		return r.handlePaletteKey(key)
+	}
+	return r.handleAction(r.bindings.Action(key))
+}
+
+// handleAction はアクションを実行する（終了時 true を返す）
+// strict モードで制限されたアクションは確認を求めるか実行しない
+func (r *runner) handleAction(action keys.Action) bool {
+	if r.enforceStrict(action, r.t.State()) {
+		return false
+	}
+	return r.doAction(action)
+}
+
+// doAction は strict モードの制限を確認せずにアクションを実行する（終了時 true を返す）
+func (r *runner) doAction(action keys.Action) bool {
+	state := r.t.State()
+	switch action {
+	case keys.ActionPause:
 		switch state.TimerState {
 		case timer.StateRunning:
-			t.Pause()
+			r.t.Pause()
 			ui.ShowPaused()
 		case timer.StatePaused:
-			t.Resume()
+			r.t.Resume()
 			ui.ShowResumed()
 		}
-	case ui.KeyQ:
-		ui.ShowExit()
+	case keys.ActionQuit:
+		r.quit()
 		return true
-	case ui.KeyS:
-		t.Stop()
-		nextType := t.State().NextSessionType(cfg.SessionsUntilLong)
-		t.Start(nextType)
+	case keys.ActionSkip:
+		r.recordStopped(history.OutcomeSkipped)
+		r.t.Stop()
+		nextType := r.t.State().NextSessionType(r.cfg.SessionsUntilLong)
+		r.t.Start(nextType)
 		ui.ShowSkipped(nextType)
-	case ui.KeyR:
+	case keys.ActionReset:
 		if state.CurrentSession != nil {
+			r.recordStopped(history.OutcomeSkipped)
 			sessionType := state.CurrentSession.Type
-			t.Stop()
-			t.Start(sessionType)
+			r.t.Stop()
+			r.t.Start(sessionType)
 			ui.ShowReset()
 		}
+	case keys.ActionGoal:
+		r.refreshProgress()
+		ui.ShowGoalInSession(r.progress)
+	case keys.ActionHelp:
+		ui.ShowHelp(r.bindings)
+	case keys.ActionCommand:
+		r.openPalette()
 	}
 	return false
 }
 
 // handleSessionComplete はセッション完了時の処理を行う
-func handleSessionComplete(t *timer.Timer, cfg *config.Config, state *timer.PomodoroState, prevState *timer.TimerState) {
-	if state.TimerState != timer.StateCompleted || *prevState == timer.StateCompleted {
-		*prevState = state.TimerState
+func (r *runner) handleSessionComplete(state *timer.PomodoroState) {
+	if state.TimerState != timer.StateCompleted || r.prevState == timer.StateCompleted {
+		r.prevState = state.TimerState
 		return
 	}
 
-	ui.ShowSessionComplete(state.CurrentSession.Type)
-	if cfg.NotifyEnabled {
-		if err := ui.NotifySessionComplete(state.CurrentSession.Type); err != nil {
+	nextType := state.NextSessionType(r.cfg.SessionsUntilLong)
+	suggestion := SuggestBreak(r.activities, nextType)
+	r.record(*state.CurrentSession, history.OutcomeCompleted)
+	ui.ShowSessionComplete(state.CurrentSession.Type, r.progress, suggestion)
+	if r.cfg.NotifyEnabled {
+		if err := ui.NotifySessionComplete(state.CurrentSession.Type, suggestion); err != nil {
 			ui.ShowError("Notification failed: " + err.Error())
 		}
 	}
-	if cfg.SoundEnabled {
+	if r.cfg.SoundEnabled {
 		if err := ui.PlaySound(); err != nil {
 			ui.ShowError("Sound playback failed: " + err.Error())
 		}
 	}
 
-	nextType := state.NextSessionType(cfg.SessionsUntilLong)
-	if ShouldAutoStart(cfg, nextType) {
-		t.Start(nextType)
+	if ShouldAutoStart(r.cfg, nextType) {
+		r.t.Start(nextType)
 		ui.ShowStartSession(nextType)
 	}
-	*prevState = state.TimerState
+	r.prevState = state.TimerState
+}
+
+// quit は終了メッセージを表示する
+// strict モードで作業セッションの途中なら放棄として記録する
+func (r *runner) quit() {
+	state := r.t.State()
+	if r.cfg.Strict && state.CurrentSession != nil && state.CurrentSession.Type == timer.SessionWork {
+		if r.recordStopped(history.OutcomeAbandoned) {
+			ui.ShowAbandoned()
+		}
+	}
+	ui.ShowExit()
+}
+
+// recordStopped は実行中または一時停止中のセッションを outcome として記録する（記録した場合 true を返す）
+func (r *runner) recordStopped(outcome history.Outcome) bool {
+	state := r.t.State()
+	if state.CurrentSession == nil {
+		return false
+	}
+	if state.TimerState != timer.StateRunning && state.TimerState != timer.StatePaused {
+		return false
+	}
+	session := *state.CurrentSession
+	if state.TimerState == timer.StatePaused {
+		session.PausedTotal += time.Since(session.PausedAt)
+	}
+	r.record(session, outcome)
+	return true
+}
+
+// record はセッションを履歴に記録して進捗を更新する
+func (r *runner) record(session timer.Session, outcome history.Outcome) {
+	record := history.NewRecord(session, outcome, time.Now())
+	record.Strict = r.cfg.Strict
+	if err := r.store.Append(record); err != nil {
+		ui.ShowError("Failed to record history: " + err.Error())
+	}
+	r.upload(record)
+	r.refreshProgress()
+}
+
+// refreshProgress は履歴から目標の進捗を再計算する
+func (r *runner) refreshProgress() {
+	r.progress = goal.Compute(r.store.Records(), r.cfg, time.Now())
+}
+
+// TrackTimewarrior はタイマーイベントに合わせてTimewarriorを操作する
+func TrackTimewarrior(events <-chan timer.Event) {
+	timewarrior.NewHook().Run(events, func(err error) {
+		ui.ShowError("Timewarrior failed: " + err.Error())
+	})
 }
 
 // ShouldAutoStart は自動開始すべきかを判定する
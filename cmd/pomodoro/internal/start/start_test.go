@@ -1,12 +1,14 @@
 package start
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
 	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/keys"
 	"pomodoro-cli/internal/timer"
-	"pomodoro-cli/internal/ui"
 )
 
 // =============================================================================
@@ -48,27 +50,29 @@ func TestShouldAutoStartはBreakセッションで設定に従う(t *testing.T)
 func TestQキーでタイマーを終了する(t *testing.T) {
 	cfg := config.Default()
 	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
 	tmr.Start(timer.SessionWork)
 
-	shouldExit := handleKeyInput(tmr, cfg, ui.KeyQ)
+	shouldExit := r.handleKeyInput("q")
 	if !shouldExit {
-		t.Error("handleKeyInput(KeyQ) = false, want true")
+		t.Error(`handleKeyInput("q") = false, want true`)
 	}
 }
 
 func TestSpaceキーで一時停止と再開を切り替える(t *testing.T) {
 	cfg := config.Default()
 	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
 	tmr.Start(timer.SessionWork)
 
 	// 一時停止
-	handleKeyInput(tmr, cfg, ui.KeySpace)
+	r.handleKeyInput("space")
 	if tmr.State().TimerState != timer.StatePaused {
 		t.Error("Spaceキー後に一時停止状態にならない")
 	}
 
 	// 再開
-	handleKeyInput(tmr, cfg, ui.KeySpace)
+	r.handleKeyInput("space")
 	if tmr.State().TimerState != timer.StateRunning {
 		t.Error("Spaceキー後に実行状態にならない")
 	}
@@ -81,9 +85,10 @@ func TestSキーで次のセッションにスキップする(t *testing.T) {
 		SessionsUntilLong:  4,
 	}
 	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
 	tmr.Start(timer.SessionWork)
 
-	handleKeyInput(tmr, cfg, ui.KeyS)
+	r.handleKeyInput("s")
 
 	if tmr.State().CurrentSession.Type != timer.SessionShortBreak {
 		t.Errorf("スキップ後のセッション = %v, want SessionShortBreak", tmr.State().CurrentSession.Type)
@@ -93,16 +98,47 @@ func TestSキーで次のセッションにスキップする(t *testing.T) {
 func TestRキーで現在のセッションをリセットする(t *testing.T) {
 	cfg := &config.Config{WorkDuration: 5 * time.Minute}
 	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
 	tmr.Start(timer.SessionWork)
 
 	time.Sleep(100 * time.Millisecond)
-	handleKeyInput(tmr, cfg, ui.KeyR)
+	r.handleKeyInput("r")
 
 	if tmr.State().CurrentSession.Remaining != cfg.WorkDuration {
 		t.Errorf("リセット後の残り時間 = %v, want %v", tmr.State().CurrentSession.Remaining, cfg.WorkDuration)
 	}
 }
 
+func Test設定したキーの割り当てに従ってアクションを実行する(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keybindings = map[string]string{"p": "pause", "space": "none"}
+	bindings, err := keys.NewBindings(cfg.Keybindings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	r.bindings = bindings
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	// 割り当てを外したキーは何もしない
+	r.handleKeyInput("space")
+	if tmr.State().TimerState != timer.StateRunning {
+		t.Error("割り当てを外したSpaceキーで一時停止した")
+	}
+
+	r.handleKeyInput("p")
+	if tmr.State().TimerState != timer.StatePaused {
+		t.Error("pキー後に一時停止状態にならない")
+	}
+
+	// デフォルトの割り当ては残る
+	if !r.handleKeyInput("ctrl+c") {
+		t.Error(`handleKeyInput("ctrl+c") = false, want true`)
+	}
+}
+
 // =============================================================================
 // handleSessionComplete - セッション完了時の処理
 // =============================================================================
@@ -124,8 +160,7 @@ func TestWork完了後に自動開始が有効なら次のBreakが始まる(t *t
 		t.Fatalf("state = %v, want StateCompleted", state.TimerState)
 	}
 
-	var prevState timer.TimerState
-	handleSessionComplete(tmr, cfg, state, &prevState)
+	newTestRunner(t, tmr, cfg).handleSessionComplete(state)
 
 	if tmr.State().CurrentSession.Type != timer.SessionShortBreak {
 		t.Errorf("次のセッション = %v, want SessionShortBreak", tmr.State().CurrentSession.Type)
@@ -149,8 +184,7 @@ func TestWork完了後に自動開始が無効なら待機状態のまま(t *tes
 		t.Fatalf("state = %v, want StateCompleted", state.TimerState)
 	}
 
-	var prevState timer.TimerState
-	handleSessionComplete(tmr, cfg, state, &prevState)
+	newTestRunner(t, tmr, cfg).handleSessionComplete(state)
 
 	if tmr.State().TimerState != timer.StateCompleted {
 		t.Errorf("state = %v, want StateCompleted（自動開始無効）", tmr.State().TimerState)
@@ -174,10 +208,68 @@ func TestBreak完了後に自動開始が有効なら次のWorkが始まる(t *t
 		t.Fatalf("state = %v, want StateCompleted", state.TimerState)
 	}
 
-	var prevState timer.TimerState
-	handleSessionComplete(tmr, cfg, state, &prevState)
+	newTestRunner(t, tmr, cfg).handleSessionComplete(state)
 
 	if tmr.State().CurrentSession.Type != timer.SessionWork {
 		t.Errorf("次のセッション = %v, want SessionWork", tmr.State().CurrentSession.Type)
 	}
 }
+
+// =============================================================================
+// History - 履歴の記録と目標の進捗
+// =============================================================================
+
+func TestSキーでスキップしたセッションを履歴に記録する(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleKeyInput("s")
+
+	records := r.store.Records()
+	if len(records) != 1 {
+		t.Fatalf("records = %d, want 1", len(records))
+	}
+	if records[0].Outcome != history.OutcomeSkipped || records[0].Type != timer.SessionWork {
+		t.Errorf("record = %+v, want skipped work session", records[0])
+	}
+}
+
+func TestWork完了で履歴に記録され今日の進捗が増える(t *testing.T) {
+	cfg := &config.Config{
+		WorkDuration:       1 * time.Second,
+		ShortBreakDuration: 1 * time.Second,
+		SessionsUntilLong:  4,
+		DailyGoal:          8,
+	}
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+
+	time.Sleep(2 * time.Second)
+
+	r.handleSessionComplete(tmr.State())
+
+	if r.progress.Today != 1 || r.progress.DailyGoal != 8 {
+		t.Errorf("progress = %+v, want 1/8 today", r.progress)
+	}
+	records := r.store.Records()
+	if len(records) != 1 || !records[0].IsCompletedWork() {
+		t.Errorf("records = %+v, want one completed work session", records)
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func newTestRunner(t *testing.T, tmr *timer.Timer, cfg *config.Config) *runner {
+	t.Helper()
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("history.Open() error = %v", err)
+	}
+	return newRunner(tmr, cfg, store, keys.DefaultBindings())
+}
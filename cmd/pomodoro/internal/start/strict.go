@@ -0,0 +1,67 @@
+package start
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/ui"
+)
+
+// strictPhrase は strict モードで作業セッションを中断するときに入力する確認の言葉
+const strictPhrase = "I give up"
+
+// enforceStrict は strict モードで制限されたアクションを止める（止めた場合 true を返す）
+// 作業セッション中の一時停止・スキップ・リセットは確認の言葉を入力すると実行する
+// strict_breaks なら休憩のスキップは実行しない
+func (r *runner) enforceStrict(action keys.Action, state *timer.PomodoroState) bool {
+	if !r.cfg.Strict || state.CurrentSession == nil {
+		return false
+	}
+	if state.TimerState != timer.StateRunning && state.TimerState != timer.StatePaused {
+		return false
+	}
+
+	if state.CurrentSession.Type != timer.SessionWork {
+		if r.cfg.StrictBreaks && action == keys.ActionSkip {
+			ui.ShowStrictRefused("breaks cannot be skipped. Enjoy the rest!")
+			return true
+		}
+		return false
+	}
+	switch action {
+	case keys.ActionPause:
+		// 確認して一時停止したセッションの再開は止めない
+		if state.TimerState == timer.StatePaused {
+			return false
+		}
+	case keys.ActionSkip, keys.ActionReset:
+	default:
+		return false
+	}
+	// 入力中にセッションが終わって次のセッションが始まったら、確認は次のセッションに使わない
+	startedAt := state.CurrentSession.StartedAt
+	r.openPrompt(fmt.Sprintf("Strict mode: type %q to %s: ", strictPhrase, action), func(line string) (bool, error) {
+		if strings.TrimSpace(line) != strictPhrase {
+			ui.ShowStrictRefused("the phrase did not match. Keep going!")
+			return false, nil
+		}
+		if !r.sameSession(startedAt) {
+			ui.ShowStrictRefused("the session ended while you were typing. Nothing was done.")
+			return false, nil
+		}
+		return r.doAction(action), nil
+	})
+	return true
+}
+
+// sameSession は startedAt に開始したセッションがまだ実行中か一時停止中かを返す
+func (r *runner) sameSession(startedAt time.Time) bool {
+	state := r.t.State()
+	if state.CurrentSession == nil || !state.CurrentSession.StartedAt.Equal(startedAt) {
+		return false
+	}
+	return state.TimerState == timer.StateRunning || state.TimerState == timer.StatePaused
+}
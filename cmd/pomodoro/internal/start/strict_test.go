@@ -0,0 +1,163 @@
+package start
+
+import (
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Strict Mode - strict モード
+// =============================================================================
+
+func TestStrictモードでは確認の言葉なしに作業セッションをスキップしない(t *testing.T) {
+	cfg := config.Default()
+	cfg.Strict = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleKeyInput("s")
+	if !r.paletteOpen {
+		t.Fatal("確認の入力欄が開かない")
+	}
+	typeLine(r, "whatever")
+
+	if tmr.State().CurrentSession.Type != timer.SessionWork {
+		t.Error("確認の言葉が違うのにスキップした")
+	}
+	if len(r.store.Records()) != 0 {
+		t.Errorf("records = %+v, want none", r.store.Records())
+	}
+}
+
+func TestStrictモードでは確認の言葉を入力すると実行する(t *testing.T) {
+	cfg := config.Default()
+	cfg.Strict = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleKeyInput("space")
+	typeLine(r, strictPhrase)
+	if tmr.State().TimerState != timer.StatePaused {
+		t.Fatal("確認の言葉を入力しても一時停止しない")
+	}
+
+	// 再開は確認しない
+	r.handleKeyInput("space")
+	if tmr.State().TimerState != timer.StateRunning || r.paletteOpen {
+		t.Error("再開に確認を求めた")
+	}
+
+	r.handleKeyInput("s")
+	typeLine(r, strictPhrase)
+	if tmr.State().CurrentSession.Type != timer.SessionShortBreak {
+		t.Error("確認の言葉を入力してもスキップしない")
+	}
+	records := r.store.Records()
+	if len(records) != 1 || records[0].Outcome != history.OutcomeSkipped || !records[0].Strict {
+		t.Errorf("records = %+v, want one strict skipped record", records)
+	}
+}
+
+func TestStrictモードの確認は入力中に始まった次のセッションには使わない(t *testing.T) {
+	cfg := config.Default()
+	cfg.Strict = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleKeyInput("s")
+	if !r.paletteOpen {
+		t.Fatal("確認の入力欄が開かない")
+	}
+	// 入力中に作業セッションが終わり、次の作業セッションが始まった
+	time.Sleep(time.Millisecond)
+	tmr.Start(timer.SessionWork)
+	next := tmr.State().CurrentSession.StartedAt
+	typeLine(r, strictPhrase)
+
+	state := tmr.State()
+	if state.CurrentSession.Type != timer.SessionWork || !state.CurrentSession.StartedAt.Equal(next) {
+		t.Error("前のセッションの確認で次のセッションをスキップした")
+	}
+	if len(r.store.Records()) != 0 {
+		t.Errorf("records = %+v, want none", r.store.Records())
+	}
+}
+
+func TestStrictモードで作業中に終了すると放棄として記録する(t *testing.T) {
+	cfg := config.Default()
+	cfg.Strict = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	if !r.handleKeyInput("q") {
+		t.Fatal("strictモードでも終了はできる")
+	}
+
+	records := r.store.Records()
+	if len(records) != 1 || records[0].Outcome != history.OutcomeAbandoned {
+		t.Errorf("records = %+v, want one abandoned record", records)
+	}
+	if r.progress.Abandoned != 1 {
+		t.Errorf("progress.Abandoned = %d, want 1", r.progress.Abandoned)
+	}
+}
+
+func TestStrictモードでなければ終了しても記録しない(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionWork)
+	defer tmr.Stop()
+
+	r.handleKeyInput("q")
+	if len(r.store.Records()) != 0 {
+		t.Errorf("records = %+v, want none", r.store.Records())
+	}
+}
+
+func TestStrictBreaksでは休憩をスキップできない(t *testing.T) {
+	cfg := config.Default()
+	cfg.Strict = true
+	tmr := timer.New(cfg)
+	r := newTestRunner(t, tmr, cfg)
+	tmr.Start(timer.SessionLongBreak)
+	defer tmr.Stop()
+
+	// strict_breaks がなければ休憩は自由にスキップできる
+	r.handleKeyInput("s")
+	if tmr.State().CurrentSession.Type != timer.SessionWork {
+		t.Fatal("strict_breaks なしで休憩をスキップできない")
+	}
+
+	cfg.StrictBreaks = true
+	tmr.Start(timer.SessionLongBreak)
+	r.handleKeyInput("s")
+	if tmr.State().CurrentSession.Type != timer.SessionLongBreak || r.paletteOpen {
+		t.Error("strict_breaks で休憩をスキップした")
+	}
+}
+
+// typeLine は開いている入力欄に line を入力して Enter を押す
+func typeLine(r *runner, line string) {
+	for _, ch := range line {
+		key := keys.Key(string(ch))
+		if ch == ' ' {
+			key = "space"
+		}
+		r.handleKeyInput(key)
+	}
+	r.handleKeyInput("enter")
+}
@@ -0,0 +1,35 @@
+package start
+
+import (
+	"path/filepath"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/teamserver"
+	"pomodoro-cli/internal/ui"
+)
+
+// UploadToTeam は完了した作業セッションをチームサーバーへ送る関数を返す（設定がなければ何もしない）
+// 送れなかった記録は設定ディレクトリに貯めておき、接続が戻ったとき（次回の起動時を含む）に送る
+// 返された stop は送信を止める
+func UploadToTeam(cfg *config.Config) (upload func(history.Record), stop func()) {
+	if cfg.TeamServer == "" {
+		return func(history.Record) {}, func() {}
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		ui.ShowError("Team server unavailable: " + err.Error())
+		return func(history.Record) {}, func() {}
+	}
+	outbox := teamserver.NewOutbox(filepath.Join(dir, teamserver.OutboxFile))
+	client := teamserver.NewClient(cfg.TeamServer, cfg.TeamToken)
+	u := teamserver.NewUploader(outbox, client, func(err error) {
+		ui.ShowError("Team server unreachable, sessions will be uploaded later: " + err.Error())
+	})
+	upload = func(r history.Record) {
+		if err := u.Add(r); err != nil {
+			ui.ShowError(err.Error())
+		}
+	}
+	return upload, u.Close
+}
@@ -0,0 +1,94 @@
+package teamserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/teamserver"
+	"pomodoro-cli/internal/ui"
+)
+
+// shutdownTimeout は終了時に処理中のリクエストを待つ時間
+const shutdownTimeout = 5 * time.Second
+
+// Options は server コマンドのオプション
+type Options struct {
+	// Listen は待ち受けアドレス
+	Listen string
+	// Data はユーザーと記録を保存するディレクトリ（空なら設定ディレクトリの server）
+	Data string
+}
+
+// dataDir はデータを保存するディレクトリを返す
+func (o Options) dataDir() (string, error) {
+	if o.Data != "" {
+		return o.Data, nil
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return filepath.Join(dir, "server"), nil
+}
+
+// Run はチームサーバーを起動する
+func Run(opts Options) error {
+	dir, err := opts.dataDir()
+	if err != nil {
+		return err
+	}
+	store, err := teamserver.OpenStore(dir)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", opts.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	srv := &http.Server{
+		Handler:           teamserver.NewServer(store).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	ui.ShowTeamServing(ln.Addr().String(), dir, len(store.Users()))
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error: %w", err)
+	}
+	return nil
+}
+
+// AddUser はユーザーを登録してトークンを表示する（登録済みならトークンを作り直す）
+func AddUser(opts Options, user string) error {
+	dir, err := opts.dataDir()
+	if err != nil {
+		return err
+	}
+	store, err := teamserver.OpenStore(dir)
+	if err != nil {
+		return err
+	}
+	token, err := store.AddUser(user)
+	if err != nil {
+		return err
+	}
+	ui.ShowTeamUserAdded(user, token)
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pomodoro-cli/internal/cli"
+)
+
+// =============================================================================
+// newRootCommand - コマンドツリー
+// =============================================================================
+
+func Testルートのヘルプにすべてのコマンドとフラグがある(t *testing.T) {
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.Stdout = &out
+	if err := root.Execute([]string{"--help"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	expectedStrings := []string{
+		"Usage:\n  pomodoro [flags] [command]",
+		"Commands:",
+		"start", "config", "init", "goal", "plan", "export", "serve", "completion", "help",
+		"Flags:",
+		"-w, --work DURATION",
+		"-s, --short-break DURATION",
+		"-l, --long-break DURATION",
+		"-n, --sessions N",
+		"-p, --profile NAME",
+		"--config FILE",
+		"-t, --task NAME",
+		"--no-sound",
+		"--no-notify",
+		"-v, --version",
+		"-h, --help",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("help missing %q:\n%s", expected, out.String())
+		}
+	}
+}
+
+func Testサブコマンドは設定を上書きするフラグを引き継ぐ(t *testing.T) {
+	root := newRootCommand()
+	for _, path := range [][]string{{"start"}, {"config", "get"}, {"init"}, {"export"}} {
+		cmd := root
+		for _, name := range path {
+			cmd = cmd.Find(name)
+		}
+		var names []string
+		for _, f := range cmd.InheritedFlags() {
+			names = append(names, f.Name)
+		}
+		for _, want := range []string{"work", "profile", "config", "task"} {
+			if !strings.Contains(" "+strings.Join(names, " ")+" ", " "+want+" ") {
+				t.Errorf("%s does not inherit --%s", cmd.Path(), want)
+			}
+		}
+	}
+}
+
+// =============================================================================
+// gen-man - 生成したドキュメント
+// =============================================================================
+
+func Test生成したドキュメントがdocsと一致する(t *testing.T) {
+	for _, tt := range []struct {
+		file string
+		args []string
+	}{
+		{"pomodoro.1", []string{"gen-man"}},
+		{"cli.md", []string{"gen-man", "--markdown"}},
+	} {
+		var out bytes.Buffer
+		root := newRootCommand()
+		root.Stdout = &out
+		if err := root.Execute(tt.args); err != nil {
+			t.Fatalf("Execute(%q) error = %v", tt.args, err)
+		}
+		want, err := os.ReadFile(filepath.Join("..", "..", "docs", tt.file))
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if out.String() != string(want) {
+			t.Errorf("docs/%s is out of date; run 'go generate ./cmd/pomodoro'", tt.file)
+		}
+	}
+}
+
+func Test生成したドキュメントにすべてのコマンドとフラグがある(t *testing.T) {
+	root := newRootCommand()
+	var man, markdown bytes.Buffer
+	if err := root.WriteMan(&man); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+
+	var walk func(cmd *cli.Command)
+	walk = func(cmd *cli.Command) {
+		if cmd.Hidden {
+			return
+		}
+		if !strings.Contains(markdown.String(), "## "+cmd.Path()) && cmd != root {
+			t.Errorf("markdown is missing %q", cmd.Path())
+		}
+		for _, f := range cmd.Flags {
+			if !strings.Contains(man.String(), `\fB\-\-`+strings.ReplaceAll(f.Name, "-", `\-`)+`\fR`) {
+				t.Errorf("man page is missing --%s of %q", f.Name, cmd.Path())
+			}
+			if !strings.Contains(markdown.String(), strings.TrimSpace(cli.FlagColumn(f))) {
+				t.Errorf("markdown is missing --%s of %q", f.Name, cmd.Path())
+			}
+		}
+		for _, sub := range cmd.Commands {
+			walk(sub)
+		}
+	}
+	walk(root)
+
+	if strings.Contains(man.String(), "gen-man") || strings.Contains(markdown.String(), "__complete") {
+		t.Error("hidden commands should not be documented")
+	}
+}
+
+func TestHelpコマンドは指定したコマンドのヘルプを表示する(t *testing.T) {
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.Stdout = &out
+	if err := root.Execute([]string{"help", "config", "set"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "pomodoro config set [flags] KEY VALUE") {
+		t.Errorf("help = %q", out.String())
+	}
+	if err := root.Execute([]string{"help", "bogus"}); err == nil {
+		t.Error("help for an unknown command should fail")
+	}
+}
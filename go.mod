@@ -2,6 +2,11 @@ module pomodoro-cli
 
 go 1.25.1
 
-require golang.org/x/term v0.39.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/godbus/dbus/v5 v5.2.2
+	golang.org/x/term v0.39.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require golang.org/x/sys v0.40.0 // indirect
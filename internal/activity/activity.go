@@ -0,0 +1,233 @@
+// Package activity は休憩中の過ごし方（ストレッチ、水分補給、目の体操など）を提案し、
+// 短い休憩に呼吸などのガイドを表示するためのステップを提供する
+package activity
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BreakTypes は過ごし方の一覧を設定できる休憩の種類（セッション種類の識別子）
+var BreakTypes = []string{"short_break", "long_break"}
+
+// Defaults は設定がないときに提案する過ごし方（先頭の "2x " は重み）
+var Defaults = map[string][]string{
+	"short_break": {
+		"2x Stand up and stretch your back and shoulders",
+		"2x Drink a glass of water",
+		"2x 20-20-20: look at something 20 feet (6 m) away for 20 seconds",
+		"Roll your neck and shoulders slowly",
+		"Take a few slow, deep breaths",
+	},
+	"long_break": {
+		"2x Take a short walk, outside if you can",
+		"2x Refill your water and have a healthy snack",
+		"Do a full-body stretch",
+		"Rest your eyes away from any screen",
+		"Open a window and get some fresh air",
+	},
+}
+
+// maxWeight は重みの上限
+const maxWeight = 100
+
+// recentSize は同じ休憩の種類で繰り返さない直近の過ごし方の数
+const recentSize = 3
+
+// Activity は休憩中の過ごし方
+type Activity struct {
+	Text string
+	// Weight は選ばれやすさ（1 以上）
+	Weight int
+}
+
+// weightPrefix は "3x Stretch" のような先頭の重み
+var weightPrefix = regexp.MustCompile(`^(\d+)x\s+`)
+
+// Parse は "3x Stretch" のように先頭に重みを付けられる過ごし方を解釈する（重みの省略は 1）
+func Parse(s string) (Activity, error) {
+	s = strings.TrimSpace(s)
+	a := Activity{Text: s, Weight: 1}
+	if m := weightPrefix.FindStringSubmatch(s); m != nil {
+		weight, err := strconv.Atoi(m[1])
+		if err != nil || weight < 1 || weight > maxWeight {
+			return Activity{}, fmt.Errorf("weight must be between 1 and %d, got %s", maxWeight, m[1])
+		}
+		a = Activity{Text: strings.TrimSpace(s[len(m[0]):]), Weight: weight}
+	}
+	if a.Text == "" {
+		return Activity{}, errors.New("must not be empty")
+	}
+	return a, nil
+}
+
+// Picker は休憩の種類ごとに重みに従って過ごし方を選ぶ
+// 直近に選んだものは（他の候補がある限り）選ばない
+type Picker struct {
+	lists  map[string][]Activity
+	recent map[string][]string
+	// intN は [0, n) の乱数を返す（テストで差し替える）
+	intN func(n int) int
+}
+
+// NewPicker は lists（休憩の種類 → 過ごし方）から選ぶ Picker を作成する
+// 一覧のない休憩の種類は Defaults を使う。解釈できない過ごし方は無視する（設定の検証で報告する）
+func NewPicker(lists map[string][]string) *Picker {
+	p := &Picker{lists: make(map[string][]Activity), recent: make(map[string][]string), intN: rand.IntN}
+	for _, breakType := range BreakTypes {
+		entries, ok := lists[breakType]
+		if !ok {
+			entries = Defaults[breakType]
+		}
+		for _, entry := range entries {
+			if a, err := Parse(entry); err == nil {
+				p.lists[breakType] = append(p.lists[breakType], a)
+			}
+		}
+	}
+	return p
+}
+
+// Pick は休憩の種類の過ごし方を1つ選ぶ（一覧が空なら false を返す）
+func (p *Picker) Pick(breakType string) (Activity, bool) {
+	list := p.lists[breakType]
+	if len(list) == 0 {
+		return Activity{}, false
+	}
+	recent := p.recent[breakType]
+	// すべてが直近に選んだものなら、最も古いものから候補に戻す
+	for len(recent) > 0 {
+		if slices.ContainsFunc(list, func(a Activity) bool { return !slices.Contains(recent, a.Text) }) {
+			break
+		}
+		recent = recent[1:]
+	}
+
+	total := 0
+	for _, a := range list {
+		if !slices.Contains(recent, a.Text) {
+			total += a.Weight
+		}
+	}
+	n := p.intN(total)
+	var picked Activity
+	for _, a := range list {
+		if slices.Contains(recent, a.Text) {
+			continue
+		}
+		if n < a.Weight {
+			picked = a
+			break
+		}
+		n -= a.Weight
+	}
+
+	recent = append(recent, picked.Text)
+	if len(recent) > recentSize {
+		recent = recent[len(recent)-recentSize:]
+	}
+	p.recent[breakType] = recent
+	return picked, true
+}
+
+// ----------------------------------------------------------------------------
+// ガイド
+// ----------------------------------------------------------------------------
+
+// Step はガイドの1つの手順
+type Step struct {
+	Text     string
+	Duration time.Duration
+}
+
+// Guide は短い休憩の最初に表示する時間を決めた手順
+type Guide struct {
+	Name  string
+	Title string
+	Steps []Step
+	// Rounds は手順を繰り返す回数
+	Rounds int
+}
+
+// Guides は設定の guided_break に指定できるガイド
+var Guides = []Guide{
+	{
+		Name:  "4-7-8",
+		Title: "4-7-8 breathing",
+		Steps: []Step{
+			{"Breathe in through your nose", 4 * time.Second},
+			{"Hold your breath", 7 * time.Second},
+			{"Breathe out through your mouth", 8 * time.Second},
+		},
+		Rounds: 4,
+	},
+	{
+		Name:  "box",
+		Title: "Box breathing",
+		Steps: []Step{
+			{"Breathe in", 4 * time.Second},
+			{"Hold", 4 * time.Second},
+			{"Breathe out", 4 * time.Second},
+			{"Hold", 4 * time.Second},
+		},
+		Rounds: 4,
+	},
+	{
+		Name:  "20-20-20",
+		Title: "Eye rest",
+		Steps: []Step{
+			{"Look at something 20 feet (6 m) away", 20 * time.Second},
+		},
+		Rounds: 1,
+	},
+}
+
+// GuideNames はガイドの名前の一覧を返す
+func GuideNames() []string {
+	names := make([]string, len(Guides))
+	for i, g := range Guides {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// FindGuide は名前に対応するガイドを返す
+func FindGuide(name string) (Guide, bool) {
+	for _, g := range Guides {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return Guide{}, false
+}
+
+// Total はガイド全体の時間を返す
+func (g Guide) Total() time.Duration {
+	var round time.Duration
+	for _, s := range g.Steps {
+		round += s.Duration
+	}
+	return round * time.Duration(g.Rounds)
+}
+
+// At は開始から elapsed 経過したときの手順、何回目（1 から）か、手順の残り時間を返す
+// ガイドが終わっていれば ok は false
+func (g Guide) At(elapsed time.Duration) (step Step, round int, left time.Duration, ok bool) {
+	if elapsed < 0 || elapsed >= g.Total() {
+		return Step{}, 0, 0, false
+	}
+	for round = 1; ; round++ {
+		for _, s := range g.Steps {
+			if elapsed < s.Duration {
+				return s, round, s.Duration - elapsed, true
+			}
+			elapsed -= s.Duration
+		}
+	}
+}
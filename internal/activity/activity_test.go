@@ -0,0 +1,136 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// 過ごし方
+// =============================================================================
+
+func TestParseは先頭の重みを読み取る(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Activity
+	}{
+		{"Drink water", Activity{"Drink water", 1}},
+		{"3x Stretch", Activity{"Stretch", 3}},
+		{"  2x   Walk  ", Activity{"Walk", 2}},
+		{"20-20-20: look away", Activity{"20-20-20: look away", 1}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "  ", "0x Stretch", "101x Stretch"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+}
+
+func TestPickは重みに従って選ぶ(t *testing.T) {
+	p := NewPicker(map[string][]string{"short_break": {"Water", "3x Stretch"}})
+	// 重みの合計は 4 で、0 は Water、1〜3 は Stretch
+	p.intN = func(n int) int {
+		if n != 4 {
+			t.Fatalf("intN(%d), want 4", n)
+		}
+		return 1
+	}
+	if a, ok := p.Pick("short_break"); !ok || a.Text != "Stretch" {
+		t.Errorf("Pick() = %+v, %v, want Stretch", a, ok)
+	}
+}
+
+func TestPickは直近に選んだものを繰り返さない(t *testing.T) {
+	p := NewPicker(map[string][]string{"short_break": {"A", "B", "C", "D", "E"}})
+	p.intN = func(int) int { return 0 }
+
+	var got []string
+	for range 7 {
+		a, _ := p.Pick("short_break")
+		got = append(got, a.Text)
+	}
+	want := []string{"A", "B", "C", "D", "A", "B", "C"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("picks = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestPickは候補が少なければ最も古いものから戻す(t *testing.T) {
+	p := NewPicker(map[string][]string{"short_break": {"A", "B"}, "long_break": {"Walk"}})
+	p.intN = func(int) int { return 0 }
+
+	var got []string
+	for range 4 {
+		a, _ := p.Pick("short_break")
+		got = append(got, a.Text)
+	}
+	if got[0] != "A" || got[1] != "B" || got[2] != "A" || got[3] != "B" {
+		t.Errorf("picks = %v, want alternating A and B", got)
+	}
+	for range 2 {
+		if a, ok := p.Pick("long_break"); !ok || a.Text != "Walk" {
+			t.Errorf("Pick(long_break) = %+v, %v, want Walk", a, ok)
+		}
+	}
+}
+
+func TestNewPickerは一覧のない休憩にデフォルトを使う(t *testing.T) {
+	p := NewPicker(map[string][]string{"short_break": {}})
+	if _, ok := p.Pick("short_break"); ok {
+		t.Error("an empty list should disable suggestions")
+	}
+	if _, ok := p.Pick("long_break"); !ok {
+		t.Error("long_break should use the defaults")
+	}
+	for _, breakType := range BreakTypes {
+		for _, entry := range Defaults[breakType] {
+			if _, err := Parse(entry); err != nil {
+				t.Errorf("default %q: %v", entry, err)
+			}
+		}
+	}
+}
+
+// =============================================================================
+// ガイド
+// =============================================================================
+
+func TestAtは経過時間の手順と残り時間を返す(t *testing.T) {
+	g, ok := FindGuide("4-7-8")
+	if !ok {
+		t.Fatal("4-7-8 guide not found")
+	}
+	if g.Total() != 76*time.Second {
+		t.Errorf("Total() = %v, want 1m16s", g.Total())
+	}
+
+	tests := []struct {
+		elapsed time.Duration
+		text    string
+		round   int
+		left    time.Duration
+	}{
+		{0, "Breathe in through your nose", 1, 4 * time.Second},
+		{5 * time.Second, "Hold your breath", 1, 6 * time.Second},
+		{18 * time.Second, "Breathe out through your mouth", 1, time.Second},
+		{19 * time.Second, "Breathe in through your nose", 2, 4 * time.Second},
+		{75 * time.Second, "Breathe out through your mouth", 4, time.Second},
+	}
+	for _, tt := range tests {
+		step, round, left, ok := g.At(tt.elapsed)
+		if !ok || step.Text != tt.text || round != tt.round || left != tt.left {
+			t.Errorf("At(%v) = %q, %d, %v, %v, want %q, %d, %v", tt.elapsed, step.Text, round, left, ok, tt.text, tt.round, tt.left)
+		}
+	}
+	if _, _, _, ok := g.At(76 * time.Second); ok {
+		t.Error("At(Total()) should report the guide as finished")
+	}
+}
@@ -0,0 +1,292 @@
+// Package chatstatus は作業中であることをチャットのステータスに表示する
+// Slack 互換の users.profile.set と Mattermost のカスタムステータスに対応する
+package chatstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status はチャットに表示するステータス
+type Status struct {
+	// Emoji は絵文字の名前（コロンなし、例: tomato）
+	Emoji string
+	Text  string
+	// Expires はステータスを自動で消す時刻（ゼロなら消さない）
+	Expires time.Time
+}
+
+// Focusing は作業セッションが until に終わることを表すステータスを返す（🍅 focusing until 14:25）
+func Focusing(until time.Time) Status {
+	return Status{Emoji: "tomato", Text: "focusing until " + until.Format("15:04"), Expires: until}
+}
+
+// Publisher はチャットのステータスを設定・解除する
+type Publisher interface {
+	Name() string
+	Set(ctx context.Context, status Status) error
+	Clear(ctx context.Context) error
+}
+
+// Providers は設定の chat_status に指定できるチャットの種類
+var Providers = []string{"slack", "mattermost"}
+
+// DefaultSlackURL は chat_status_url を設定していないときの Slack の URL
+const DefaultSlackURL = "https://slack.com"
+
+// New は名前に対応する Publisher を返す（baseURL が空なら Slack は DefaultSlackURL を使う）
+func New(name, baseURL, token string) (Publisher, error) {
+	if token == "" {
+		return nil, errors.New("chat_status_token is not set")
+	}
+	switch name {
+	case "slack":
+		if baseURL == "" {
+			baseURL = DefaultSlackURL
+		}
+		return NewSlack(baseURL, token), nil
+	case "mattermost":
+		if baseURL == "" {
+			return nil, errors.New("chat_status_url is required for mattermost")
+		}
+		return NewMattermost(baseURL, token), nil
+	default:
+		return nil, fmt.Errorf("unknown chat status provider %q (use one of: %s)", name, strings.Join(Providers, ", "))
+	}
+}
+
+// ----------------------------------------------------------------------------
+// HTTP クライアント
+// ----------------------------------------------------------------------------
+
+// 一時的な失敗（接続エラー、429、5xx）を再試行する回数と最初の待ち時間
+const (
+	defaultAttempts = 4
+	defaultBackoff  = 500 * time.Millisecond
+	maxBackoff      = 10 * time.Second
+)
+
+// client はトークン付きの JSON リクエストを送り、一時的な失敗を指数バックオフで再試行する
+type client struct {
+	baseURL  string
+	token    string
+	http     *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+func newClient(baseURL, token string) client {
+	return client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		token:    token,
+		http:     &http.Client{Timeout: 10 * time.Second},
+		attempts: defaultAttempts,
+		backoff:  defaultBackoff,
+	}
+}
+
+// statusError は HTTP のエラー応答
+type statusError struct {
+	code       int
+	body       string
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	if e.body == "" {
+		return fmt.Sprintf("HTTP %d", e.code)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.code, e.body)
+}
+
+// temporary は再試行すれば成功しうる応答か
+func (e *statusError) temporary() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// do はリクエストを送って応答の本文を返す
+func (c *client) do(ctx context.Context, method, path string, body any) ([]byte, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	wait := c.backoff
+	for attempt := 1; ; attempt++ {
+		data, err := c.send(ctx, method, path, payload)
+		if err == nil {
+			return data, nil
+		}
+		var serr *statusError
+		if errors.As(err, &serr) && !serr.temporary() || attempt >= c.attempts {
+			return nil, err
+		}
+		// Retry-After があればそれに従う
+		delay := wait
+		if serr != nil && serr.retryAfter > 0 {
+			delay = min(serr.retryAfter, maxBackoff)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		wait = min(wait*2, maxBackoff)
+	}
+}
+
+// send はリクエストを1回送る
+func (c *client) send(ctx context.Context, method, path string, payload []byte) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	// 応答は読み終えているので、閉じるエラーは使わない
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		serr := &statusError{code: resp.StatusCode, body: strings.TrimSpace(string(data))}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			serr.retryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, serr
+	}
+	return data, nil
+}
+
+// ----------------------------------------------------------------------------
+// Slack
+// ----------------------------------------------------------------------------
+
+// Slack は users.profile.set でプロフィールのステータスを変更する
+// Slack 互換の API を持つサーバーにも baseURL を変えて使える
+type Slack struct {
+	client
+}
+
+// NewSlack は baseURL の Slack 互換 API を使う Slack を作成する
+func NewSlack(baseURL, token string) *Slack {
+	return &Slack{client: newClient(baseURL, token)}
+}
+
+// Name はチャットの種類を返す
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+// slackProfile は users.profile.set の profile
+type slackProfile struct {
+	StatusText       string `json:"status_text"`
+	StatusEmoji      string `json:"status_emoji"`
+	StatusExpiration int64  `json:"status_expiration"`
+}
+
+// Set はステータスを設定する
+func (s *Slack) Set(ctx context.Context, status Status) error {
+	profile := slackProfile{StatusText: status.Text}
+	if status.Emoji != "" {
+		profile.StatusEmoji = ":" + status.Emoji + ":"
+	}
+	if !status.Expires.IsZero() {
+		profile.StatusExpiration = status.Expires.Unix()
+	}
+	return s.setProfile(ctx, profile)
+}
+
+// Clear はステータスを消す
+func (s *Slack) Clear(ctx context.Context) error {
+	return s.setProfile(ctx, slackProfile{})
+}
+
+// setProfile は users.profile.set を呼ぶ（HTTP 200 でも ok が false なら失敗）
+func (s *Slack) setProfile(ctx context.Context, profile slackProfile) error {
+	data, err := s.do(ctx, http.MethodPost, "/api/users.profile.set", map[string]any{"profile": profile})
+	if err != nil {
+		return fmt.Errorf("failed to set slack status: %w", err)
+	}
+	var resp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse slack response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("failed to set slack status: %s", resp.Error)
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+// Mattermost
+// ----------------------------------------------------------------------------
+
+// Mattermost は API v4 のカスタムステータスを変更する
+type Mattermost struct {
+	client
+}
+
+// NewMattermost は baseURL の Mattermost サーバーを使う Mattermost を作成する
+func NewMattermost(baseURL, token string) *Mattermost {
+	return &Mattermost{client: newClient(baseURL, token)}
+}
+
+// Name はチャットの種類を返す
+func (m *Mattermost) Name() string {
+	return "mattermost"
+}
+
+// mattermostStatus は /users/me/status/custom の本文
+type mattermostStatus struct {
+	Emoji     string `json:"emoji"`
+	Text      string `json:"text"`
+	Duration  string `json:"duration,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// Set はカスタムステータスを設定する
+func (m *Mattermost) Set(ctx context.Context, status Status) error {
+	body := mattermostStatus{Emoji: status.Emoji, Text: status.Text}
+	if !status.Expires.IsZero() {
+		body.Duration = "date_and_time"
+		body.ExpiresAt = status.Expires.UTC().Format(time.RFC3339)
+	}
+	if _, err := m.do(ctx, http.MethodPut, "/api/v4/users/me/status/custom", body); err != nil {
+		return fmt.Errorf("failed to set mattermost status: %w", err)
+	}
+	return nil
+}
+
+// Clear はカスタムステータスを消す
+func (m *Mattermost) Clear(ctx context.Context) error {
+	if _, err := m.do(ctx, http.MethodDelete, "/api/v4/users/me/status/custom", nil); err != nil {
+		return fmt.Errorf("failed to clear mattermost status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package chatstatus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// テスト用のチャットサーバー
+// =============================================================================
+
+// request はテストサーバーが受け取ったリクエスト
+type request struct {
+	method string
+	path   string
+	auth   string
+	body   map[string]any
+}
+
+// stubServer は受け取ったリクエストを記録し、responses を順に返すサーバー
+type stubServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	requests  []request
+	responses []func(w http.ResponseWriter)
+}
+
+func newStubServer(t *testing.T, responses ...func(w http.ResponseWriter)) *stubServer {
+	t.Helper()
+	s := &stubServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Errorf("invalid JSON body %q: %v", data, err)
+			}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, request{r.Method, r.URL.Path, r.Header.Get("Authorization"), body})
+		if len(s.responses) == 0 {
+			if _, err := io.WriteString(w, `{"ok":true}`); err != nil {
+				t.Errorf("write response error = %v", err)
+			}
+			return
+		}
+		respond := s.responses[0]
+		s.responses = s.responses[1:]
+		respond(w)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// fastRetry はテストのために再試行の待ち時間を短くする
+func fastRetry(c *client) {
+	c.backoff = time.Millisecond
+}
+
+func status(code int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(code)
+		// 書き込めなければ、クライアント側の検証が失敗する
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+// =============================================================================
+// Slack
+// =============================================================================
+
+func TestSlackはusers_profile_setでステータスを設定して消す(t *testing.T) {
+	srv := newStubServer(t)
+	slack := NewSlack(srv.URL+"/", "xoxp-1")
+	until := time.Date(2024, 1, 15, 14, 25, 0, 0, time.Local)
+
+	if err := slack.Set(context.Background(), Focusing(until)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := slack.Clear(context.Background()); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+
+	if len(srv.requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(srv.requests))
+	}
+	set := srv.requests[0]
+	if set.method != http.MethodPost || set.path != "/api/users.profile.set" || set.auth != "Bearer xoxp-1" {
+		t.Errorf("request = %s %s (%s)", set.method, set.path, set.auth)
+	}
+	profile, _ := set.body["profile"].(map[string]any)
+	if profile["status_text"] != "focusing until 14:25" || profile["status_emoji"] != ":tomato:" {
+		t.Errorf("profile = %v", profile)
+	}
+	if profile["status_expiration"] != float64(until.Unix()) {
+		t.Errorf("status_expiration = %v, want %d", profile["status_expiration"], until.Unix())
+	}
+	cleared, _ := srv.requests[1].body["profile"].(map[string]any)
+	if cleared["status_text"] != "" || cleared["status_emoji"] != "" {
+		t.Errorf("cleared profile = %v", cleared)
+	}
+}
+
+func TestSlackはokがfalseならエラーにして再試行しない(t *testing.T) {
+	srv := newStubServer(t, status(http.StatusOK, `{"ok":false,"error":"invalid_auth"}`))
+	slack := NewSlack(srv.URL, "bad")
+	fastRetry(&slack.client)
+
+	err := slack.Clear(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_auth") {
+		t.Errorf("Clear() error = %v, want invalid_auth", err)
+	}
+	if len(srv.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(srv.requests))
+	}
+}
+
+// =============================================================================
+// Mattermost
+// =============================================================================
+
+func TestMattermostはカスタムステータスを設定して消す(t *testing.T) {
+	srv := newStubServer(t, status(http.StatusOK, `{"status":"OK"}`), status(http.StatusOK, `{"status":"OK"}`))
+	mm := NewMattermost(srv.URL, "mm-token")
+	until := time.Date(2024, 1, 15, 14, 25, 0, 0, time.UTC)
+
+	if err := mm.Set(context.Background(), Focusing(until)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := mm.Clear(context.Background()); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+
+	set := srv.requests[0]
+	if set.method != http.MethodPut || set.path != "/api/v4/users/me/status/custom" || set.auth != "Bearer mm-token" {
+		t.Errorf("request = %s %s (%s)", set.method, set.path, set.auth)
+	}
+	if set.body["emoji"] != "tomato" || set.body["expires_at"] != "2024-01-15T14:25:00Z" || set.body["duration"] != "date_and_time" {
+		t.Errorf("body = %v", set.body)
+	}
+	if clear := srv.requests[1]; clear.method != http.MethodDelete || clear.path != "/api/v4/users/me/status/custom" {
+		t.Errorf("clear request = %s %s", clear.method, clear.path)
+	}
+}
+
+// =============================================================================
+// 再試行
+// =============================================================================
+
+func Test一時的な失敗はバックオフして再試行する(t *testing.T) {
+	srv := newStubServer(t,
+		status(http.StatusServiceUnavailable, "maintenance"),
+		func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	)
+	mm := NewMattermost(srv.URL, "mm-token")
+	fastRetry(&mm.client)
+
+	if err := mm.Clear(context.Background()); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if len(srv.requests) != 3 {
+		t.Errorf("requests = %d, want 3", len(srv.requests))
+	}
+}
+
+func Test再試行の回数を超えたら最後のエラーを返す(t *testing.T) {
+	fail := status(http.StatusBadGateway, "down")
+	srv := newStubServer(t, fail, fail, fail, fail, fail)
+	mm := NewMattermost(srv.URL, "mm-token")
+	fastRetry(&mm.client)
+
+	err := mm.Clear(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "HTTP 502") {
+		t.Errorf("Clear() error = %v, want HTTP 502", err)
+	}
+	if len(srv.requests) != defaultAttempts {
+		t.Errorf("requests = %d, want %d", len(srv.requests), defaultAttempts)
+	}
+}
+
+func Testクライアントエラーは再試行しない(t *testing.T) {
+	srv := newStubServer(t, status(http.StatusUnauthorized, `{"message":"invalid token"}`))
+	mm := NewMattermost(srv.URL, "bad")
+	fastRetry(&mm.client)
+
+	if err := mm.Clear(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Clear() error = %v, want HTTP 401", err)
+	}
+	if len(srv.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(srv.requests))
+	}
+}
+
+func TestNewは必要な設定がなければエラーにする(t *testing.T) {
+	tests := []struct {
+		name, provider, url, token string
+		wantErr                    bool
+	}{
+		{"slackはURLを省略できる", "slack", "", "t", false},
+		{"mattermostはURLが必要", "mattermost", "", "t", true},
+		{"トークンが必要", "slack", "", "", true},
+		{"不明な種類", "teams", "https://example.com", "t", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.provider, tt.url, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
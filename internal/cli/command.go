@@ -0,0 +1,290 @@
+// Package cli はサブコマンドとGNU形式のフラグを持つコマンドラインの解析、
+// ヘルプの表示、シェル補完を提供する
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Command はコマンドツリーの1つのコマンド
+type Command struct {
+	Name string
+	// Usage は位置引数の書式（"KEY VALUE" など）
+	Usage string
+	// Summary はコマンド一覧に表示する1行の説明
+	Summary string
+	// Description はヘルプに表示する詳しい説明（省略可）
+	Description string
+	Hidden      bool
+	// RawArgs はサブコマンド名より後の引数をフラグとして解析せずにそのまま渡す
+	RawArgs bool
+
+	Flags    []*Flag
+	Commands []*Command
+
+	// Args は位置引数を検証する（nil なら任意の数を受け付ける）
+	Args func(args []string) error
+	// Run はコマンドを実行する（nil ならサブコマンドが必要）
+	Run func(args []string) error
+	// Complete は位置引数の補完候補を返す（args は入力済みの位置引数）
+	Complete func(args []string) []string
+
+	// Version はルートコマンドの --version で表示するバージョン
+	Version string
+	// Stdout はヘルプやバージョンの出力先（ルートコマンドの設定を使う、nil なら標準出力）
+	Stdout io.Writer
+
+	parent *Command
+}
+
+// UsageError はコマンドラインの誤りによるエラー
+type UsageError struct {
+	Command *Command
+	Err     error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Add はサブコマンドを追加する
+func (c *Command) Add(commands ...*Command) *Command {
+	for _, sub := range commands {
+		sub.parent = c
+		c.Commands = append(c.Commands, sub)
+	}
+	return c
+}
+
+// Path は "pomodoro config get" のようなコマンドの完全な名前を返す
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+// Root はルートコマンドを返す
+func (c *Command) Root() *Command {
+	for c.parent != nil {
+		c = c.parent
+	}
+	return c
+}
+
+// Find は名前が一致するサブコマンドを返す
+func (c *Command) Find(name string) *Command {
+	for _, sub := range c.Commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// VisibleCommands は隠していないサブコマンドを返す
+func (c *Command) VisibleCommands() []*Command {
+	var commands []*Command
+	for _, sub := range c.Commands {
+		if !sub.Hidden {
+			commands = append(commands, sub)
+		}
+	}
+	return commands
+}
+
+// InheritedFlags は親コマンドから引き継ぐフラグを返す
+func (c *Command) InheritedFlags() []*Flag {
+	var flags []*Flag
+	for p := c.parent; p != nil; p = p.parent {
+		for _, f := range p.Flags {
+			if f.Global {
+				flags = append(flags, f)
+			}
+		}
+	}
+	return flags
+}
+
+// lookup はこのコマンドで使えるフラグを長い名前か短い名前で探す
+func (c *Command) lookup(name string, short bool) *Flag {
+	for _, f := range slices.Concat(c.Flags, c.InheritedFlags()) {
+		if (short && f.Short == name) || (!short && f.Name == name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// stdout はヘルプやバージョンの出力先を返す
+func (c *Command) stdout() io.Writer {
+	if out := c.Root().Stdout; out != nil {
+		return out
+	}
+	return os.Stdout
+}
+
+// ----------------------------------------------------------------------------
+// 実行
+// ----------------------------------------------------------------------------
+
+// Execute はコマンドライン引数（プログラム名を除く）を解析してコマンドを実行する
+// フラグはサブコマンドの前後どちらにも書ける（"--" 以降はすべて位置引数）
+// -h/--help はそのコマンドのヘルプ、ルートの -v/--version はバージョンを表示する
+func (c *Command) Execute(args []string) error {
+	cmd, positional, err := c.parse(args)
+	if errors.Is(err, errHelp) {
+		return cmd.WriteHelp(cmd.stdout())
+	}
+	if errors.Is(err, errVersion) {
+		_, err := fmt.Fprintf(cmd.stdout(), "%s version %s\n", c.Name, c.Version)
+		return err
+	}
+	if err != nil {
+		return &UsageError{Command: cmd, Err: err}
+	}
+
+	if cmd.Run == nil {
+		if len(positional) > 0 {
+			return &UsageError{Command: cmd, Err: fmt.Errorf("unknown command %q for %q", positional[0], cmd.Path())}
+		}
+		return cmd.WriteHelp(cmd.stdout())
+	}
+	if cmd.Args != nil {
+		if err := cmd.Args(positional); err != nil {
+			if len(cmd.Commands) > 0 && len(positional) > 0 {
+				err = fmt.Errorf("unknown command %q for %q", positional[0], cmd.Path())
+			}
+			return &UsageError{Command: cmd, Err: err}
+		}
+	}
+	return cmd.Run(positional)
+}
+
+var (
+	errHelp    = errors.New("help requested")
+	errVersion = errors.New("version requested")
+)
+
+// parse は実行するコマンドと位置引数を求め、フラグの値を設定する
+func (c *Command) parse(args []string) (cmd *Command, positional []string, err error) {
+	cmd = c
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return cmd, append(positional, args[i+1:]...), nil
+		case arg == "-h" || arg == "--help":
+			return cmd, nil, errHelp
+		case (arg == "-v" || arg == "--version") && c.Version != "" && cmd.lookup(strings.TrimLeft(arg, "-"), arg == "-v") == nil:
+			return cmd, nil, errVersion
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			f := cmd.lookup(name, false)
+			if f == nil {
+				return cmd, nil, fmt.Errorf("unknown flag --%s", name)
+			}
+			if !hasValue {
+				if f.takesValue() {
+					if i+1 >= len(args) {
+						return cmd, nil, fmt.Errorf("flag --%s needs a value", name)
+					}
+					i++
+					value = args[i]
+				} else {
+					value = "true"
+				}
+			}
+			if err := f.set(value); err != nil {
+				return cmd, nil, err
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			consumed, err := cmd.parseShort(arg[1:], args[i+1:])
+			if err != nil {
+				return cmd, nil, err
+			}
+			i += consumed
+		default:
+			// 位置引数より前ならサブコマンドとして扱う
+			if len(positional) == 0 {
+				if sub := cmd.Find(arg); sub != nil {
+					cmd = sub
+					if cmd.RawArgs {
+						return cmd, args[i+1:], nil
+					}
+					continue
+				}
+			}
+			positional = append(positional, arg)
+		}
+	}
+	return cmd, positional, nil
+}
+
+// parseShort は -x、-xVALUE、-x VALUE、-abc の形の短いフラグを解析する
+// 次の引数を値として使った場合は 1 を返す
+func (c *Command) parseShort(shorts string, rest []string) (consumed int, err error) {
+	for j := 0; j < len(shorts); j++ {
+		name := shorts[j : j+1]
+		f := c.lookup(name, true)
+		if f == nil {
+			return 0, fmt.Errorf("unknown flag -%s", name)
+		}
+		if !f.takesValue() {
+			if err := f.set("true"); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		// 値を取るフラグは残りの文字列か次の引数を値にする
+		if value := strings.TrimPrefix(shorts[j+1:], "="); j+1 < len(shorts) {
+			return 0, f.set(value)
+		}
+		if len(rest) == 0 {
+			return 0, fmt.Errorf("flag -%s needs a value", name)
+		}
+		return 1, f.set(rest[0])
+	}
+	return 0, nil
+}
+
+// ----------------------------------------------------------------------------
+// 位置引数の検証
+// ----------------------------------------------------------------------------
+
+// NoArgs は位置引数を受け付けない
+func NoArgs(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected argument %q", args[0])
+	}
+	return nil
+}
+
+// ExactArgs は n 個の位置引数を必要とする
+func ExactArgs(n int) func(args []string) error {
+	return func(args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("expected %d argument(s), got %d", n, len(args))
+		}
+		return nil
+	}
+}
+
+// OneOf は1つの位置引数が values のいずれかであることを必要とする
+func OneOf(values ...string) func(args []string) error {
+	return func(args []string) error {
+		if len(args) != 1 || !slices.Contains(values, args[0]) {
+			return fmt.Errorf("expected one of: %s", strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCommand はテスト用のコマンドツリーと、実行されたコマンドの記録を返す
+type testCommand struct {
+	root    *Command
+	out     *bytes.Buffer
+	ran     string
+	args    []string
+	work    time.Duration
+	profile string
+	quiet   bool
+	verbose bool
+	format  string
+}
+
+func newTestCommand() *testCommand {
+	tc := &testCommand{out: &bytes.Buffer{}}
+	record := func(name string) func([]string) error {
+		return func(args []string) error {
+			tc.ran, tc.args = name, args
+			return nil
+		}
+	}
+	tc.root = &Command{
+		Name:    "app",
+		Summary: "Test application",
+		Version: "1.2.3",
+		Stdout:  tc.out,
+		Args:    NoArgs,
+		Run:     record("app"),
+		Flags: []*Flag{
+			Duration(&tc.work, "work", "w", 0, "Work duration").Inherited(),
+			String(&tc.profile, "profile", "p", "", "Profile").Placeholder("NAME").Inherited(),
+			Bool(&tc.quiet, "quiet", "q", "Quiet").Inherited(),
+		},
+	}
+	export := &Command{
+		Name:    "export",
+		Summary: "Export data",
+		Args:    NoArgs,
+		Run:     record("export"),
+		Flags: []*Flag{
+			String(&tc.format, "format", "f", "csv", "Output format").Values("csv", "json"),
+			Bool(&tc.verbose, "verbose", "V", "Verbose"),
+		},
+	}
+	config := &Command{Name: "config", Summary: "Configuration"}
+	config.Add(
+		&Command{Name: "set", Usage: "KEY VALUE", Summary: "Set a value", Args: ExactArgs(2), Run: record("config set")},
+		&Command{Name: "secret", Summary: "Hidden", Hidden: true, Run: record("config secret")},
+	)
+	tc.root.Add(export, config)
+	return tc
+}
+
+// =============================================================================
+// Execute - コマンドとフラグの解析
+// =============================================================================
+
+func TestExecuteはサブコマンドの前後のフラグを受け付ける(t *testing.T) {
+	tc := newTestCommand()
+	err := tc.root.Execute([]string{"-w", "10m", "export", "--format=json", "-p", "deep", "-qV"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if tc.ran != "export" {
+		t.Errorf("ran = %q, want export", tc.ran)
+	}
+	if tc.work != 10*time.Minute || tc.format != "json" || tc.profile != "deep" || !tc.quiet || !tc.verbose {
+		t.Errorf("flags = %v %q %q %v %v", tc.work, tc.format, tc.profile, tc.quiet, tc.verbose)
+	}
+}
+
+func TestExecuteは短いフラグの値の書き方をすべて受け付ける(t *testing.T) {
+	for _, args := range [][]string{
+		{"-w", "5m"}, {"-w5m"}, {"-w=5m"}, {"--work", "5m"}, {"--work=5m"}, {"-qw5m"},
+	} {
+		tc := newTestCommand()
+		if err := tc.root.Execute(args); err != nil {
+			t.Errorf("Execute(%q) error = %v", args, err)
+			continue
+		}
+		if tc.work != 5*time.Minute {
+			t.Errorf("Execute(%q) work = %v, want 5m", args, tc.work)
+		}
+	}
+}
+
+func TestExecuteは位置引数とダブルダッシュ以降を渡す(t *testing.T) {
+	tc := newTestCommand()
+	if err := tc.root.Execute([]string{"config", "set", "tags", "--", "-x"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if tc.ran != "config set" || strings.Join(tc.args, " ") != "tags -x" {
+		t.Errorf("ran %q with %q", tc.ran, tc.args)
+	}
+}
+
+func TestExecuteは誤りをUsageErrorで返す(t *testing.T) {
+	tests := []struct {
+		args     []string
+		command  string
+		expected string
+	}{
+		{[]string{"bogus"}, "app", `unknown command "bogus" for "app"`},
+		{[]string{"config", "bogus"}, "app config", `unknown command "bogus" for "app config"`},
+		{[]string{"export", "--bogus"}, "app export", "unknown flag --bogus"},
+		{[]string{"-x"}, "app", "unknown flag -x"},
+		{[]string{"export", "--format"}, "app export", "flag --format needs a value"},
+		{[]string{"-w", "soon"}, "app", `invalid value "soon" for --work`},
+		{[]string{"config", "set", "key"}, "app config set", "expected 2 argument(s), got 1"},
+	}
+	for _, tt := range tests {
+		tc := newTestCommand()
+		err := tc.root.Execute(tt.args)
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("Execute(%q) error = %v, want UsageError", tt.args, err)
+			continue
+		}
+		if usageErr.Command.Path() != tt.command || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Execute(%q) = %q on %q, want %q on %q", tt.args, err, usageErr.Command.Path(), tt.expected, tt.command)
+		}
+	}
+}
+
+func TestExecuteはRunのないコマンドでヘルプを表示する(t *testing.T) {
+	tc := newTestCommand()
+	if err := tc.root.Execute([]string{"config"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(tc.out.String(), "Usage:\n  app config [flags] COMMAND") {
+		t.Errorf("output = %q", tc.out.String())
+	}
+}
+
+func TestExecuteはバージョンを表示する(t *testing.T) {
+	tc := newTestCommand()
+	if err := tc.root.Execute([]string{"--version"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if tc.out.String() != "app version 1.2.3\n" || tc.ran != "" {
+		t.Errorf("output = %q, ran = %q", tc.out.String(), tc.ran)
+	}
+}
+
+// =============================================================================
+// WriteHelp - ヘルプの表示
+// =============================================================================
+
+func TestWriteHelpはコマンドとフラグと引き継いだフラグを表示する(t *testing.T) {
+	tc := newTestCommand()
+	if err := tc.root.Execute([]string{"export", "--help"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	output := tc.out.String()
+	for _, expected := range []string{
+		"Export data",
+		"Usage:\n  app export [flags]",
+		"-f, --format VALUE",
+		"-V, --verbose ",
+		"-h, --help",
+		"Global flags:",
+		"-w, --work DURATION",
+		"-p, --profile NAME",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("help missing %q:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "--version") {
+		t.Errorf("subcommand help should not list --version:\n%s", output)
+	}
+}
+
+func TestWriteHelpは隠しコマンドを表示しない(t *testing.T) {
+	tc := newTestCommand()
+	if err := tc.root.Execute([]string{"config", "-h"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	output := tc.out.String()
+	if !strings.Contains(output, "set  Set a value") || strings.Contains(output, "secret") {
+		t.Errorf("help = %q", output)
+	}
+	if !strings.Contains(output, "Run 'app config COMMAND --help'") {
+		t.Errorf("help missing hint:\n%s", output)
+	}
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Candidate は補完候補
+type Candidate struct {
+	Value       string
+	Description string
+}
+
+// Completions は入力中のコマンドライン（プログラム名を除く、最後が入力中の単語）の補完候補を返す
+// サブコマンド名、フラグ名、フラグの値、位置引数を補完する
+func (c *Command) Completions(words []string) []Candidate {
+	words = slices.Clone(words)
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	// bash は "--format=csv" を "--format" "=" "csv" に分けるので "=" を取り除く
+	if words[len(words)-1] == "=" {
+		words[len(words)-1] = ""
+	}
+	words = slices.DeleteFunc(words, func(w string) bool { return w == "=" })
+	current, previous := words[len(words)-1], words[:len(words)-1]
+
+	cmd := c
+	var positional []string
+	var pending *Flag // 値を入力中のフラグ
+	for i := 0; i < len(previous); i++ {
+		word := previous[i]
+		pending = nil
+		var f *Flag
+		switch {
+		case word == "--":
+			positional = append(positional, previous[i+1:]...)
+			i = len(previous)
+			continue
+		case strings.HasPrefix(word, "--"):
+			name, _, hasValue := strings.Cut(word[2:], "=")
+			if !hasValue {
+				f = cmd.lookup(name, false)
+			}
+		case strings.HasPrefix(word, "-") && len(word) == 2:
+			f = cmd.lookup(word[1:], true)
+		case strings.HasPrefix(word, "-"):
+			// -w10m のように値を含む短いフラグ
+		default:
+			if len(positional) == 0 {
+				if sub := cmd.Find(word); sub != nil {
+					cmd = sub
+					continue
+				}
+			}
+			positional = append(positional, word)
+		}
+		if f != nil && f.takesValue() {
+			if i+1 < len(previous) {
+				i++
+			} else {
+				pending = f
+			}
+		}
+	}
+
+	var candidates []Candidate
+	switch {
+	case pending != nil:
+		if pending.Complete != nil {
+			for _, v := range pending.Complete() {
+				candidates = append(candidates, Candidate{Value: v})
+			}
+		}
+	case strings.HasPrefix(current, "--") && strings.Contains(current, "="):
+		name, _, _ := strings.Cut(current[2:], "=")
+		if f := cmd.lookup(name, false); f != nil && f.Complete != nil {
+			for _, v := range f.Complete() {
+				candidates = append(candidates, Candidate{Value: "--" + name + "=" + v})
+			}
+		}
+	case strings.HasPrefix(current, "-"):
+		for _, f := range visibleFlags(slices.Concat(cmd.Flags, cmd.InheritedFlags())) {
+			candidates = append(candidates, Candidate{Value: "--" + f.Name, Description: f.Usage})
+		}
+		candidates = append(candidates, Candidate{Value: "--help", Description: "Show help"})
+	default:
+		if len(positional) == 0 {
+			for _, sub := range cmd.VisibleCommands() {
+				candidates = append(candidates, Candidate{Value: sub.Name, Description: sub.Summary})
+			}
+		}
+		if cmd.Complete != nil {
+			for _, v := range cmd.Complete(positional) {
+				candidates = append(candidates, Candidate{Value: v})
+			}
+		}
+	}
+
+	return slices.DeleteFunc(candidates, func(candidate Candidate) bool {
+		return !strings.HasPrefix(candidate.Value, current)
+	})
+}
+
+// ----------------------------------------------------------------------------
+// 補完スクリプト
+// ----------------------------------------------------------------------------
+
+// Shells は補完スクリプトを生成できるシェル
+var Shells = []string{"bash", "zsh", "fish"}
+
+// completeCommand は補完スクリプトから呼び出す隠しコマンドの名前
+const completeCommand = "__complete"
+
+// CompletionCommands は補完スクリプトを出力する completion コマンドと、
+// スクリプトから呼び出されて候補を返す隠しコマンドを返す
+func CompletionCommands(root *Command) []*Command {
+	completion := &Command{
+		Name:    "completion",
+		Usage:   "SHELL",
+		Summary: "Print the shell completion script (bash, zsh or fish)",
+		Description: strings.ReplaceAll(`Print the shell completion script (bash, zsh or fish).
+
+  bash:  source <(PROGRAM completion bash)
+  zsh:   source <(PROGRAM completion zsh)
+  fish:  PROGRAM completion fish | source`, "PROGRAM", root.Name),
+		Args:     OneOf(Shells...),
+		Complete: func(args []string) []string { return completeFirst(args, Shells) },
+		Run: func(args []string) error {
+			return WriteCompletionScript(root.stdout(), args[0], root.Name)
+		},
+	}
+	complete := &Command{
+		Name:    completeCommand,
+		Summary: "Print completion candidates for the given words",
+		Hidden:  true,
+		RawArgs: true,
+		Run: func(args []string) error {
+			out := root.stdout()
+			for _, candidate := range root.Completions(args) {
+				if _, err := fmt.Fprintf(out, "%s\t%s\n", candidate.Value, candidate.Description); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	return []*Command{completion, complete}
+}
+
+// completeFirst は最初の位置引数だけを values から補完する
+func completeFirst(args, values []string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	return values
+}
+
+// WriteCompletionScript は shell 用の補完スクリプトを書き出す
+func WriteCompletionScript(w io.Writer, shell, program string) error {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashCompletion
+	case "zsh":
+		script = zshCompletion
+	case "fish":
+		script = fishCompletion
+	default:
+		return fmt.Errorf("unsupported shell %q (use %s)", shell, strings.Join(Shells, ", "))
+	}
+	script = strings.NewReplacer("PROGRAM", program, "COMPLETE", completeCommand).Replace(script)
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+const bashCompletion = `# bash completion for PROGRAM
+_PROGRAM() {
+    local candidate
+    COMPREPLY=()
+    while IFS= read -r candidate; do
+        COMPREPLY+=("$(printf '%q' "${candidate%%$'\t'*}")")
+    done < <(PROGRAM COMPLETE "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+}
+complete -o default -F _PROGRAM PROGRAM
+`
+
+const zshCompletion = `#compdef PROGRAM
+# zsh completion for PROGRAM
+_PROGRAM() {
+    local -a lines candidates
+    local line
+    lines=("${(@f)$(PROGRAM COMPLETE "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    for line in $lines; do
+        [[ -z $line ]] && continue
+        candidates+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+    done
+    if (( ${#candidates} )); then
+        _describe 'PROGRAM' candidates
+    else
+        _files
+    fi
+}
+compdef _PROGRAM PROGRAM
+`
+
+const fishCompletion = `# fish completion for PROGRAM
+function __PROGRAM_complete
+    set -l tokens (commandline -opc)
+    set -l current (commandline -ct)
+    PROGRAM COMPLETE $tokens[2..-1] "$current" 2>/dev/null
+end
+complete -c PROGRAM -f -a '(__PROGRAM_complete)'
+`
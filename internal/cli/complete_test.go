@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// values は補完候補の値だけを返す
+func values(candidates []Candidate) []string {
+	var vs []string
+	for _, c := range candidates {
+		vs = append(vs, c.Value)
+	}
+	return vs
+}
+
+// =============================================================================
+// Completions - 補完候補
+// =============================================================================
+
+func TestCompletionsはコマンドとフラグと値を補完する(t *testing.T) {
+	tests := []struct {
+		words    []string
+		expected []string
+	}{
+		{[]string{""}, []string{"export", "config"}},
+		{[]string{"ex"}, []string{"export"}},
+		{[]string{"config", ""}, []string{"set"}},
+		{[]string{"-w", "10m", "con"}, []string{"config"}},
+		{[]string{"export", "--f"}, []string{"--format"}},
+		{[]string{"export", "--format", ""}, []string{"csv", "json"}},
+		{[]string{"export", "-f", "j"}, []string{"json"}},
+		{[]string{"export", "--format=j"}, []string{"--format=json"}},
+		{[]string{"export", "--format", "=", ""}, []string{"csv", "json"}},
+		{[]string{"export", "--format", "csv", "--v"}, []string{"--verbose"}},
+	}
+	for _, tt := range tests {
+		got := values(newTestCommand().root.Completions(tt.words))
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("Completions(%q) = %q, want %q", tt.words, got, tt.expected)
+		}
+	}
+}
+
+func TestCompletionsは引き継いだフラグとhelpを含める(t *testing.T) {
+	got := values(newTestCommand().root.Completions([]string{"export", "--"}))
+	for _, want := range []string{"--format", "--verbose", "--work", "--profile", "--help"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Completions() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestCompletionsは位置引数をCompleteで補完する(t *testing.T) {
+	tc := newTestCommand()
+	set := tc.root.Find("config").Find("set")
+	set.Complete = func(args []string) []string { return completeFirst(args, []string{"work", "tags"}) }
+
+	if got := values(tc.root.Completions([]string{"config", "set", "w"})); !slices.Equal(got, []string{"work"}) {
+		t.Errorf("Completions(first) = %q", got)
+	}
+	if got := values(tc.root.Completions([]string{"config", "set", "work", ""})); len(got) != 0 {
+		t.Errorf("Completions(second) = %q, want none", got)
+	}
+}
+
+// =============================================================================
+// CompletionCommands - 補完スクリプトと候補の出力
+// =============================================================================
+
+func TestCompletionCommandsはスクリプトと候補を出力する(t *testing.T) {
+	tc := newTestCommand()
+	tc.root.Add(CompletionCommands(tc.root)...)
+
+	for _, shell := range Shells {
+		tc.out.Reset()
+		if err := tc.root.Execute([]string{"completion", shell}); err != nil {
+			t.Fatalf("completion %s error = %v", shell, err)
+		}
+		if !strings.Contains(tc.out.String(), "app __complete") {
+			t.Errorf("%s script does not call app __complete:\n%s", shell, tc.out.String())
+		}
+	}
+
+	tc.out.Reset()
+	if err := tc.root.Execute([]string{"__complete", "export", "--format", ""}); err != nil {
+		t.Fatalf("__complete error = %v", err)
+	}
+	if tc.out.String() != "csv\t\njson\t\n" {
+		t.Errorf("__complete output = %q", tc.out.String())
+	}
+
+	// 隠しコマンドは補完にもヘルプにも出さない
+	if got := values(tc.root.Completions([]string{"__"})); len(got) != 0 {
+		t.Errorf("Completions(__) = %q", got)
+	}
+	var help bytes.Buffer
+	if err := tc.root.WriteHelp(&help); err != nil || strings.Contains(help.String(), "__complete") {
+		t.Errorf("help lists hidden command: %v\n%s", err, help.String())
+	}
+}
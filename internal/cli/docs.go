@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// DocCommands はコマンドのヘルプを表示する help コマンドと、
+// man ページやMarkdownのリファレンスを出力する隠しコマンド gen-man を返す
+func DocCommands(root *Command) []*Command {
+	help := &Command{
+		Name:    "help",
+		Usage:   "[COMMAND...]",
+		Summary: "Show help for a command",
+		Complete: func(args []string) []string {
+			cmd := root
+			for _, name := range args {
+				if cmd = cmd.Find(name); cmd == nil {
+					return nil
+				}
+			}
+			var names []string
+			for _, sub := range cmd.VisibleCommands() {
+				names = append(names, sub.Name)
+			}
+			return names
+		},
+		Run: func(args []string) error {
+			cmd := root
+			for _, name := range args {
+				sub := cmd.Find(name)
+				if sub == nil {
+					return &UsageError{Command: root, Err: fmt.Errorf("unknown command %q for %q", name, cmd.Path())}
+				}
+				cmd = sub
+			}
+			return cmd.WriteHelp(root.stdout())
+		},
+	}
+
+	var markdown bool
+	genMan := &Command{
+		Name:    "gen-man",
+		Summary: "Print the man page (or a Markdown reference) for all commands",
+		Hidden:  true,
+		Args:    NoArgs,
+		Flags: []*Flag{
+			Bool(&markdown, "markdown", "", "Print a Markdown reference instead of roff"),
+		},
+		Run: func([]string) error {
+			if markdown {
+				return root.WriteMarkdown(root.stdout())
+			}
+			return root.WriteMan(root.stdout())
+		},
+	}
+	return []*Command{help, genMan}
+}
+
+// documented は man ページやリファレンスに載せるコマンドを深さ優先の順に返す
+func (c *Command) documented() []*Command {
+	var commands []*Command
+	for _, sub := range c.VisibleCommands() {
+		commands = append(commands, sub)
+		commands = append(commands, sub.documented()...)
+	}
+	return commands
+}
+
+// description はヘルプに表示する説明を返す（Description がなければ Summary）
+func (c *Command) description() string {
+	if c.Description != "" {
+		return strings.TrimSpace(c.Description)
+	}
+	return c.Summary
+}
+
+// ----------------------------------------------------------------------------
+// man ページ
+// ----------------------------------------------------------------------------
+
+// WriteMan はコマンドツリー全体を1つの roff 形式の man ページ（セクション1）として書き出す
+// 日付は入れない（生成結果をリポジトリに置いて差分を確認できるようにするため）
+func (c *Command) WriteMan(w io.Writer) error {
+	var b strings.Builder
+	title := strings.ToUpper(c.Name)
+	fmt.Fprintf(&b, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n", title, c.Name, c.Version)
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", c.Name, roffEscape(c.Summary))
+	b.WriteString(".SH SYNOPSIS\n")
+	writeManSynopsis(&b, c)
+	b.WriteString(".SH DESCRIPTION\n")
+	writeManText(&b, c.description())
+
+	b.WriteString(".SH OPTIONS\n")
+	for _, f := range visibleFlags(c.Flags) {
+		writeManFlag(&b, f)
+	}
+	fmt.Fprintf(&b, ".TP\n%s\nShow help\n", manFlagTerm("h", "help", ""))
+	if c.Version != "" {
+		fmt.Fprintf(&b, ".TP\n%s\nShow version\n", manFlagTerm("v", "version", ""))
+	}
+
+	if commands := c.documented(); len(commands) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, sub := range commands {
+			fmt.Fprintf(&b, ".SS \"%s\"\n", roffEscape(strings.TrimPrefix(sub.Path(), c.Name+" ")))
+			writeManSynopsis(&b, sub)
+			b.WriteString(".PP\n")
+			writeManText(&b, sub.description())
+			for _, f := range visibleFlags(sub.Flags) {
+				writeManFlag(&b, f)
+			}
+		}
+	}
+
+	if slices.ContainsFunc(visibleFlags(c.Flags), func(f *Flag) bool { return f.Global }) {
+		b.WriteString(".SH NOTES\n")
+		fmt.Fprintf(&b, "The options of \\fB%s\\fR are accepted by every command, before or after the command name.\n", c.Name)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeManSynopsis はコマンドの書式を太字のコマンド名と引数の形で書き出す
+func writeManSynopsis(b *strings.Builder, c *Command) {
+	usage := strings.TrimPrefix(c.UsageLine(), c.Path())
+	fmt.Fprintf(b, ".B %s\n%s\n", roffEscape(c.Path()), roffEscape(strings.TrimSpace(usage)))
+}
+
+// writeManText は空行で区切られた段落を書き出す
+// 字下げされた行（コマンド例など）はそのまま表示する
+func writeManText(b *strings.Builder, text string) {
+	for i, paragraph := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			b.WriteString(".PP\n")
+		}
+		lines := strings.Split(paragraph, "\n")
+		if strings.HasPrefix(lines[0], " ") {
+			b.WriteString(".nf\n")
+			for _, line := range lines {
+				b.WriteString(roffEscape(strings.TrimSpace(line)) + "\n")
+			}
+			b.WriteString(".fi\n")
+			continue
+		}
+		for _, line := range lines {
+			b.WriteString(roffEscape(line) + "\n")
+		}
+	}
+}
+
+// writeManFlag はフラグを .TP の項目として書き出す
+func writeManFlag(b *strings.Builder, f *Flag) {
+	valueName := ""
+	if f.takesValue() {
+		valueName = f.ValueName
+	}
+	fmt.Fprintf(b, ".TP\n%s\n%s\n", manFlagTerm(f.Short, f.Name, valueName), roffEscape(f.Usage))
+}
+
+// manFlagTerm は太字のフラグ名と斜体の値の名前（"\fB\-w\fR, \fB\-\-work\fR \fIDURATION\fR"）を返す
+func manFlagTerm(short, name, valueName string) string {
+	term := `\fB\-\-` + roffEscape(name) + `\fR`
+	if short != "" {
+		term = `\fB\-` + roffEscape(short) + `\fR, ` + term
+	}
+	if valueName != "" {
+		term += ` \fI` + roffEscape(valueName) + `\fR`
+	}
+	return term
+}
+
+// roffEscape は roff で特別な意味を持つ文字をエスケープする
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// ----------------------------------------------------------------------------
+// Markdown
+// ----------------------------------------------------------------------------
+
+// WriteMarkdown はコマンドツリー全体のリファレンスをMarkdownで書き出す
+func (c *Command) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", c.Name)
+	b.WriteString("<!-- Generated by `" + c.Name + " gen-man --markdown`. Do not edit. -->\n\n")
+	b.WriteString(markdownText(c.description()) + "\n\n")
+	fmt.Fprintf(&b, "```\n%s\n```\n\n", c.UsageLine())
+
+	b.WriteString("## Flags\n\n")
+	if slices.ContainsFunc(visibleFlags(c.Flags), func(f *Flag) bool { return f.Global }) {
+		b.WriteString("These flags are accepted by every command, before or after the command name.\n\n")
+	}
+	writeMarkdownFlags(&b, c.Flags, c.Version != "")
+
+	for _, sub := range c.documented() {
+		fmt.Fprintf(&b, "\n## %s\n\n", sub.Path())
+		b.WriteString(markdownText(sub.description()) + "\n\n")
+		fmt.Fprintf(&b, "```\n%s\n```\n", sub.UsageLine())
+		if flags := visibleFlags(sub.Flags); len(flags) > 0 {
+			b.WriteString("\n")
+			writeMarkdownFlags(&b, flags, false)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownFlags はフラグの表を書き出す
+func writeMarkdownFlags(b *strings.Builder, flags []*Flag, withVersion bool) {
+	b.WriteString("| Flag | Description |\n|------|-------------|\n")
+	for _, f := range visibleFlags(flags) {
+		fmt.Fprintf(b, "| `%s` | %s |\n", strings.TrimSpace(FlagColumn(f)), strings.ReplaceAll(f.Usage, "|", `\|`))
+	}
+	fmt.Fprintf(b, "| `%s` | Show help |\n", helpFlagColumn)
+	if withVersion {
+		fmt.Fprintf(b, "| `%s` | Show version |\n", versionFlagColumn)
+	}
+}
+
+// markdownText は字下げされた行をコードブロックにする
+func markdownText(text string) string {
+	paragraphs := strings.Split(text, "\n\n")
+	for i, paragraph := range paragraphs {
+		lines := strings.Split(paragraph, "\n")
+		if !strings.HasPrefix(lines[0], " ") {
+			continue
+		}
+		for j, line := range lines {
+			lines[j] = strings.TrimSpace(line)
+		}
+		paragraphs[i] = "```\n" + strings.Join(lines, "\n") + "\n```"
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
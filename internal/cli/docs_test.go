@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// WriteMan / WriteMarkdown - ドキュメントの生成
+// =============================================================================
+
+func TestWriteManはコマンドとフラグをroffで書き出す(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestCommand().root.WriteMan(&out); err != nil {
+		t.Fatalf("WriteMan() error = %v", err)
+	}
+	man := out.String()
+	for _, expected := range []string{
+		`.TH APP 1 "" "app 1.2.3" "User Commands"`,
+		`app \- Test application`,
+		".TP\n" + `\fB\-w\fR, \fB\-\-work\fR \fIDURATION\fR` + "\nWork duration\n",
+		`.SS "config set"` + "\n.B app config set\n[flags] KEY VALUE\n",
+		`\fB\-f\fR, \fB\-\-format\fR \fIVALUE\fR`,
+		`\fB\-V\fR, \fB\-\-verbose\fR` + "\n",
+	} {
+		if !strings.Contains(man, expected) {
+			t.Errorf("man page missing %q:\n%s", expected, man)
+		}
+	}
+	if strings.Contains(man, "secret") {
+		t.Errorf("man page documents a hidden command:\n%s", man)
+	}
+}
+
+func TestWriteMarkdownはコマンドごとにフラグの表を書き出す(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestCommand().root.WriteMarkdown(&out); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	markdown := out.String()
+	for _, expected := range []string{
+		"# app\n",
+		"| `-w, --work DURATION` | Work duration |",
+		"| `-v, --version` | Show version |",
+		"## app export\n\nExport data\n\n```\napp export [flags]\n```\n",
+		"| `-f, --format VALUE` | Output format |",
+		"## app config set\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("markdown missing %q:\n%s", expected, markdown)
+		}
+	}
+}
+
+func TestRoffEscapeは行頭のドットとバックスラッシュとハイフンをエスケープする(t *testing.T) {
+	tests := []struct{ input, expected string }{
+		{"auto-start", `auto\-start`},
+		{`C:\path`, `C:\epath`},
+		{".pomodoro.toml", `\&.pomodoro.toml`},
+	}
+	for _, tt := range tests {
+		if got := roffEscape(tt.input); got != tt.expected {
+			t.Errorf("roffEscape(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Value はフラグの値（標準の flag.Value と同じ形）
+type Value interface {
+	String() string
+	Set(string) error
+}
+
+// Flag はコマンドのフラグ
+// --name VALUE、--name=VALUE、-x VALUE、-xVALUE の形で指定できる
+// 値を取らないフラグ（bool）は -abc のようにまとめて指定できる
+type Flag struct {
+	// Name は "--" を除いた長い名前
+	Name string
+	// Short は "-" を除いた1文字の短い名前（なければ空文字列）
+	Short string
+	Usage string
+	Value Value
+	// ValueName はヘルプに表示する値の名前（"DURATION" など）
+	ValueName string
+	// Global はサブコマンドにも引き継ぐフラグ
+	Global bool
+	Hidden bool
+	// Complete は値の補完候補を返す
+	Complete func() []string
+
+	isBool  bool
+	changed bool
+}
+
+// Changed はコマンドラインで指定されたかを返す
+func (f *Flag) Changed() bool {
+	return f.changed
+}
+
+// Placeholder はヘルプに表示する値の名前を設定する
+func (f *Flag) Placeholder(name string) *Flag {
+	f.ValueName = name
+	return f
+}
+
+// Completion は値の補完候補を返す関数を設定する
+func (f *Flag) Completion(complete func() []string) *Flag {
+	f.Complete = complete
+	return f
+}
+
+// Values は値の補完候補を固定の一覧にする
+func (f *Flag) Values(values ...string) *Flag {
+	return f.Completion(func() []string { return values })
+}
+
+// Inherited はサブコマンドにも引き継ぐフラグにする
+func (f *Flag) Inherited() *Flag {
+	f.Global = true
+	return f
+}
+
+// takesValue は値を取るフラグかを返す
+func (f *Flag) takesValue() bool {
+	return !f.isBool
+}
+
+// set はコマンドラインの値を設定する
+func (f *Flag) set(value string) error {
+	if err := f.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", value, f.display(), err)
+	}
+	f.changed = true
+	return nil
+}
+
+// display はエラーメッセージ用のフラグ名（--name）
+func (f *Flag) display() string {
+	return "--" + f.Name
+}
+
+// ----------------------------------------------------------------------------
+// フラグの作成
+// ----------------------------------------------------------------------------
+
+// String は文字列のフラグを作成する
+func String(p *string, name, short, value, usage string) *Flag {
+	*p = value
+	return &Flag{Name: name, Short: short, Usage: usage, Value: (*stringValue)(p), ValueName: "VALUE"}
+}
+
+// Bool は値を取らないフラグを作成する（指定すると true になる）
+func Bool(p *bool, name, short, usage string) *Flag {
+	return &Flag{Name: name, Short: short, Usage: usage, Value: (*boolValue)(p), isBool: true}
+}
+
+// Int は整数のフラグを作成する
+func Int(p *int, name, short string, value int, usage string) *Flag {
+	*p = value
+	return &Flag{Name: name, Short: short, Usage: usage, Value: (*intValue)(p), ValueName: "N"}
+}
+
+// Duration は時間のフラグを作成する（"25m" のような Go の時間の書式）
+func Duration(p *time.Duration, name, short string, value time.Duration, usage string) *Flag {
+	*p = value
+	return &Flag{Name: name, Short: short, Usage: usage, Value: (*durationValue)(p), ValueName: "DURATION"}
+}
+
+type stringValue string
+
+func (v *stringValue) String() string { return string(*v) }
+
+func (v *stringValue) Set(s string) error {
+	*v = stringValue(s)
+	return nil
+}
+
+type boolValue bool
+
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("expected true or false")
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("expected an integer")
+	}
+	*v = intValue(n)
+	return nil
+}
+
+type durationValue time.Duration
+
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+
+func (v *durationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("expected a duration like 25m or 1h30m")
+	}
+	*v = durationValue(d)
+	return nil
+}
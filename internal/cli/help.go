@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteHelp はコマンドのヘルプを書き出す
+func (c *Command) WriteHelp(w io.Writer) error {
+	var b strings.Builder
+	if description := c.description(); description != "" {
+		b.WriteString(description + "\n\n")
+	}
+
+	b.WriteString("Usage:\n")
+	b.WriteString("  " + c.UsageLine() + "\n")
+
+	if commands := c.VisibleCommands(); len(commands) > 0 {
+		b.WriteString("\nCommands:\n")
+		width := 0
+		for _, sub := range commands {
+			width = max(width, len(sub.Name))
+		}
+		for _, sub := range commands {
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, sub.Name, sub.Summary)
+		}
+	}
+
+	local := visibleFlags(c.Flags)
+	inherited := visibleFlags(c.InheritedFlags())
+	width := max(flagColumnWidth(local), flagColumnWidth(inherited), len(helpFlagColumn))
+	b.WriteString("\nFlags:\n")
+	for _, f := range local {
+		writeFlag(&b, FlagColumn(f), f.Usage, width)
+	}
+	writeFlag(&b, helpFlagColumn, "Show help", width)
+	if c.parent == nil && c.Version != "" {
+		writeFlag(&b, versionFlagColumn, "Show version", width)
+	}
+	if len(inherited) > 0 {
+		b.WriteString("\nGlobal flags:\n")
+		for _, f := range inherited {
+			writeFlag(&b, FlagColumn(f), f.Usage, width)
+		}
+	}
+
+	if len(c.VisibleCommands()) > 0 {
+		fmt.Fprintf(&b, "\nRun '%s COMMAND --help' for more information on a command.\n", c.Path())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// UsageLine は "pomodoro config set [flags] KEY VALUE" のような書式を返す
+func (c *Command) UsageLine() string {
+	parts := []string{c.Path(), "[flags]"}
+	if len(c.VisibleCommands()) > 0 {
+		if c.Run != nil {
+			parts = append(parts, "[command]")
+		} else {
+			parts = append(parts, "COMMAND")
+		}
+	}
+	if c.Usage != "" {
+		parts = append(parts, c.Usage)
+	}
+	return strings.Join(parts, " ")
+}
+
+// FlagColumn はヘルプのフラグ列（"-w, --work DURATION" など）を返す
+func FlagColumn(f *Flag) string {
+	s := "    --" + f.Name
+	if f.Short != "" {
+		s = "-" + f.Short + ", --" + f.Name
+	}
+	if f.takesValue() {
+		s += " " + f.ValueName
+	}
+	return s
+}
+
+const (
+	helpFlagColumn    = "-h, --help"
+	versionFlagColumn = "-v, --version"
+)
+
+// visibleFlags は隠していないフラグを返す
+func visibleFlags(flags []*Flag) []*Flag {
+	var visible []*Flag
+	for _, f := range flags {
+		if !f.Hidden {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
+
+// flagColumnWidth はフラグ列の最大幅を返す
+func flagColumnWidth(flags []*Flag) int {
+	width := 0
+	for _, f := range flags {
+		width = max(width, len(FlagColumn(f)))
+	}
+	return width
+}
+
+// writeFlag はフラグの1行を書き出す
+func writeFlag(b *strings.Builder, column, usage string, width int) {
+	fmt.Fprintf(b, "  %-*s  %s\n", width, column, usage)
+}
@@ -2,13 +2,21 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
 // Config はポモドーロタイマーの設定を保持する
+// 時間は "25m" のような文字列で保存する（旧形式のナノ秒の数値も読み込める）
 type Config struct {
+	// Version は設定ファイルのスキーマバージョン
+	Version            int           `json:"version"`
 	WorkDuration       time.Duration `json:"work_duration"`
 	ShortBreakDuration time.Duration `json:"short_break_duration"`
 	LongBreakDuration  time.Duration `json:"long_break_duration"`
@@ -17,11 +25,84 @@ type Config struct {
 	AutoStartWork      bool          `json:"auto_start_work"`
 	SoundEnabled       bool          `json:"sound_enabled"`
 	NotifyEnabled      bool          `json:"notify_enabled"`
+	DailyGoal          int           `json:"daily_goal"`
+	WeeklyGoal         int           `json:"weekly_goal"`
+	// WeekdayGoals は曜日別の1日の目標（キーは "monday" などの小文字の曜日名）
+	WeekdayGoals map[string]int `json:"weekday_goals,omitempty"`
+	// Tags はセッションに付けるデフォルトのタグ
+	Tags []string `json:"tags,omitempty"`
+	// TimewarriorLive は作業セッションに合わせてTimewarriorのインターバルを開始・停止する
+	TimewarriorLive bool `json:"timewarrior_live,omitempty"`
+	// Strict は作業セッション中の一時停止・スキップ・リセットに確認の入力を求め、
+	// 作業セッションの途中での終了を放棄として記録する
+	Strict bool `json:"strict,omitempty"`
+	// StrictBreaks は strict モードで休憩のスキップも禁止する
+	StrictBreaks bool `json:"strict_breaks,omitempty"`
+	// IdleDetection は離席を検出する方法（空なら検出しない、"auto"、"x11"、"logind"、"terminal"）
+	IdleDetection string `json:"idle_detection,omitempty"`
+	// IdleThreshold はこの時間操作がなければ離席とみなす（0 ならデフォルトの5分）
+	IdleThreshold time.Duration `json:"idle_threshold,omitempty"`
+	// IdleAction は戻ったときに離席中の時間をどう扱うか（"ask"、"keep"、"discard"、空なら "ask"）
+	IdleAction string `json:"idle_action,omitempty"`
+	// ScreenLock は作業中に画面がロックされたときの扱い（空なら何もしない、"pause"、"interrupt"）
+	ScreenLock string `json:"screen_lock,omitempty"`
+	// LockOnLongBreak は長い休憩の開始時に画面をロックする
+	LockOnLongBreak bool `json:"lock_on_long_break,omitempty"`
+	// DND は作業中に通知を止める方法（空なら止めない、"auto"、"gnome"、"dunst"、"mako"）
+	DND string `json:"dnd,omitempty"`
+	// ChatStatus は作業中にステータスを表示するチャット（空なら表示しない、"slack"、"mattermost"）
+	ChatStatus string `json:"chat_status,omitempty"`
+	// ChatStatusURL はチャットの API の URL（Slack は省略すると https://slack.com）
+	ChatStatusURL string `json:"chat_status_url,omitempty"`
+	// ChatStatusToken はチャットの API のトークン（POMODORO_CHAT_TOKEN でも指定できる）
+	ChatStatusToken string `json:"chat_status_token,omitempty"`
+	// TeamServer は完了した作業セッションを送るチームサーバーの URL（空なら送らない）
+	TeamServer string `json:"team_server,omitempty"`
+	// TeamToken はチームサーバーのユーザーのトークン（POMODORO_TEAM_TOKEN でも指定できる）
+	TeamToken string `json:"team_token,omitempty"`
+	// BreakActivities は休憩の種類（"short_break"、"long_break"）ごとに提案する過ごし方
+	// 先頭の "3x " は選ばれやすさの重み。種類がなければ組み込みの一覧、空の一覧なら提案しない
+	BreakActivities map[string][]string `json:"break_activities,omitempty"`
+	// GuidedBreak は短い休憩の最初に表示するガイド（空なら表示しない、"4-7-8"、"box"、"20-20-20"）
+	GuidedBreak string `json:"guided_break,omitempty"`
+	// Keybindings はキーに割り当てるアクション（"ctrl+s" = "skip"、"none" でデフォルトの割り当てを外す）
+	Keybindings map[string]string `json:"keybindings,omitempty"`
+
+	// DefaultProfile は --profile を指定しなかったときに使うプロファイル名
+	DefaultProfile string `json:"default_profile,omitempty"`
+	// Profiles は基本設定に重ねる名前付きの設定（指定したキーだけを上書きする）
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// Profile は適用中のプロファイル名（保存しない）
+	Profile string `json:"-"`
+	// Project はプロジェクトの設定ファイルを適用したときのプロジェクト名（保存しない）
+	Project string `json:"-"`
+	// Warnings は読み込み時の警告（不明なキーや移行の通知、保存しない）
+	Warnings []string `json:"-"`
+	// Sources は各設定キーの値がどこから来たか（記録がなければデフォルト値、保存しない）
+	Sources map[string]Source `json:"-"`
+}
+
+// DefaultIdleThreshold は idle_threshold を設定していないときに離席とみなす時間
+const DefaultIdleThreshold = 5 * time.Minute
+
+// IdleThresholdOrDefault は離席とみなす時間を返す
+func (c *Config) IdleThresholdOrDefault() time.Duration {
+	if c.IdleThreshold > 0 {
+		return c.IdleThreshold
+	}
+	return DefaultIdleThreshold
 }
 
+// Profile は基本設定からの差分となる設定キーと値
+type Profile map[string]any
+
+// ErrUnknownProfile は存在しないプロファイルを指定したときのエラー
+var ErrUnknownProfile = errors.New("unknown profile")
+
 // Default はデフォルトの設定を返す
 func Default() *Config {
 	return &Config{
+		Version:            CurrentVersion,
 		WorkDuration:       25 * time.Minute,
 		ShortBreakDuration: 5 * time.Minute,
 		LongBreakDuration:  15 * time.Minute,
@@ -33,18 +114,78 @@ func Default() *Config {
 	}
 }
 
-// ConfigPath は設定ファイルのパスを返す
-func ConfigPath() (string, error) {
+// Dir は設定ディレクトリのパスを返す
+// XDG_CONFIG_HOME が絶対パスで設定されていればその下の pomodoro、なければ ~/.config/pomodoro
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "pomodoro"), nil
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".config", "pomodoro", "config.json"), nil
+	return filepath.Join(home, ".config", "pomodoro"), nil
+}
+
+// GoalFor は指定した曜日の1日の目標ポモドーロ数を返す
+// 曜日別の目標がなければ DailyGoal を返す（0 は目標なし）
+func (c *Config) GoalFor(day time.Weekday) int {
+	if goal, ok := c.WeekdayGoals[strings.ToLower(day.String())]; ok {
+		return goal
+	}
+	return c.DailyGoal
 }
 
-// Load は設定ファイルから設定を読み込む
+// Load は設定ファイルから設定を読み込み、default_profile のプロファイルを適用する
 // ファイルが存在しない場合はデフォルト設定を返す
 func Load() (*Config, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile は設定ファイルから設定を読み込み、プロジェクトの設定ファイル、
+// 指定したプロファイル、環境変数の順に重ねる
+// name が空の場合は default_profile を使う
+// 優先順位はデフォルト < 設定ファイル < プロジェクト < プロファイル < 環境変数
+// （この後にコマンドラインのフラグ）
+func LoadProfile(name string) (*Config, error) {
+	cfg, err := LoadBase()
+	// ファイルがない場合もデフォルト設定に環境変数を適用する
+	// （プロファイルを指定していればプロファイルが見つからないエラーになる）
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, err
+	}
+	if dir, wdErr := os.Getwd(); wdErr == nil {
+		if projectErr := cfg.applyProject(dir); projectErr != nil {
+			return cfg, projectErr
+		}
+	}
+	merged, profileErr := cfg.WithProfile(name)
+	if profileErr != nil {
+		return merged, profileErr
+	}
+	if envErr := merged.applyEnv(); envErr != nil {
+		return merged, envErr
+	}
+	return merged, err
+}
+
+// LoadBase はプロファイルを適用せずに設定ファイルから設定を読み込む
+// 古い形式の設定ファイルは移行し、バックアップを残して書き戻す
+// 読み込みや検証に失敗した場合は、位置付きの *ParseError をまとめたエラーと
+// 警告だけを持つデフォルト設定を返す
+func LoadBase() (*Config, error) {
+	return loadBase(true)
+}
+
+// ReadBase は LoadBase と同じく読み込むが、設定ファイルには書き込まない
+// （古い形式はメモリ上で移行するだけ）
+// シェル補完や config check のように、ファイルを変えてはいけない場面で使う
+func ReadBase() (*Config, error) {
+	return loadBase(false)
+}
+
+// loadBase は LoadBase と ReadBase の本体（save が false なら移行した設定を保存しない）
+func loadBase(save bool) (*Config, error) {
 	path, err := ConfigPath()
 	if err != nil {
 		return Default(), err
@@ -55,31 +196,261 @@ func Load() (*Config, error) {
 		return Default(), err
 	}
 
+	format := FormatOf(path)
+	raw, err := decodeFile(format, data)
+	if err != nil {
+		return Default(), locate(path, data, nil, err)
+	}
+	// TOML と YAML はバージョン2から対応したので、version がなければ2とみなす
+	if _, ok := raw["version"]; !ok && format != FormatJSON {
+		raw["version"] = float64(2)
+	}
+
+	var warnings []string
+	if paths, err := existingPaths(); err == nil {
+		for _, shadowed := range paths[1:] {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored because %s takes precedence", shadowed, filepath.Base(path)))
+		}
+	}
+	if v := fileVersion(raw); v > CurrentVersion {
+		warnings = append(warnings, fmt.Sprintf("config version %d is newer than supported version %d; some settings may be ignored", v, CurrentVersion))
+	}
+	from, migrated := migrate(raw)
+	warnings = append(warnings, removeUnknownKeys(raw)...)
+
+	// キーごとに読み込んで、どのキーの値が不正かを報告できるようにする
 	cfg := Default()
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return Default(), err
+	var errs []error
+	invalid := make(map[string]bool)
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		if err := decodeField(cfg, key, key, raw[key]); err != nil {
+			errs = append(errs, err)
+			invalid[key] = true
+		}
+	}
+	// 読み込めなかったキーはデフォルト値のままなので検証結果から除く
+	for _, err := range append(Problems(cfg.Validate()), cfg.validateProfiles()...) {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) && invalid[topKey(fieldErr.Field)] {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		fallback := Default()
+		fallback.Warnings = warnings
+		return fallback, locateAll(path, format, data, errs)
 	}
 
+	if migrated && !save {
+		warnings = append(warnings, fmt.Sprintf("config version %d will be migrated to %d the next time it is loaded", from, CurrentVersion))
+	} else if migrated {
+		backup := path + ".bak"
+		if err := WriteFile(backup, data); err != nil {
+			return cfg, fmt.Errorf("failed to back up config before migration: %w", err)
+		}
+		if err := cfg.SaveTo(path); err != nil {
+			return cfg, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+		warnings = append(warnings, fmt.Sprintf("migrated config from version %d to %d (backup: %s)", from, CurrentVersion, backup))
+	}
+	for key := range raw {
+		if key != "version" {
+			cfg.SetSource(key, Source{Kind: SourceFile, Name: path})
+		}
+	}
+	cfg.Warnings = warnings
 	return cfg, nil
 }
 
-// Save は設定をファイルに保存する
+// ProfileNames はプロファイル名を名前順で返す
+func (c *Config) ProfileNames() []string {
+	return slices.Sorted(maps.Keys(c.Profiles))
+}
+
+// WithProfile はプロファイルを基本設定に重ねた設定を返す
+// name が空の場合は DefaultProfile を使い、それも空なら c をそのまま返す
+func (c *Config) WithProfile(name string) (*Config, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return c, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return c, fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+
+	merged, err := c.clone()
+	if err != nil {
+		return c, err
+	}
+	var errs []error
+	settings := profile.settings()
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		if err := decodeField(merged, "profiles."+name+"."+key, key, settings[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return c, errors.Join(errs...)
+	}
+	for key := range settings {
+		merged.SetSource(key, Source{Kind: SourceProfile, Name: name})
+	}
+	merged.Profile = name
+	return merged, nil
+}
+
+// validateProfiles は各プロファイルを重ねた設定を検証する
+// 基本設定の問題と重複しないよう、プロファイルで設定したキーのエラーだけを返す
+func (c *Config) validateProfiles() []error {
+	var errs []error
+	for _, name := range c.ProfileNames() {
+		merged, err := c.WithProfile(name)
+		if err != nil {
+			errs = append(errs, Problems(err)...)
+			continue
+		}
+		for _, err := range Problems(merged.Validate()) {
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				continue
+			}
+			if _, ok := c.Profiles[name][topKey(fieldErr.Field)]; ok {
+				errs = append(errs, &FieldError{Field: "profiles." + name + "." + fieldErr.Field, Err: fieldErr.Err})
+			}
+		}
+	}
+	return errs
+}
+
+// topKey はキーのパスの先頭のキーを返す（"weekday_goals.friday" → "weekday_goals"）
+func topKey(field string) string {
+	key, _, _ := strings.Cut(field, ".")
+	return key
+}
+
+// SetProfile は profile の設定のうち基本設定と異なるキーだけを name のプロファイルとして保存する
+func (c *Config) SetProfile(name string, profile *Config) error {
+	base, err := c.settings()
+	if err != nil {
+		return err
+	}
+	values, err := profile.settings()
+	if err != nil {
+		return err
+	}
+
+	diff := Profile{}
+	for key, value := range values {
+		if baseValue, ok := base[key]; !ok || !jsonEqual(baseValue, value) {
+			diff[key] = value
+		}
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+	c.Profiles[name] = diff
+	return nil
+}
+
+// settings はプロファイル関連のキーを除いた設定をキーと値の組で返す
+func (c *Config) settings() (Profile, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var values Profile
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values.settings(), nil
+}
+
+// settings はプロファイルの入れ子や既定プロファイルの指定を除いた設定を返す
+func (p Profile) settings() Profile {
+	values := maps.Clone(p)
+	for _, key := range profileOnlyKeys {
+		delete(values, key)
+	}
+	return values
+}
+
+// clone は設定の深いコピーを返す
+func (c *Config) clone() (*Config, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	copied := &Config{}
+	if err := json.Unmarshal(data, copied); err != nil {
+		return nil, err
+	}
+	copied.Profile = c.Profile
+	copied.Project = c.Project
+	copied.Warnings = c.Warnings
+	copied.Sources = maps.Clone(c.Sources)
+	return copied, nil
+}
+
+// jsonEqual はJSONとして同じ値かを判定する
+func jsonEqual(a, b any) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+// Save は設定を現在の設定ファイルに保存する（設定ファイルがなければ config.json）
 func (c *Config) Save() error {
 	path, err := ConfigPath()
 	if err != nil {
 		return err
 	}
+	return c.SaveTo(path)
+}
 
+// SaveTo は設定を path に保存する（形式は拡張子で決まる）
+// ファイル全体を書き直すため、コメントは残らない
+func (c *Config) SaveTo(path string) error {
 	// ディレクトリを作成
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	c.Version = CurrentVersion
+	values, err := c.values()
 	if err != nil {
 		return err
 	}
+	data, err := encodeFile(FormatOf(path), values)
+	if err != nil {
+		return err
+	}
+
+	return WriteFile(path, data)
+}
 
-	return os.WriteFile(path, data, 0644)
+// WriteFile は設定ファイル（バックアップを含む）を書き込む
+// トークンを書けるので、既存のファイルも含めて他のユーザーから読めないようにする
+func WriteFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
+}
+
+// values は設定をキーと値の組で返す
+func (c *Config) values() (map[string]any, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
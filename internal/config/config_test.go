@@ -2,12 +2,29 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 )
 
+// TestMain は実行環境の設定ファイルや POMODORO_* 環境変数がテストに影響しないようにする
+func TestMain(m *testing.M) {
+	names := []string{"XDG_CONFIG_HOME", "POMODORO_CONFIG"}
+	for _, v := range EnvVars {
+		names = append(names, v.Name)
+	}
+	for _, name := range names {
+		if err := os.Unsetenv(name); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to unset %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	os.Exit(m.Run())
+}
+
 // =============================================================================
 // Default - デフォルト設定の生成
 // =============================================================================
@@ -60,6 +77,31 @@ func TestDefaultは通知と音声を有効にする(t *testing.T) {
 	}
 }
 
+func TestDefaultは目標を設定しない(t *testing.T) {
+	cfg := Default()
+	if cfg.DailyGoal != 0 || cfg.WeeklyGoal != 0 {
+		t.Errorf("goals = %d/%d, want 0/0", cfg.DailyGoal, cfg.WeeklyGoal)
+	}
+}
+
+// =============================================================================
+// GoalFor - 曜日別の目標
+// =============================================================================
+
+func TestGoalForは曜日別の目標を優先する(t *testing.T) {
+	cfg := &Config{DailyGoal: 8, WeekdayGoals: map[string]int{"friday": 4, "sunday": 0}}
+
+	if got := cfg.GoalFor(time.Friday); got != 4 {
+		t.Errorf("GoalFor(Friday) = %d, want 4", got)
+	}
+	if got := cfg.GoalFor(time.Sunday); got != 0 {
+		t.Errorf("GoalFor(Sunday) = %d, want 0", got)
+	}
+	if got := cfg.GoalFor(time.Monday); got != 8 {
+		t.Errorf("GoalFor(Monday) = %d, want 8", got)
+	}
+}
+
 // =============================================================================
 // Save/Load - 設定の保存と読み込み
 // =============================================================================
@@ -268,3 +310,126 @@ func TestSaveはディレクトリを自動作成する(t *testing.T) {
 		t.Error("config file was not created")
 	}
 }
+
+// =============================================================================
+// Profiles - 名前付きプロファイル
+// =============================================================================
+
+func TestWithProfileは指定したキーだけを上書きする(t *testing.T) {
+	cfg := Default()
+	cfg.Tags = []string{"work"}
+	cfg.Profiles = map[string]Profile{
+		"deep": {"work_duration": float64(50 * time.Minute), "short_break_duration": float64(10 * time.Minute)},
+	}
+
+	merged, err := cfg.WithProfile("deep")
+	if err != nil {
+		t.Fatalf("WithProfile() error = %v", err)
+	}
+	if merged.WorkDuration != 50*time.Minute || merged.ShortBreakDuration != 10*time.Minute {
+		t.Errorf("durations = %v/%v, want 50m/10m", merged.WorkDuration, merged.ShortBreakDuration)
+	}
+	if merged.LongBreakDuration != 15*time.Minute || len(merged.Tags) != 1 {
+		t.Errorf("base values were not kept: %+v", merged)
+	}
+	if merged.Profile != "deep" {
+		t.Errorf("Profile = %q, want deep", merged.Profile)
+	}
+	if cfg.WorkDuration != 25*time.Minute {
+		t.Errorf("base WorkDuration changed to %v", cfg.WorkDuration)
+	}
+}
+
+func TestWithProfileは未指定ならdefault_profileを使う(t *testing.T) {
+	cfg := Default()
+	cfg.DefaultProfile = "meetings"
+	cfg.Profiles = map[string]Profile{"meetings": {"work_duration": float64(15 * time.Minute)}}
+
+	merged, err := cfg.WithProfile("")
+	if err != nil {
+		t.Fatalf("WithProfile() error = %v", err)
+	}
+	if merged.WorkDuration != 15*time.Minute {
+		t.Errorf("WorkDuration = %v, want 15m", merged.WorkDuration)
+	}
+
+	cfg.DefaultProfile = ""
+	if merged, _ := cfg.WithProfile(""); merged != cfg {
+		t.Error("WithProfile(\"\") without default_profile should return the base config")
+	}
+}
+
+func TestWithProfileは存在しないプロファイルでエラーを返す(t *testing.T) {
+	_, err := Default().WithProfile("missing")
+	if !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("WithProfile() error = %v, want ErrUnknownProfile", err)
+	}
+}
+
+func TestSetProfileは基本設定との差分だけを保存する(t *testing.T) {
+	base := Default()
+	profile := Default()
+	profile.WorkDuration = 50 * time.Minute
+	profile.SoundEnabled = false
+
+	if err := base.SetProfile("deep", profile); err != nil {
+		t.Fatalf("SetProfile() error = %v", err)
+	}
+
+	got := base.Profiles["deep"]
+	if len(got) != 2 {
+		t.Fatalf("profile = %v, want only work_duration and sound_enabled", got)
+	}
+	if _, ok := got["work_duration"]; !ok {
+		t.Error("work_duration missing from profile")
+	}
+	if got["sound_enabled"] != false {
+		t.Errorf("sound_enabled = %v, want false", got["sound_enabled"])
+	}
+}
+
+func TestLoadProfileはファイルのプロファイルを適用する(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	cfg := Default()
+	cfg.DefaultProfile = "study"
+	study := Default()
+	study.WorkDuration = 40 * time.Minute
+	deep := Default()
+	deep.WorkDuration = 50 * time.Minute
+	if err := cfg.SetProfile("study", study); err != nil {
+		t.Fatalf("SetProfile() error = %v", err)
+	}
+	if err := cfg.SetProfile("deep", deep); err != nil {
+		t.Fatalf("SetProfile() error = %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		expected time.Duration
+	}{
+		{"", 40 * time.Minute},
+		{"deep", 50 * time.Minute},
+	}
+	for _, tt := range tests {
+		loaded, err := LoadProfile(tt.name)
+		if err != nil {
+			t.Fatalf("LoadProfile(%q) error = %v", tt.name, err)
+		}
+		if loaded.WorkDuration != tt.expected {
+			t.Errorf("LoadProfile(%q).WorkDuration = %v, want %v", tt.name, loaded.WorkDuration, tt.expected)
+		}
+	}
+
+	base, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if base.WorkDuration != 25*time.Minute || base.Profile != "" {
+		t.Errorf("LoadBase() = %v (profile %q), want the base config", base.WorkDuration, base.Profile)
+	}
+}
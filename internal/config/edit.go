@@ -0,0 +1,480 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"pomodoro-cli/internal/activity"
+)
+
+// Set は設定ファイルの key に value を設定する
+// key は "work_duration"、"weekday_goals.friday"、"profiles.deep.work_duration" のようなパス
+// TOML と YAML ではコメントや書式を残したまま該当する値だけを書き換える
+// 書き換えた設定が検証に失敗した場合はファイルを元に戻してエラーを返す
+func Set(key, value string) (path string, err error) {
+	segments := strings.Split(key, ".")
+	typed, err := parseValue(segments, value)
+	if err != nil {
+		return "", err
+	}
+	return update(func(format Format, data []byte) ([]byte, error) {
+		return setInFile(format, data, segments, typed)
+	})
+}
+
+// Unset は設定ファイルから key を取り除き、デフォルト値（プロファイルなら基本設定の値）に戻す
+// "weekday_goals" や "profiles.deep" のようにテーブル全体も指定できる
+// key が設定されていなければファイルを変更せずに removed = false を返す
+func Unset(key string) (path string, removed bool, err error) {
+	segments := strings.Split(key, ".")
+	if _, err := resolveKey(segments, true); err != nil {
+		return "", false, err
+	}
+	path, err = update(func(format Format, data []byte) ([]byte, error) {
+		updated, ok, err := unsetInFile(format, data, segments)
+		removed = ok
+		return updated, err
+	})
+	return path, removed, err
+}
+
+// update は設定ファイルの内容を modify で書き換えて検証する
+// 検証に失敗した場合は元の内容に戻す（ファイルがなかった場合は削除する）
+func update(modify func(format Format, data []byte) ([]byte, error)) (path string, err error) {
+	path, err = ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	original, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return path, err
+	}
+
+	updated, err := modify(FormatOf(path), original)
+	if err != nil {
+		return path, fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	if bytes.Equal(updated, original) {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return path, err
+	}
+	if err := WriteFile(path, updated); err != nil {
+		return path, err
+	}
+
+	if _, err := LoadBase(); err != nil {
+		if existed {
+			_ = WriteFile(path, original)
+		} else {
+			_ = os.Remove(path)
+		}
+		return path, err
+	}
+	return path, nil
+}
+
+// setInFile は設定ファイルの内容の path の値を書き換えた内容を返す
+func setInFile(format Format, data []byte, path []string, value any) ([]byte, error) {
+	switch format {
+	case FormatTOML:
+		doc := parseTOMLDocument(data)
+		if err := doc.set(path, tomlValue(value)); err != nil {
+			return nil, err
+		}
+		return doc.bytes(), nil
+	case FormatYAML:
+		return setYAML(data, path, value)
+	default:
+		// 新しいファイルは現在のバージョンで作る（version がないと旧形式とみなされる）
+		raw := map[string]any{"version": CurrentVersion}
+		if len(bytes.TrimSpace(data)) > 0 {
+			raw = map[string]any{}
+			if err := json.Unmarshal(data, &raw); err != nil {
+				return nil, err
+			}
+		}
+		table := raw
+		for _, key := range path[:len(path)-1] {
+			child, ok := table[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				table[key] = child
+			}
+			table = child
+		}
+		table[path[len(path)-1]] = value
+		return encodeFile(FormatJSON, raw)
+	}
+}
+
+// setYAML はコメントを残してYAMLの値を書き換える
+func setYAML(data []byte, path []string, value any) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	node := doc.Content[0]
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("cannot update %s: %s is not a mapping", strings.Join(path, "."), strings.Join(path[:i], "."))
+		}
+		index := -1
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				index = j + 1
+			}
+		}
+
+		if i < len(path)-1 {
+			if index < 0 {
+				child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content, yamlNode(key), child)
+				index = len(node.Content) - 1
+			}
+			node = node.Content[index]
+			continue
+		}
+
+		replacement := yamlNode(value)
+		if index < 0 {
+			node.Content = append(node.Content, yamlNode(key), replacement)
+			break
+		}
+		old := node.Content[index]
+		replacement.HeadComment, replacement.LineComment, replacement.FootComment = old.HeadComment, old.LineComment, old.FootComment
+		if old.Kind == replacement.Kind && (old.Kind != yaml.ScalarNode || old.Tag == replacement.Tag) {
+			replacement.Style = old.Style
+		}
+		node.Content[index] = replacement
+	}
+
+	return encodeYAML(&doc)
+}
+
+// unsetInFile は設定ファイルの内容から path のキーを取り除いた内容を返す
+// キーがなければ data をそのまま返す
+func unsetInFile(format Format, data []byte, path []string) ([]byte, bool, error) {
+	switch format {
+	case FormatTOML:
+		doc := parseTOMLDocument(data)
+		removed, err := doc.remove(path)
+		if err != nil || !removed {
+			return data, false, err
+		}
+		return doc.bytes(), true, nil
+	case FormatYAML:
+		return unsetYAML(data, path)
+	default:
+		if len(bytes.TrimSpace(data)) == 0 {
+			return data, false, nil
+		}
+		raw := map[string]any{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, false, err
+		}
+		table := raw
+		for _, key := range path[:len(path)-1] {
+			child, ok := table[key].(map[string]any)
+			if !ok {
+				return data, false, nil
+			}
+			table = child
+		}
+		if _, ok := table[path[len(path)-1]]; !ok {
+			return data, false, nil
+		}
+		delete(table, path[len(path)-1])
+		updated, err := encodeFile(FormatJSON, raw)
+		return updated, true, err
+	}
+}
+
+// unsetYAML はコメントを残してYAMLからキーを取り除く
+func unsetYAML(data []byte, path []string) ([]byte, bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false, err
+	}
+	if len(doc.Content) == 0 {
+		return data, false, nil
+	}
+
+	node := doc.Content[0]
+	for i, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return data, false, nil
+		}
+		index := -1
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				index = j
+			}
+		}
+		if index < 0 {
+			return data, false, nil
+		}
+		if i < len(path)-1 {
+			node = node.Content[index+1]
+			continue
+		}
+		// ファイル冒頭のコメントは最初のキーに付くので、次のキーに引き継ぐ
+		if removedKey := node.Content[index]; i == 0 && index == 0 && removedKey.HeadComment != "" && len(node.Content) > 2 {
+			next := node.Content[index+2]
+			next.HeadComment = strings.TrimSpace(removedKey.HeadComment + "\n" + next.HeadComment)
+		}
+		node.Content = slices.Delete(node.Content, index, index+2)
+	}
+
+	updated, err := encodeYAML(&doc)
+	return updated, true, err
+}
+
+// encodeYAML は2文字のインデントでYAMLを書き出す
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// ----------------------------------------------------------------------------
+// 値の解釈
+// ----------------------------------------------------------------------------
+
+// parseValue はコマンドラインで指定された値を設定キーの型に合わせて変換する
+// 返す値はJSONを読み込んだときと同じ型（数値は float64、リストは []any）
+func parseValue(path []string, value string) (any, error) {
+	key := strings.Join(path, ".")
+	t, err := resolveKey(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t == reflect.TypeFor[time.Duration]():
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid duration %q (use a value like 25m or 1h30m)", key, value)
+		}
+		return formatDuration(d), nil
+	case t.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected true or false, got %q", key, value)
+		}
+		return b, nil
+	case t.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected an integer, got %q", key, value)
+		}
+		return float64(n), nil
+	case t.Kind() == reflect.Slice:
+		items := []any{}
+		for item := range strings.SplitSeq(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveKey はキーのパスを検証し、その値の型を返す
+// table が true なら "weekday_goals" や "profiles.deep" のようなテーブル全体も受け付ける
+func resolveKey(path []string, table bool) (reflect.Type, error) {
+	key := strings.Join(path, ".")
+	if path[0] == "version" {
+		return nil, fmt.Errorf("%s is managed by pomodoro and cannot be changed", key)
+	}
+	fieldPath := path
+	if path[0] == "profiles" {
+		switch {
+		case len(path) == 1 && table:
+			return reflect.TypeFor[map[string]Profile](), nil
+		case len(path) == 2 && table:
+			return reflect.TypeFor[Profile](), nil
+		case len(path) < 3:
+			return nil, fmt.Errorf("%s: use profiles.<name>.<key>", key)
+		}
+		fieldPath = path[2:]
+		if slices.Contains(profileOnlyKeys, fieldPath[0]) {
+			return nil, fmt.Errorf("%s: %q cannot be set in a profile", key, fieldPath[0])
+		}
+	}
+
+	field, ok := fieldByKey(fieldPath[0])
+	if !ok {
+		msg := fmt.Sprintf("unknown config key %q", key)
+		if suggestion := closestKey(fieldPath[0], knownKeys()); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		return nil, errors.New(msg)
+	}
+
+	t := field.Type
+	switch {
+	case t.Kind() == reflect.Map && len(fieldPath) == 1 && table:
+		return t, nil
+	case t.Kind() == reflect.Map:
+		if len(fieldPath) != 2 {
+			return nil, fmt.Errorf("%s: use %s.<name>", key, fieldPath[0])
+		}
+		return t.Elem(), nil
+	case len(fieldPath) != 1:
+		return nil, fmt.Errorf("%s: %s is not a table", key, fieldPath[0])
+	}
+	return t, nil
+}
+
+// ----------------------------------------------------------------------------
+// 値の表示
+// ----------------------------------------------------------------------------
+
+// ErrNotSet は値が設定されていないキーを取得しようとしたときのエラー
+var ErrNotSet = errors.New("not set")
+
+// Get は key の値を config set で指定するのと同じ書式の文字列で返す
+// テーブルは "friday = 4" のような行にして返す
+func (c *Config) Get(key string) (string, error) {
+	segments := strings.Split(key, ".")
+	if segments[0] != "version" {
+		if _, err := resolveKey(segments, true); err != nil {
+			return "", err
+		}
+	}
+
+	values, err := c.values()
+	if err != nil {
+		return "", err
+	}
+	var value any = values
+	for i, segment := range segments {
+		table, _ := value.(map[string]any)
+		v, ok := table[segment]
+		if !ok {
+			// omitempty で省略されたフィールドはゼロ値
+			if i == 0 {
+				field, _ := fieldByKey(segment)
+				if field.Type.Kind() == reflect.Bool {
+					return "false", nil
+				}
+				return "", nil
+			}
+			return "", fmt.Errorf("%s: %w", key, ErrNotSet)
+		}
+		value = v
+	}
+	return formatValue(value), nil
+}
+
+// Keys は config get/set で指定できるキーの一覧を返す（シェル補完に使う）
+// テーブルは "weekday_goals.friday" や "profiles.deep.work_duration" のように展開する
+// （keybindings は設定済みのキーだけ）
+func (c *Config) Keys() []string {
+	var base []string
+	for _, key := range knownKeys() {
+		switch key {
+		case "version", "profiles":
+		case "weekday_goals":
+			for _, day := range weekdayNames {
+				base = append(base, key+"."+day)
+			}
+		case "break_activities":
+			for _, breakType := range activity.BreakTypes {
+				base = append(base, key+"."+breakType)
+			}
+		case "keybindings":
+			for _, name := range slices.Sorted(maps.Keys(c.Keybindings)) {
+				base = append(base, key+"."+name)
+			}
+		default:
+			base = append(base, key)
+		}
+	}
+
+	keys := slices.Clone(base)
+	for _, name := range slices.Sorted(maps.Keys(c.Profiles)) {
+		for _, key := range base {
+			if !slices.Contains(profileOnlyKeys, key) {
+				keys = append(keys, "profiles."+name+"."+key)
+			}
+		}
+	}
+	return keys
+}
+
+// formatValue は値を表示用の文字列にする
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return strings.Join(items, ", ")
+	case map[string]any:
+		var lines []string
+		for _, key := range orderedKeys(v) {
+			if table, ok := v[key].(map[string]any); ok {
+				for line := range strings.SplitSeq(formatValue(table), "\n") {
+					if line != "" {
+						lines = append(lines, key+"."+line)
+					}
+				}
+				continue
+			}
+			lines = append(lines, key+" = "+formatValue(v[key]))
+		}
+		return strings.Join(lines, "\n")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fieldByKey はJSONのキーに対応する Config のフィールドを返す
+func fieldByKey(key string) (reflect.StructField, bool) {
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == key && name != "-" {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,389 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// tomlDocument.set - コメントを残したTOMLの書き換え
+// =============================================================================
+
+func TestTOMLのsetは値だけを置き換えてコメントを残す(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		path     []string
+		value    string
+		expected string
+	}{
+		{
+			"既存のキー",
+			"# header\nwork_duration = \"25m\"   # classic\n",
+			[]string{"work_duration"}, `"50m"`,
+			"# header\nwork_duration = \"50m\"   # classic\n",
+		},
+		{
+			"文字列中の#はコメントではない",
+			"tags = [\"a#b\", \"c\"] # note\n",
+			[]string{"tags"}, `["x"]`,
+			"tags = [\"x\"] # note\n",
+		},
+		{
+			"複数行の配列",
+			"tags = [\n  \"a\", # first\n  \"b\",\n]\nsound_enabled = true\n",
+			[]string{"tags"}, `["c"]`,
+			"tags = [\"c\"]\nsound_enabled = true\n",
+		},
+		{
+			"ルートのキーは最初の見出しの前に追加する",
+			"work_duration = \"25m\"\n\n[weekday_goals]\nfriday = 4\n",
+			[]string{"daily_goal"}, "8",
+			"work_duration = \"25m\"\ndaily_goal = 8\n\n[weekday_goals]\nfriday = 4\n",
+		},
+		{
+			"既存のテーブルに追加する",
+			"[profiles.deep]\nwork_duration = \"50m\" # long\n\n[profiles.study]\n",
+			[]string{"profiles", "deep", "daily_goal"}, "3",
+			"[profiles.deep]\nwork_duration = \"50m\" # long\ndaily_goal = 3\n\n[profiles.study]\n",
+		},
+		{
+			"空のテーブルの見出しの後に追加する",
+			"[profiles.study]\n",
+			[]string{"profiles", "study", "daily_goal"}, "3",
+			"[profiles.study]\ndaily_goal = 3\n",
+		},
+		{
+			"ドット区切りのキーで定義したテーブル",
+			"[profiles]\ndeep.work_duration = \"50m\"\n",
+			[]string{"profiles", "deep", "daily_goal"}, "3",
+			"[profiles]\ndeep.work_duration = \"50m\"\ndeep.daily_goal = 3\n",
+		},
+		{
+			"新しいテーブルは末尾に追加する",
+			"work_duration = \"25m\"\n",
+			[]string{"profiles", "meetings day", "work_duration"}, `"15m"`,
+			"work_duration = \"25m\"\n\n[profiles.\"meetings day\"]\nwork_duration = \"15m\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseTOMLDocument([]byte(tt.input))
+			if err := doc.set(tt.path, tt.value); err != nil {
+				t.Fatalf("set() error = %v", err)
+			}
+			if got := string(doc.bytes()); got != tt.expected {
+				t.Errorf("set() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTOMLのsetはインラインテーブルの中を書き換えない(t *testing.T) {
+	doc := parseTOMLDocument([]byte("weekday_goals = { friday = 4 }\n"))
+	if err := doc.set([]string{"weekday_goals", "monday"}, "6"); err == nil {
+		t.Error("set() error = nil, want inline table error")
+	}
+}
+
+// =============================================================================
+// tomlDocument.remove - コメントを残したTOMLのキーの削除
+// =============================================================================
+
+func TestTOMLのremoveはキーやテーブルを取り除く(t *testing.T) {
+	input := "# settings\nwork_duration = \"50m\" # long\ntags = [\n  \"a\",\n]\ndaily_goal = 4\n\n[weekday_goals]\nfriday = 2\n\n[profiles.deep]\nwork_duration = \"90m\"\n"
+	tests := []struct {
+		name     string
+		path     []string
+		expected string
+	}{
+		{
+			"1行のキー",
+			[]string{"work_duration"},
+			"# settings\ntags = [\n  \"a\",\n]\ndaily_goal = 4\n\n[weekday_goals]\nfriday = 2\n\n[profiles.deep]\nwork_duration = \"90m\"\n",
+		},
+		{
+			"複数行の値",
+			[]string{"tags"},
+			"# settings\nwork_duration = \"50m\" # long\ndaily_goal = 4\n\n[weekday_goals]\nfriday = 2\n\n[profiles.deep]\nwork_duration = \"90m\"\n",
+		},
+		{
+			"テーブル全体",
+			[]string{"weekday_goals"},
+			"# settings\nwork_duration = \"50m\" # long\ntags = [\n  \"a\",\n]\ndaily_goal = 4\n\n[profiles.deep]\nwork_duration = \"90m\"\n",
+		},
+		{
+			"末尾のテーブル",
+			[]string{"profiles", "deep"},
+			"# settings\nwork_duration = \"50m\" # long\ntags = [\n  \"a\",\n]\ndaily_goal = 4\n\n[weekday_goals]\nfriday = 2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseTOMLDocument([]byte(input))
+			removed, err := doc.remove(tt.path)
+			if err != nil || !removed {
+				t.Fatalf("remove() = %v, %v", removed, err)
+			}
+			if got := string(doc.bytes()); got != tt.expected {
+				t.Errorf("remove() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+
+	doc := parseTOMLDocument([]byte(input))
+	if removed, err := doc.remove([]string{"sound_enabled"}); err != nil || removed {
+		t.Errorf("remove(missing) = %v, %v, want false, nil", removed, err)
+	}
+}
+
+// =============================================================================
+// Set - 設定ファイルの値の書き換え
+// =============================================================================
+
+func TestSetはYAMLのコメントを残す(t *testing.T) {
+	path := writeConfigFileAs(t, "config.yaml", "# settings\nwork_duration: \"25m\" # classic\nprofiles:\n  deep:\n    # focus\n    work_duration: 50m\n")
+
+	if _, err := Set("work_duration", "30m"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := Set("profiles.deep.daily_goal", "3"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	expected := "# settings\nwork_duration: \"30m\" # classic\nprofiles:\n  deep:\n    # focus\n    work_duration: 50m\n    daily_goal: 3\n"
+	if got := readFile(t, path); got != expected {
+		t.Errorf("config.yaml =\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestSetはキーの割り当てをTOMLのテーブルに書き込む(t *testing.T) {
+	path := writeConfigFileAs(t, "config.toml", "work_duration = \"25m\"\n")
+
+	if _, err := Set("keybindings.ctrl+s", "skip"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := Set("keybindings.q", "none"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := Set("keybindings.x", "explode"); err == nil {
+		t.Error("Set() with an unknown action error = nil, want error")
+	}
+
+	expected := "work_duration = \"25m\"\n\n[keybindings]\n\"ctrl+s\" = \"skip\"\nq = \"none\"\n"
+	if got := readFile(t, path); got != expected {
+		t.Errorf("config.toml =\n%s\nwant:\n%s", got, expected)
+	}
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if cfg.Keybindings["ctrl+s"] != "skip" || cfg.Keybindings["q"] != "none" {
+		t.Errorf("Keybindings = %v", cfg.Keybindings)
+	}
+}
+
+func TestSetは設定ファイルがなければconfig_jsonを作成する(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := Set("tags", "docs, review")
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !strings.HasSuffix(path, "config.json") {
+		t.Errorf("path = %s, want config.json", path)
+	}
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[1] != "review" || cfg.WorkDuration != 25*time.Minute {
+		t.Errorf("loaded = %+v", cfg)
+	}
+}
+
+func TestSetは設定ファイルを他のユーザーから読めないようにする(t *testing.T) {
+	path := writeConfigFileAs(t, "config.toml", "daily_goal = 4\n")
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatalf("Chmod() error = %v", err)
+	}
+
+	if _, err := Set("team_token", "secret"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestSetは検証に失敗したら元に戻す(t *testing.T) {
+	original := "sessions_until_long_break = 4 # keep\n"
+	path := writeConfigFileAs(t, "config.toml", original)
+
+	if _, err := Set("sessions_until_long_break", "0"); err == nil {
+		t.Fatal("Set() error = nil, want validation error")
+	}
+	if got := readFile(t, path); got != original {
+		t.Errorf("config.toml = %q, want unchanged %q", got, original)
+	}
+}
+
+func TestSetは不正なキーと値を拒否する(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		key, value, want string
+	}{
+		{"work_duraton", "25m", `did you mean "work_duration"?`},
+		{"work_duration", "soon", "invalid duration"},
+		{"sound_enabled", "maybe", "expected true or false"},
+		{"weekday_goals", "3", "use weekday_goals.<name>"},
+		{"profiles.deep", "3", "use profiles.<name>.<key>"},
+		{"profiles.deep.default_profile", "x", "cannot be set in a profile"},
+		{"version", "3", "cannot be changed"},
+	}
+	for _, tt := range tests {
+		_, err := Set(tt.key, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Set(%q, %q) error = %v, want %q", tt.key, tt.value, err, tt.want)
+		}
+	}
+}
+
+// =============================================================================
+// Unset - 設定ファイルの値の削除
+// =============================================================================
+
+func TestUnsetはキーを取り除いてデフォルトに戻す(t *testing.T) {
+	path := writeConfigFileAs(t, "config.yaml", "# settings\nwork_duration: 50m\nweekday_goals:\n  friday: 2\n  monday: 6 # busy\n")
+
+	_, removed, err := Unset("work_duration")
+	if err != nil || !removed {
+		t.Fatalf("Unset() = %v, %v", removed, err)
+	}
+	if _, removed, err = Unset("weekday_goals.friday"); err != nil || !removed {
+		t.Fatalf("Unset() = %v, %v", removed, err)
+	}
+
+	expected := "# settings\nweekday_goals:\n  monday: 6 # busy\n"
+	if got := readFile(t, path); got != expected {
+		t.Errorf("config.yaml =\n%s\nwant:\n%s", got, expected)
+	}
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if cfg.WorkDuration != 25*time.Minute {
+		t.Errorf("WorkDuration = %v, want default 25m", cfg.WorkDuration)
+	}
+}
+
+func TestUnsetは設定されていないキーならファイルを変更しない(t *testing.T) {
+	original := `{"version": 2, "daily_goal": 4}`
+	path := writeConfigFileAs(t, "config.json", original)
+
+	_, removed, err := Unset("weekly_goal")
+	if err != nil || removed {
+		t.Errorf("Unset() = %v, %v, want false, nil", removed, err)
+	}
+	if got := readFile(t, path); got != original {
+		t.Errorf("config.json = %q, want unchanged", got)
+	}
+}
+
+func TestUnsetは参照されているプロファイルを取り除かない(t *testing.T) {
+	original := `{"version": 2, "default_profile": "deep", "profiles": {"deep": {"daily_goal": 3}}}`
+	path := writeConfigFileAs(t, "config.json", original)
+
+	if _, _, err := Unset("profiles.deep"); err == nil {
+		t.Error("Unset() error = nil, want default_profile error")
+	}
+	if got := readFile(t, path); got != original {
+		t.Errorf("config.json = %q, want restored", got)
+	}
+}
+
+// =============================================================================
+// Get - 設定値の取得
+// =============================================================================
+
+func TestGetはsetと同じ書式で値を返す(t *testing.T) {
+	cfg := Default()
+	cfg.Tags = []string{"docs", "review"}
+	cfg.WeekdayGoals = map[string]int{"sunday": 0, "monday": 6}
+	cfg.Profiles = map[string]Profile{"deep": {"work_duration": "50m"}}
+
+	tests := []struct {
+		key, expected string
+	}{
+		{"work_duration", "25m"},
+		{"sessions_until_long_break", "4"},
+		{"sound_enabled", "true"},
+		{"timewarrior_live", "false"},
+		{"default_profile", ""},
+		{"tags", "docs, review"},
+		{"weekday_goals", "monday = 6\nsunday = 0"},
+		{"weekday_goals.monday", "6"},
+		{"profiles", "deep.work_duration = 50m"},
+		{"profiles.deep.work_duration", "50m"},
+		{"version", "2"},
+	}
+	for _, tt := range tests {
+		got, err := cfg.Get(tt.key)
+		if err != nil {
+			t.Errorf("Get(%q) error = %v", tt.key, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Get(%q) = %q, want %q", tt.key, got, tt.expected)
+		}
+	}
+
+	if _, err := cfg.Get("profiles.deep.daily_goal"); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Get(unset profile key) error = %v, want ErrNotSet", err)
+	}
+	if _, err := cfg.Get("work_duraton"); err == nil || !strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("Get(typo) error = %v, want suggestion", err)
+	}
+}
+
+func TestKeysはsetできるキーとプロファイルのキーを返す(t *testing.T) {
+	cfg := Default()
+	cfg.Profiles = map[string]Profile{"deep": {}}
+	cfg.Keybindings = map[string]string{"ctrl+s": "skip"}
+
+	keys := cfg.Keys()
+	for _, want := range []string{"work_duration", "weekday_goals.friday", "keybindings.ctrl+s", "profiles.deep.daily_goal", "profiles.deep.weekday_goals.monday"} {
+		if !slices.Contains(keys, want) {
+			t.Errorf("Keys() missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"version", "profiles", "weekday_goals", "keybindings", "profiles.deep.default_profile"} {
+		if slices.Contains(keys, unwanted) {
+			t.Errorf("Keys() contains %q", unwanted)
+		}
+	}
+	for _, key := range keys {
+		if _, err := resolveKey(strings.Split(key, "."), false); err != nil {
+			t.Errorf("Keys() returned %q that cannot be set: %v", key, err)
+		}
+	}
+}
+
+// readFile はファイルの内容を返す
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,460 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format は設定ファイルの形式を表す
+type Format string
+
+const (
+	FormatTOML Format = "toml"
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// Formats は設定ファイルを探す優先順（複数ある場合は先の形式を使う）
+var Formats = []Format{FormatTOML, FormatYAML, FormatJSON}
+
+// ParseFormat は文字列から設定ファイルの形式を返す
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(s)
+	if s == "yml" {
+		return FormatYAML, nil
+	}
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown config format: %q (use toml, yaml or json)", s)
+}
+
+// FormatOf は拡張子から設定ファイルの形式を返す（不明な拡張子は JSON として扱う）
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return FormatTOML
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
+// PathFor は指定した形式の設定ファイルのパスを返す
+func PathFor(format Format) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config."+string(format)), nil
+}
+
+// explicitPath は --config で指定された設定ファイルのパス
+var explicitPath string
+
+// SetPath は使用する設定ファイルを明示する（--config、空文字列なら指定を取り消す）
+func SetPath(path string) {
+	explicitPath = path
+}
+
+// ExplicitPath は明示された設定ファイルのパスを返す（なければ空文字列）
+// --config の指定を POMODORO_CONFIG 環境変数より優先する
+func ExplicitPath() string {
+	if explicitPath != "" {
+		return explicitPath
+	}
+	return os.Getenv("POMODORO_CONFIG")
+}
+
+// ConfigPath は使用する設定ファイルのパスを返す
+// 明示されたファイルがあればそれを使い、なければ設定ディレクトリの
+// config.toml, config.yaml, config.json の順に探し、どれもなければ config.json を返す
+func ConfigPath() (string, error) {
+	if path := ExplicitPath(); path != "" {
+		return path, nil
+	}
+	paths, err := existingPaths()
+	if err != nil {
+		return "", err
+	}
+	if len(paths) > 0 {
+		return paths[0], nil
+	}
+	return PathFor(FormatJSON)
+}
+
+// existingPaths は存在する設定ファイルを優先順に返す
+// 設定ファイルが明示されていれば、ほかのファイルは探さない
+func existingPaths() ([]string, error) {
+	var candidates []string
+	if path := ExplicitPath(); path != "" {
+		candidates = []string{path}
+	} else {
+		for _, format := range Formats {
+			path, err := PathFor(format)
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, path)
+		}
+	}
+
+	var paths []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// ----------------------------------------------------------------------------
+// 読み込み
+// ----------------------------------------------------------------------------
+
+// decodeFile は設定ファイルをキーと値の組に変換する
+// 形式によらず値はJSONと同じ型（数値は float64）にそろえる
+func decodeFile(format Format, data []byte) (map[string]any, error) {
+	var raw map[string]any
+	switch format {
+	case FormatTOML:
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	default:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		return raw, nil
+	}
+	if raw == nil {
+		raw = map[string]any{}
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	raw = nil
+	if err := json.Unmarshal(normalized, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// position はファイル内の1始まりの行と列
+type position struct {
+	line, column int
+}
+
+// keyPositions は各キーの位置をキーのパス（"profiles.deep.work_duration"）ごとに返す
+func keyPositions(format Format, data []byte) map[string]position {
+	positions := make(map[string]position)
+	switch format {
+	case FormatTOML:
+		doc := parseTOMLDocument(data)
+		for _, h := range doc.headers {
+			positions[strings.Join(h.path, ".")] = position{h.line + 1, 1}
+		}
+		for _, e := range doc.entries {
+			positions[strings.Join(e.path(), ".")] = position{e.line + 1, e.column + 1}
+		}
+	case FormatYAML:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err == nil && len(doc.Content) > 0 {
+			walkYAML(doc.Content[0], "", positions)
+		}
+	default:
+		for path, offset := range fieldOffsets(data) {
+			line, col := lineColumn(data, offset)
+			positions[path] = position{line, col}
+		}
+	}
+	return positions
+}
+
+// walkYAML はYAMLのマッピングを辿ってキーの位置を記録する
+func walkYAML(node *yaml.Node, prefix string, positions map[string]position) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+		positions[path] = position{key.Line, key.Column}
+		walkYAML(node.Content[i+1], path, positions)
+	}
+}
+
+// yamlErrorLine は "yaml: line 3: ..." 形式のエラーから行番号を取り出す
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// syntaxErrorPosition は構文エラーの位置とメッセージを返す（位置が不明なら ok が false）
+func syntaxErrorPosition(data []byte, err error) (pos position, msg string, ok bool) {
+	var jsonErr *json.SyntaxError
+	if errors.As(err, &jsonErr) {
+		line, col := lineColumn(data, max(jsonErr.Offset-1, 0))
+		return position{line, col}, err.Error(), true
+	}
+	var tomlErr toml.ParseError
+	if errors.As(err, &tomlErr) {
+		return position{tomlErr.Position.Line, tomlErr.Position.Col}, tomlErr.Message, true
+	}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return position{line, 0}, m[2], true
+	}
+	return position{}, "", false
+}
+
+// ----------------------------------------------------------------------------
+// 書き込み
+// ----------------------------------------------------------------------------
+
+// encodeFile は設定のキーと値を指定した形式で書き出す
+// キーは Config のフィールド順（曜日は月曜から、その他は名前順）に並べる
+func encodeFile(format Format, values map[string]any) ([]byte, error) {
+	switch format {
+	case FormatTOML:
+		var b bytes.Buffer
+		writeTOMLTable(&b, nil, values)
+		return b.Bytes(), nil
+	case FormatYAML:
+		var b bytes.Buffer
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err := enc.Encode(yamlNode(values)); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	default:
+		var b bytes.Buffer
+		if err := writeJSONValue(&b, values, ""); err != nil {
+			return nil, err
+		}
+		b.WriteString("\n")
+		return b.Bytes(), nil
+	}
+}
+
+// weekdayNames は月曜始まりの曜日名
+var weekdayNames = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// orderedKeys はマップのキーを書き出す順に返す
+func orderedKeys(m map[string]any) []string {
+	known := knownKeys()
+	rank := func(key string) int {
+		if i := slices.Index(known, key); i >= 0 {
+			return i
+		}
+		if i := slices.Index(weekdayNames, key); i >= 0 {
+			return len(known) + i
+		}
+		return len(known) + len(weekdayNames)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra - rb
+		}
+		return strings.Compare(a, b)
+	})
+	return keys
+}
+
+// writeJSONValue はキーの順序を保ってJSONを書き出す
+func writeJSONValue(b *bytes.Buffer, v any, indent string) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		return nil
+	}
+	if len(m) == 0 {
+		b.WriteString("{}")
+		return nil
+	}
+	b.WriteString("{\n")
+	for i, key := range orderedKeys(m) {
+		name, _ := json.Marshal(key)
+		fmt.Fprintf(b, "%s  %s: ", indent, name)
+		if err := writeJSONValue(b, m[key], indent+"  "); err != nil {
+			return err
+		}
+		if i < len(m)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + "}")
+	return nil
+}
+
+// writeTOMLTable はテーブルの値を書き出し、入れ子のテーブルを [a.b] の見出しで続ける
+func writeTOMLTable(b *bytes.Buffer, path []string, table map[string]any) {
+	var tables []string
+	for _, key := range orderedKeys(table) {
+		switch v := table[key].(type) {
+		case nil:
+		case map[string]any:
+			tables = append(tables, key)
+		default:
+			fmt.Fprintf(b, "%s = %s\n", tomlKey(key), tomlValue(v))
+		}
+	}
+	for _, key := range tables {
+		sub := table[key].(map[string]any)
+		subPath := append(slices.Clone(path), key)
+		// 値を持たずテーブルだけを含むテーブル（profiles など）は見出しを省く
+		if len(sub) == 0 || hasValues(sub) {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(b, "[%s]\n", tomlKeyPath(subPath))
+		}
+		writeTOMLTable(b, subPath, sub)
+	}
+}
+
+// hasValues はテーブル以外の値を含むかを判定する
+func hasValues(table map[string]any) bool {
+	for _, v := range table {
+		if _, ok := v.(map[string]any); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bareKey は引用符なしで書けるTOMLのキー
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey はTOMLのキーを必要に応じて引用符で囲んで返す
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlKeyPath はドット区切りのキーを返す
+func tomlKeyPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlValue はTOMLの値の表記を返す
+func tomlValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return tomlString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		items := make([]string, 0, len(v))
+		for _, key := range orderedKeys(v) {
+			items = append(items, tomlKey(key)+" = "+tomlValue(v[key]))
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	default:
+		return tomlString(fmt.Sprint(v))
+	}
+}
+
+// tomlString はTOMLの基本文字列を返す
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// yamlNode はキーの順序を保ったYAMLのノードを返す
+func yamlNode(v any) *yaml.Node {
+	switch v := v.(type) {
+	case map[string]any:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range orderedKeys(v) {
+			node.Content = append(node.Content, yamlNode(key), yamlNode(v[key]))
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case float64:
+		if v == float64(int64(v)) {
+			return yamlScalar(int64(v))
+		}
+		return yamlScalar(v)
+	default:
+		return yamlScalar(v)
+	}
+}
+
+// yamlScalar はスカラー値のノードを返す
+func yamlScalar(v any) *yaml.Node {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(v)}
+	}
+	return &node
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// ConfigPath - 設定ファイルの探索
+// =============================================================================
+
+func TestConfigPathはTOMLYAMLJSONの順に優先する(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "pomodoro")
+
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("ConfigPath() error = %v", err)
+	}
+	if path != filepath.Join(dir, "config.json") {
+		t.Errorf("ConfigPath() without files = %s, want config.json", path)
+	}
+
+	for _, name := range []string{"config.json", "config.yaml", "config.toml"} {
+		writeFile(t, filepath.Join(dir, name), "")
+		path, err := ConfigPath()
+		if err != nil {
+			t.Fatalf("ConfigPath() error = %v", err)
+		}
+		if filepath.Base(path) != name {
+			t.Errorf("ConfigPath() = %s, want %s", path, name)
+		}
+	}
+}
+
+// =============================================================================
+// LoadBase - TOML / YAML の読み込み
+// =============================================================================
+
+func TestLoadBaseはTOMLを読み込む(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", `# comment
+work_duration = "50m"
+sessions_until_long_break = 3
+tags = ["deep"]
+
+[weekday_goals]
+friday = 4
+
+[profiles.meetings]
+work_duration = "15m"
+`)
+
+	cfg, err := LoadProfile("meetings")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.WorkDuration != 15*time.Minute || cfg.SessionsUntilLong != 3 || cfg.GoalFor(time.Friday) != 4 {
+		t.Errorf("loaded = %v/%d/%d", cfg.WorkDuration, cfg.SessionsUntilLong, cfg.GoalFor(time.Friday))
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none (TOML without version is not migrated)", cfg.Warnings)
+	}
+}
+
+func TestLoadBaseはYAMLを読み込む(t *testing.T) {
+	writeConfigFileAs(t, "config.yaml", `# comment
+work_duration: 50m
+auto_start_work: false
+weekday_goals:
+  sunday: 0
+`)
+
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if cfg.WorkDuration != 50*time.Minute || cfg.AutoStartWork || cfg.GoalFor(time.Sunday) != 0 {
+		t.Errorf("loaded = %+v", cfg)
+	}
+}
+
+func TestLoadBaseはTOMLの不正な値をキーの位置で報告する(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", "work_duration = \"25m\"\n\n[profiles.deep]\n  sessions_until_long_break = 0\n")
+
+	_, err := LoadBase()
+	problems := Problems(err)
+	want := ":4:3: profiles.deep.sessions_until_long_break: must be between 1 and 100, got 0"
+	if len(problems) != 1 || !strings.HasSuffix(problems[0].Error(), want) {
+		t.Errorf("problems = %v, want suffix %q", problems, want)
+	}
+}
+
+func TestLoadBaseは優先されないファイルを警告する(t *testing.T) {
+	path := writeConfigFileAs(t, "config.toml", `work_duration = "50m"`)
+	writeFile(t, filepath.Join(filepath.Dir(path), "config.json"), `{}`)
+
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "config.json is ignored because config.toml takes precedence") {
+		t.Errorf("Warnings = %v", cfg.Warnings)
+	}
+}
+
+// =============================================================================
+// SaveTo - 形式ごとの書き込み
+// =============================================================================
+
+func TestSaveToは拡張子の形式で保存して読み戻せる(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			path, err := PathFor(format)
+			if err != nil {
+				t.Fatalf("PathFor() error = %v", err)
+			}
+
+			cfg := Default()
+			cfg.WorkDuration = 50 * time.Minute
+			cfg.Tags = []string{"deep work", "docs"}
+			cfg.WeekdayGoals = map[string]int{"sunday": 0, "monday": 6}
+			cfg.Profiles = map[string]Profile{"meetings day": {"work_duration": "15m"}}
+			if err := cfg.SaveTo(path); err != nil {
+				t.Fatalf("SaveTo() error = %v", err)
+			}
+
+			loaded, err := LoadProfile("meetings day")
+			if err != nil {
+				t.Fatalf("LoadProfile() error = %v", err)
+			}
+			if loaded.WorkDuration != 15*time.Minute || len(loaded.Tags) != 2 || loaded.GoalFor(time.Monday) != 6 {
+				t.Errorf("loaded = %+v", loaded)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			// キーは Config のフィールド順、曜日は月曜から
+			out := string(data)
+			if strings.Index(out, "version") > strings.Index(out, "work_duration") ||
+				strings.Index(out, "monday") > strings.Index(out, "sunday") {
+				t.Errorf("keys are not in field order:\n%s", out)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// writeConfigFileAs は一時的なHOMEに name の設定ファイルを書き込んでそのパスを返す
+func writeConfigFileAs(t *testing.T, name, content string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".config", "pomodoro", name)
+	writeFile(t, path, content)
+	return path
+}
+
+// writeFile はディレクトリを作成してファイルを書き込む
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
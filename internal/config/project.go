@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// プロジェクトの設定ファイルは .pomodoro.toml, .pomodoro.yaml, .pomodoro.json のいずれか
+// 作業ディレクトリからgitのルートまでさかのぼって探し、ユーザーの設定ファイルに重ねる
+const projectFileBase = ".pomodoro"
+
+// projectKeys はプロジェクトの設定ファイルで使えるキー（タイマーと表示の設定だけ）
+// クローンしたリポジトリが、トークンや記録の送り先、キー入力、通知や画面ロックなどの
+// システムの状態、外部コマンドの実行を変えられないように、それ以外のキーは無視する
+// default_profile もプロファイルがプロジェクトの設定より優先するので使えない
+var projectKeys = []string{
+	"work_duration", "short_break_duration", "long_break_duration", "sessions_until_long_break",
+	"auto_start_breaks", "auto_start_work", "sound_enabled", "notify_enabled",
+	"daily_goal", "weekly_goal", "weekday_goals", "tags", "break_activities", "guided_break",
+}
+
+// FindProjectFile は dir から親ディレクトリへgitのルートまでプロジェクトの設定ファイルを探す
+// gitのリポジトリの外では dir だけを探す
+// root はプロジェクトのルート（gitのルート、リポジトリの外では dir）
+// 見つからなければ path は空文字列
+func FindProjectFile(dir string) (path, root string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	dirs := []string{dir}
+	root = dir
+	if gitRoot, ok := findGitRoot(dir); ok {
+		root = gitRoot
+		for d := dir; d != gitRoot; d = filepath.Dir(d) {
+			dirs = append(dirs, filepath.Dir(d))
+		}
+	}
+
+	for _, d := range dirs {
+		for _, format := range Formats {
+			path := filepath.Join(d, projectFileBase+"."+string(format))
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, root, nil
+			}
+		}
+	}
+	return "", root, nil
+}
+
+// findGitRoot は .git を含む最も近い親ディレクトリを返す
+func findGitRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// applyProject は dir から見つかったプロジェクトの設定ファイルを重ね、
+// プロジェクト名をタグに加える
+// プロジェクトの設定ファイルがなければ何もしない
+func (c *Config) applyProject(dir string) error {
+	path, root, err := FindProjectFile(dir)
+	if err != nil || path == "" {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	format := FormatOf(path)
+	raw, err := decodeFile(format, data)
+	if err != nil {
+		return locate(path, data, nil, err)
+	}
+	// プロファイルはユーザーの設定ファイルで定義する
+	var warnings []string
+	if _, ok := raw["profiles"]; ok {
+		warnings = append(warnings, fmt.Sprintf("%s: profiles cannot be defined in a project config and are ignored", path))
+	}
+	delete(raw, "profiles")
+	delete(raw, "version")
+	for _, warning := range removeUnknownKeys(raw) {
+		warnings = append(warnings, path+": "+warning)
+	}
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		if !slices.Contains(projectKeys, key) {
+			warnings = append(warnings, fmt.Sprintf("%s: %s cannot be set in a project config and is ignored", path, key))
+			delete(raw, key)
+		}
+	}
+
+	merged, err := c.clone()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	invalid := make(map[string]bool)
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		if err := decodeField(merged, key, key, raw[key]); err != nil {
+			errs = append(errs, err)
+			invalid[key] = true
+		}
+	}
+	// ユーザーの設定ファイルの値は検証済みなので、プロジェクトで設定したキーだけを検証する
+	for _, err := range Problems(merged.Validate()) {
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			key := topKey(fieldErr.Field)
+			if _, ok := raw[key]; ok && !invalid[key] {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		c.Warnings = append(c.Warnings, warnings...)
+		return locateAll(path, format, data, errs)
+	}
+
+	for key := range raw {
+		merged.SetSource(key, Source{Kind: SourceProject, Name: path})
+	}
+	merged.Project = filepath.Base(root)
+	if !slices.Contains(merged.Tags, merged.Project) {
+		merged.Tags = append([]string{merged.Project}, merged.Tags...)
+		merged.SetSource("tags", Source{Kind: SourceProject, Name: path})
+	}
+	merged.Warnings = append(merged.Warnings, warnings...)
+	*c = *merged
+	return nil
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// FindProjectFile - プロジェクトの設定ファイルの探索
+// =============================================================================
+
+func TestFindProjectFileはgitのルートまでさかのぼる(t *testing.T) {
+	repo := filepath.Join(t.TempDir(), "website")
+	sub := filepath.Join(repo, "src", "pages")
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(repo, ".pomodoro.json"), "{}")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, root, err := FindProjectFile(sub)
+	if err != nil {
+		t.Fatalf("FindProjectFile() error = %v", err)
+	}
+	if want := filepath.Join(repo, ".pomodoro.json"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	if root != repo {
+		t.Errorf("root = %s, want %s", root, repo)
+	}
+}
+
+func TestFindProjectFileは近いファイルとTOMLを優先する(t *testing.T) {
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "api")
+	writeFile(t, filepath.Join(repo, ".git"), "gitdir: ../.git/worktrees/api\n")
+	writeFile(t, filepath.Join(repo, ".pomodoro.toml"), "")
+	writeFile(t, filepath.Join(sub, ".pomodoro.json"), "{}")
+	writeFile(t, filepath.Join(sub, ".pomodoro.toml"), "")
+
+	path, _, err := FindProjectFile(sub)
+	if err != nil {
+		t.Fatalf("FindProjectFile() error = %v", err)
+	}
+	if want := filepath.Join(sub, ".pomodoro.toml"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+}
+
+func TestFindProjectFileはgitのルートより上を探さない(t *testing.T) {
+	outer := t.TempDir()
+	repo := filepath.Join(outer, "repo")
+	writeFile(t, filepath.Join(outer, ".pomodoro.json"), "{}")
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+
+	if path, _, _ := FindProjectFile(repo); path != "" {
+		t.Errorf("path = %s, want none", path)
+	}
+}
+
+func TestFindProjectFileはリポジトリの外では作業ディレクトリだけを探す(t *testing.T) {
+	outer := t.TempDir()
+	dir := filepath.Join(outer, "notes")
+	writeFile(t, filepath.Join(outer, ".pomodoro.json"), "{}")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, root, _ := FindProjectFile(dir)
+	if path != "" {
+		t.Errorf("path = %s, want none", path)
+	}
+	if root != dir {
+		t.Errorf("root = %s, want %s", root, dir)
+	}
+}
+
+// =============================================================================
+// LoadProfile - プロジェクトの設定ファイルを重ねる
+// =============================================================================
+
+func TestLoadProfileはプロジェクトの設定をユーザー設定とプロファイルの間に重ねる(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", `work_duration = "25m"
+daily_goal = 8
+tags = ["work"]
+
+[profiles.deep]
+work_duration = "50m"
+`)
+	repo := filepath.Join(t.TempDir(), "website")
+	projectPath := filepath.Join(repo, ".pomodoro.yaml")
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+	writeFile(t, projectPath, "work_duration: 40m\nshort_break_duration: 10m\nprofiles:\n  x: {}\n")
+	t.Chdir(repo)
+
+	cfg, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.Project != "website" {
+		t.Errorf("Project = %q, want website", cfg.Project)
+	}
+	if cfg.WorkDuration != 40*time.Minute || cfg.ShortBreakDuration != 10*time.Minute || cfg.DailyGoal != 8 {
+		t.Errorf("merged = %v / %v / %d", cfg.WorkDuration, cfg.ShortBreakDuration, cfg.DailyGoal)
+	}
+	if !slices.Equal(cfg.Tags, []string{"website", "work"}) {
+		t.Errorf("Tags = %v, want project name first", cfg.Tags)
+	}
+	if got := cfg.SourceOf("work_duration"); got != (Source{SourceProject, projectPath}) {
+		t.Errorf("SourceOf(work_duration) = %v", got)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "profiles cannot be defined in a project config") {
+		t.Errorf("Warnings = %v", cfg.Warnings)
+	}
+
+	// プロファイルはプロジェクトの設定より優先する
+	deep, err := LoadProfile("deep")
+	if err != nil {
+		t.Fatalf("LoadProfile(deep) error = %v", err)
+	}
+	if deep.WorkDuration != 50*time.Minute || deep.ShortBreakDuration != 10*time.Minute {
+		t.Errorf("deep = %v / %v, want 50m / 10m", deep.WorkDuration, deep.ShortBreakDuration)
+	}
+}
+
+func TestLoadProfileはプロジェクトの設定の問題を位置付きで返す(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".pomodoro.json"), "{\n  \"daily_goal\": -1\n}\n")
+	t.Chdir(dir)
+
+	_, err := LoadProfile("")
+	problems := Problems(err)
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), ".pomodoro.json:2:3: daily_goal: must be between 0 and 100") {
+		t.Errorf("problems = %v", problems)
+	}
+}
+
+func TestLoadProfileはプロジェクトの設定でチャットの接続先を変えさせない(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", `chat_status = "mattermost"
+chat_status_url = "https://chat.example.com"
+chat_status_token = "user-secret"
+`)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".pomodoro.toml"), `chat_status = "slack"
+chat_status_url = "https://attacker.example.net"
+daily_goal = 3
+`)
+	t.Chdir(dir)
+
+	cfg, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.ChatStatus != "mattermost" || cfg.ChatStatusURL != "https://chat.example.com" || cfg.ChatStatusToken != "user-secret" {
+		t.Errorf("chat status = %q %q, want the user's settings", cfg.ChatStatus, cfg.ChatStatusURL)
+	}
+	if cfg.DailyGoal != 3 {
+		t.Errorf("DailyGoal = %d, want 3 from the project", cfg.DailyGoal)
+	}
+	var warned []string
+	for _, w := range cfg.Warnings {
+		if strings.Contains(w, "cannot be set in a project config") {
+			warned = append(warned, w)
+		}
+	}
+	if len(warned) != 2 || !strings.Contains(warned[0], "chat_status ") || !strings.Contains(warned[1], "chat_status_url") {
+		t.Errorf("Warnings = %v, want chat_status and chat_status_url ignored", cfg.Warnings)
+	}
+}
+
+func TestLoadProfileはプロジェクトの設定でデフォルトのプロファイルを変えさせない(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", `default_profile = "meetings"
+
+[profiles.deep]
+work_duration = "50m"
+
+[profiles.meetings]
+work_duration = "15m"
+`)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".pomodoro.toml"), `default_profile = "deep"
+short_break_duration = "10m"
+`)
+	t.Chdir(dir)
+
+	// ユーザーの default_profile を使い、プロジェクトの値はその下に重なる
+	cfg, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.WorkDuration != 15*time.Minute || cfg.ShortBreakDuration != 10*time.Minute {
+		t.Errorf("merged = %v / %v, want 15m / 10m", cfg.WorkDuration, cfg.ShortBreakDuration)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "default_profile cannot be set in a project config") {
+		t.Errorf("Warnings = %v", cfg.Warnings)
+	}
+
+	// --profile で選んだプロファイルもプロジェクトの設定より優先する
+	deep, err := LoadProfile("deep")
+	if err != nil {
+		t.Fatalf("LoadProfile(deep) error = %v", err)
+	}
+	if deep.WorkDuration != 50*time.Minute || deep.ShortBreakDuration != 10*time.Minute {
+		t.Errorf("deep = %v / %v, want 50m / 10m", deep.WorkDuration, deep.ShortBreakDuration)
+	}
+}
+
+func TestLoadProfileはプロジェクトの設定でチームサーバーを変えさせない(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", `team_server = "https://pomodoro.example.com"
+team_token = "user-secret"
+`)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".pomodoro.toml"), `team_server = "https://attacker.example.net"
+team_token = "attacker"
+`)
+	t.Chdir(dir)
+
+	cfg, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.TeamServer != "https://pomodoro.example.com" || cfg.TeamToken != "user-secret" {
+		t.Errorf("team server = %q, want the user's settings", cfg.TeamServer)
+	}
+	if got := cfg.SourceOf("team_server"); got.Kind == SourceProject {
+		t.Errorf("SourceOf(team_server) = %v, want not from the project", got)
+	}
+}
+
+func TestLoadProfileはプロジェクトの設定でタイマーと表示以外の設定を変えさせない(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", `sound_enabled = true
+`)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".pomodoro.toml"), `work_duration = "40m"
+timewarrior_live = true
+strict = true
+lock_on_long_break = true
+dnd = "auto"
+
+[keybindings]
+q = "none"
+`)
+	t.Chdir(dir)
+
+	cfg, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.WorkDuration != 40*time.Minute {
+		t.Errorf("WorkDuration = %v, want 40m", cfg.WorkDuration)
+	}
+	if cfg.TimewarriorLive || cfg.Strict || cfg.LockOnLongBreak || cfg.DND != "" || len(cfg.Keybindings) != 0 {
+		t.Errorf("project config changed user-only settings: %+v", cfg)
+	}
+	for _, key := range []string{"dnd", "keybindings", "lock_on_long_break", "strict", "timewarrior_live"} {
+		if !slices.ContainsFunc(cfg.Warnings, func(w string) bool {
+			return strings.Contains(w, key+" cannot be set in a project config")
+		}) {
+			t.Errorf("Warnings = %v, want one for %s", cfg.Warnings, key)
+		}
+	}
+}
+
+func TestLoadBaseはプロジェクトの設定を読まない(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".pomodoro.json"), `{"daily_goal": 3}`)
+	t.Chdir(dir)
+
+	cfg, _ := LoadBase()
+	if cfg.DailyGoal != 0 || cfg.Project != "" {
+		t.Errorf("LoadBase() = goal %d, project %q, want user config only", cfg.DailyGoal, cfg.Project)
+	}
+}
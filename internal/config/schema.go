@@ -0,0 +1,243 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CurrentVersion は設定ファイルの現在のスキーマバージョン
+// 1: version キーなし、時間はナノ秒の数値
+// 2: 時間は "25m" のような文字列
+const CurrentVersion = 2
+
+// migrations[i] はバージョン i+1 の設定をバージョン i+2 に変換する
+var migrations = []func(raw map[string]any){
+	migrateDurationsToStrings,
+}
+
+// durationKeys は時間を表す設定キー
+var durationKeys = []string{"work_duration", "short_break_duration", "long_break_duration", "idle_threshold"}
+
+// ----------------------------------------------------------------------------
+// JSON表現
+// ----------------------------------------------------------------------------
+
+// duration は "25m" のような文字列で書き込み、文字列とナノ秒の数値の両方を読み込める時間
+type duration time.Duration
+
+// MarshalJSON は時間を文字列で書き込む
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(formatDuration(time.Duration(d)))
+}
+
+// UnmarshalJSON は文字列（"25m"）または旧形式のナノ秒の数値を読み込む
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (use a value like \"25m\" or \"1h30m\")", s)
+		}
+		*d = duration(parsed)
+		return nil
+	}
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid duration %s (use a value like \"25m\" or \"1h30m\")", data)
+	}
+	*d = duration(n)
+	return nil
+}
+
+// formatDuration は "25m0s" を "25m" のように末尾のゼロを省いて返す
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// configAlias はメソッドを持たない Config（MarshalJSON の再帰を避ける）
+type configAlias Config
+
+// MarshalJSON は時間を文字列にして設定を書き込む
+func (c Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version            int      `json:"version"`
+		WorkDuration       duration `json:"work_duration"`
+		ShortBreakDuration duration `json:"short_break_duration"`
+		LongBreakDuration  duration `json:"long_break_duration"`
+		IdleThreshold      duration `json:"idle_threshold,omitempty"`
+		configAlias
+	}{
+		Version:            c.Version,
+		WorkDuration:       duration(c.WorkDuration),
+		ShortBreakDuration: duration(c.ShortBreakDuration),
+		LongBreakDuration:  duration(c.LongBreakDuration),
+		IdleThreshold:      duration(c.IdleThreshold),
+		configAlias:        configAlias(c),
+	})
+}
+
+// UnmarshalJSON は文字列と旧形式の数値のどちらの時間も受け付けて設定を読み込む
+func (c *Config) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		WorkDuration       *duration `json:"work_duration"`
+		ShortBreakDuration *duration `json:"short_break_duration"`
+		LongBreakDuration  *duration `json:"long_break_duration"`
+		IdleThreshold      *duration `json:"idle_threshold"`
+		*configAlias
+	}{
+		WorkDuration:       (*duration)(&c.WorkDuration),
+		ShortBreakDuration: (*duration)(&c.ShortBreakDuration),
+		LongBreakDuration:  (*duration)(&c.LongBreakDuration),
+		IdleThreshold:      (*duration)(&c.IdleThreshold),
+		configAlias:        (*configAlias)(c),
+	}
+	return json.Unmarshal(data, &aux)
+}
+
+// ----------------------------------------------------------------------------
+// バージョンと移行
+// ----------------------------------------------------------------------------
+
+// fileVersion は設定ファイルのバージョンを返す（version キーがないか 0 以下なら 1）
+func fileVersion(raw map[string]any) int {
+	v, ok := raw["version"].(float64)
+	if !ok || v < 1 {
+		return 1
+	}
+	return int(v)
+}
+
+// migrate は古いバージョンの設定を現在のバージョンに変換する
+// 変換した場合は元のバージョンと true を返す
+func migrate(raw map[string]any) (from int, migrated bool) {
+	from = fileVersion(raw)
+	for v := from; v < CurrentVersion; v++ {
+		migrations[v-1](raw)
+	}
+	if from < CurrentVersion {
+		raw["version"] = CurrentVersion
+		return from, true
+	}
+	return from, false
+}
+
+// migrateDurationsToStrings はナノ秒の数値の時間を文字列に変換する（1 → 2）
+func migrateDurationsToStrings(raw map[string]any) {
+	convert := func(values map[string]any) {
+		for _, key := range durationKeys {
+			if n, ok := values[key].(float64); ok {
+				values[key] = formatDuration(time.Duration(n))
+			}
+		}
+	}
+	convert(raw)
+	if profiles, ok := raw["profiles"].(map[string]any); ok {
+		for _, p := range profiles {
+			if values, ok := p.(map[string]any); ok {
+				convert(values)
+			}
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------
+// 不明なキー
+// ----------------------------------------------------------------------------
+
+// profileOnlyKeys はプロファイルの中では使えないキー
+var profileOnlyKeys = []string{"version", "default_profile", "profiles"}
+
+// knownKeys は Config のJSONキーの一覧
+func knownKeys() []string {
+	var keys []string
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// removeUnknownKeys は不明なキーを取り除き、その警告を返す
+// 打ち間違いと思われるキーには正しいキーの候補を添える
+func removeUnknownKeys(raw map[string]any) []string {
+	known := knownKeys()
+	var warnings []string
+	check := func(values map[string]any, allowed func(string) bool, where string) {
+		for _, key := range slices.Sorted(maps.Keys(values)) {
+			if allowed(key) {
+				continue
+			}
+			delete(values, key)
+			if slices.Contains(known, key) {
+				warnings = append(warnings, fmt.Sprintf("config key %q cannot be set%s and is ignored", key, where))
+				continue
+			}
+			msg := fmt.Sprintf("unknown config key %q%s is ignored", key, where)
+			if suggestion := closestKey(key, known); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			warnings = append(warnings, msg)
+		}
+	}
+
+	check(raw, func(key string) bool { return slices.Contains(known, key) }, "")
+	if profiles, ok := raw["profiles"].(map[string]any); ok {
+		for _, name := range slices.Sorted(maps.Keys(profiles)) {
+			values, ok := profiles[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			check(values, func(key string) bool {
+				return slices.Contains(known, key) && !slices.Contains(profileOnlyKeys, key)
+			}, fmt.Sprintf(" in profile %q", name))
+		}
+	}
+	return warnings
+}
+
+// closestKey は編集距離が2以下で最も近いキーを返す（なければ空文字列）
+func closestKey(key string, candidates []string) string {
+	best, bestDistance := "", 3
+	for _, c := range candidates {
+		if d := editDistance(key, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// editDistance は2つの文字列のレーベンシュタイン距離を返す
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Durations - 時間の表現
+// =============================================================================
+
+func TestMarshalJSONは時間を文字列で書き込む(t *testing.T) {
+	cfg := Default()
+	cfg.LongBreakDuration = 90 * time.Minute
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, expected := range []string{`"work_duration":"25m"`, `"short_break_duration":"5m"`, `"long_break_duration":"1h30m"`, `"version":2`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Marshal() = %s, want %s", data, expected)
+		}
+	}
+}
+
+func TestUnmarshalJSONは文字列とナノ秒の両方を読み込む(t *testing.T) {
+	var cfg Config
+	data := `{"work_duration":"50m","short_break_duration":600000000000,"long_break_duration":"1h"}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if cfg.WorkDuration != 50*time.Minute || cfg.ShortBreakDuration != 10*time.Minute || cfg.LongBreakDuration != time.Hour {
+		t.Errorf("durations = %v/%v/%v", cfg.WorkDuration, cfg.ShortBreakDuration, cfg.LongBreakDuration)
+	}
+}
+
+func TestUnmarshalJSONは不正な時間でエラーを返す(t *testing.T) {
+	var cfg Config
+	err := json.Unmarshal([]byte(`{"work_duration":"25 minutes"}`), &cfg)
+	if err == nil || !strings.Contains(err.Error(), `"25 minutes"`) {
+		t.Errorf("Unmarshal() error = %v, want invalid duration error", err)
+	}
+}
+
+// =============================================================================
+// Migration - 古い設定ファイルの移行
+// =============================================================================
+
+func TestLoadBaseはバージョン1の設定を移行して保存する(t *testing.T) {
+	path := writeConfigFile(t, `{
+  "work_duration": 3000000000000,
+  "sessions_until_long_break": 3,
+  "profiles": {"short": {"work_duration": 900000000000}}
+}`)
+
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if cfg.WorkDuration != 50*time.Minute || cfg.SessionsUntilLong != 3 {
+		t.Errorf("loaded = %v/%d, want 50m/3", cfg.WorkDuration, cfg.SessionsUntilLong)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "migrated config from version 1 to 2") {
+		t.Errorf("Warnings = %v, want a migration notice", cfg.Warnings)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, expected := range []string{`"version": 2`, `"work_duration": "50m"`, `"work_duration": "15m"`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("migrated file does not contain %s:\n%s", expected, data)
+		}
+	}
+	if info, err := os.Stat(path + ".bak"); err != nil {
+		t.Errorf("backup was not written: %v", err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("backup mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// 移行済みのファイルは再度移行しない
+	cfg, err = LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("Warnings after migration = %v, want none", cfg.Warnings)
+	}
+}
+
+func TestReadBaseはバージョン1の設定を移行しても保存しない(t *testing.T) {
+	content := `{"work_duration": 3000000000000}`
+	path := writeConfigFile(t, content)
+
+	cfg, err := ReadBase()
+	if err != nil {
+		t.Fatalf("ReadBase() error = %v", err)
+	}
+	if cfg.WorkDuration != 50*time.Minute {
+		t.Errorf("WorkDuration = %v, want 50m", cfg.WorkDuration)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "will be migrated") {
+		t.Errorf("Warnings = %v, want a pending migration notice", cfg.Warnings)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("config file was rewritten:\n%s", data)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("backup should not be written, Stat() error = %v", err)
+	}
+}
+
+// =============================================================================
+// Unknown Keys - 不明なキーの警告
+// =============================================================================
+
+func TestLoadBaseは不明なキーを警告して無視する(t *testing.T) {
+	writeConfigFile(t, `{
+  "version": 2,
+  "work_duraton": "50m",
+  "profiles": {"deep": {"version": 3, "colour": "red"}}
+}`)
+
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if cfg.WorkDuration != 25*time.Minute {
+		t.Errorf("WorkDuration = %v, want the default 25m", cfg.WorkDuration)
+	}
+
+	expected := []string{
+		`unknown config key "work_duraton" is ignored (did you mean "work_duration"?)`,
+		`unknown config key "colour" in profile "deep" is ignored`,
+		`config key "version" cannot be set in profile "deep" and is ignored`,
+	}
+	if len(cfg.Warnings) != len(expected) {
+		t.Fatalf("Warnings = %q, want %d warnings", cfg.Warnings, len(expected))
+	}
+	for i, w := range expected {
+		if cfg.Warnings[i] != w {
+			t.Errorf("Warnings[%d] = %q, want %q", i, cfg.Warnings[i], w)
+		}
+	}
+}
+
+func TestLoadBaseは新しいバージョンを警告する(t *testing.T) {
+	writeConfigFile(t, `{"version": 99}`)
+
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if len(cfg.Warnings) != 1 || !strings.Contains(cfg.Warnings[0], "version 99 is newer") {
+		t.Errorf("Warnings = %v, want a newer version warning", cfg.Warnings)
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// writeConfigFile は一時的なHOMEに config.json を書き込んでそのパスを返す
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	return writeConfigFileAs(t, "config.json", content)
+}
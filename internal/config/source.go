@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// SourceKind は設定値の出どころの種類
+type SourceKind int
+
+// 優先順位の低い順
+const (
+	SourceDefault SourceKind = iota // デフォルト値
+	SourceFile                      // 設定ファイル
+	SourceProject                   // プロジェクトの設定ファイル
+	SourceProfile                   // プロファイル
+	SourceEnv                       // 環境変数
+	SourceFlag                      // コマンドラインのフラグ
+)
+
+// Source は設定値の出どころ
+type Source struct {
+	Kind SourceKind
+	// Name はファイルのパス、プロファイル名、環境変数名、フラグ名のいずれか
+	Name string
+}
+
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceFile:
+		return "file " + s.Name
+	case SourceProject:
+		return "project " + s.Name
+	case SourceProfile:
+		return "profile " + s.Name
+	case SourceEnv:
+		return "env " + s.Name
+	case SourceFlag:
+		return "flag " + s.Name
+	default:
+		return "default"
+	}
+}
+
+// SetSource は key の値の出どころを記録する
+func (c *Config) SetSource(key string, source Source) {
+	if c.Sources == nil {
+		c.Sources = make(map[string]Source)
+	}
+	c.Sources[key] = source
+}
+
+// SourceOf は key の値の出どころを返す
+func (c *Config) SourceOf(key string) Source {
+	return c.Sources[key]
+}
+
+// ----------------------------------------------------------------------------
+// 環境変数
+// ----------------------------------------------------------------------------
+
+// EnvVar は設定を上書きする環境変数
+type EnvVar struct {
+	Name string
+	Key  string
+}
+
+// EnvVars は設定を上書きする環境変数の一覧（名前はコマンドラインのフラグに合わせる）
+var EnvVars = []EnvVar{
+	{"POMODORO_WORK", "work_duration"},
+	{"POMODORO_SHORT_BREAK", "short_break_duration"},
+	{"POMODORO_LONG_BREAK", "long_break_duration"},
+	{"POMODORO_SESSIONS", "sessions_until_long_break"},
+	{"POMODORO_AUTO_BREAK", "auto_start_breaks"},
+	{"POMODORO_AUTO_WORK", "auto_start_work"},
+	{"POMODORO_SOUND", "sound_enabled"},
+	{"POMODORO_NOTIFY", "notify_enabled"},
+	{"POMODORO_DAILY_GOAL", "daily_goal"},
+	{"POMODORO_WEEKLY_GOAL", "weekly_goal"},
+	{"POMODORO_TAGS", "tags"},
+	{"POMODORO_TIMEWARRIOR", "timewarrior_live"},
+	{"POMODORO_STRICT", "strict"},
+	{"POMODORO_STRICT_BREAKS", "strict_breaks"},
+	{"POMODORO_IDLE", "idle_detection"},
+	{"POMODORO_SCREEN_LOCK", "screen_lock"},
+	{"POMODORO_DND", "dnd"},
+	{"POMODORO_CHAT_STATUS", "chat_status"},
+	{"POMODORO_CHAT_TOKEN", "chat_status_token"},
+	{"POMODORO_TEAM_SERVER", "team_server"},
+	{"POMODORO_TEAM_TOKEN", "team_token"},
+}
+
+// EnvError は環境変数の値のエラー
+type EnvError struct {
+	Name string
+	Err  error
+}
+
+func (e *EnvError) Error() string {
+	return "environment variable " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// applyEnv は POMODORO_* 環境変数の値で設定を上書きする
+// 空の環境変数は設定されていないものとして扱う
+func (c *Config) applyEnv() error {
+	var errs []error
+	applied := make(map[string]string)
+	for _, v := range EnvVars {
+		value := os.Getenv(v.Name)
+		if value == "" {
+			continue
+		}
+		typed, err := parseValue([]string{v.Key}, value)
+		if err == nil {
+			err = decodeField(c, v.Key, v.Key, typed)
+		}
+		if err != nil {
+			errs = append(errs, &EnvError{Name: v.Name, Err: err})
+			continue
+		}
+		c.SetSource(v.Key, Source{Kind: SourceEnv, Name: v.Name})
+		applied[v.Key] = v.Name
+	}
+
+	// 環境変数で設定した値だけを検証する（ファイルの値は読み込み時に検証済み）
+	for _, err := range Problems(c.Validate()) {
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			continue
+		}
+		if name, ok := applied[topKey(fieldErr.Field)]; ok {
+			errs = append(errs, &EnvError{Name: name, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ----------------------------------------------------------------------------
+// 設定値の説明
+// ----------------------------------------------------------------------------
+
+// Setting は設定キーの値とその出どころ
+type Setting struct {
+	Key    string
+	Value  string
+	Source Source
+}
+
+// Explain は各設定キーの値と出どころを設定ファイルのキーの順に返す
+// 曜日別の目標、休憩の過ごし方とキーの割り当ては1つずつの行にする
+func (c *Config) Explain() ([]Setting, error) {
+	var settings []Setting
+	for _, key := range knownKeys() {
+		if key == "version" || key == "profiles" {
+			continue
+		}
+		if key == "weekday_goals" && len(c.WeekdayGoals) > 0 {
+			for _, day := range weekdayNames {
+				if goal, ok := c.WeekdayGoals[day]; ok {
+					settings = append(settings, Setting{Key: key + "." + day, Value: fmt.Sprint(goal), Source: c.SourceOf(key)})
+				}
+			}
+			continue
+		}
+		if key == "break_activities" && len(c.BreakActivities) > 0 {
+			for _, breakType := range slices.Sorted(maps.Keys(c.BreakActivities)) {
+				value := strings.Join(c.BreakActivities[breakType], ", ")
+				settings = append(settings, Setting{Key: key + "." + breakType, Value: value, Source: c.SourceOf(key)})
+			}
+			continue
+		}
+		if key == "keybindings" && len(c.Keybindings) > 0 {
+			for _, name := range slices.Sorted(maps.Keys(c.Keybindings)) {
+				settings = append(settings, Setting{Key: key + "." + name, Value: c.Keybindings[name], Source: c.SourceOf(key)})
+			}
+			continue
+		}
+		value, err := c.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		// トークンは画面に出さない
+		if (key == "chat_status_token" || key == "team_token") && value != "" {
+			value = "********"
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: c.SourceOf(key)})
+	}
+	return settings, nil
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// 設定ファイルの場所 - XDG_CONFIG_HOME と明示したファイル
+// =============================================================================
+
+func TestDirはXDG_CONFIG_HOMEを使う(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	if want := filepath.Join(xdg, "pomodoro"); dir != want {
+		t.Errorf("Dir() = %s, want %s", dir, want)
+	}
+}
+
+func TestDirは相対パスのXDG_CONFIG_HOMEを無視する(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	if want := filepath.Join(home, ".config", "pomodoro"); dir != want {
+		t.Errorf("Dir() = %s, want %s", dir, want)
+	}
+}
+
+func TestConfigPathは明示したファイルを優先する(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", "daily_goal = 1\n")
+	envPath := filepath.Join(t.TempDir(), "env.yaml")
+	flagPath := filepath.Join(t.TempDir(), "flag.json")
+	writeFile(t, envPath, "daily_goal: 2\n")
+	writeFile(t, flagPath, `{"version": 2, "daily_goal": 3}`)
+
+	t.Setenv("POMODORO_CONFIG", envPath)
+	assertLoadedGoal(t, envPath, 2)
+
+	SetPath(flagPath)
+	t.Cleanup(func() { SetPath("") })
+	assertLoadedGoal(t, flagPath, 3)
+}
+
+func TestLoadBaseは明示したファイルがなければErrNotExistを返す(t *testing.T) {
+	writeConfigFileAs(t, "config.toml", "daily_goal = 1\n")
+	t.Setenv("POMODORO_CONFIG", filepath.Join(t.TempDir(), "missing.toml"))
+
+	if _, err := LoadBase(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadBase() error = %v, want ErrNotExist", err)
+	}
+}
+
+// assertLoadedGoal は ConfigPath と読み込んだ daily_goal を確認する
+func assertLoadedGoal(t *testing.T, path string, goal int) {
+	t.Helper()
+	if got, _ := ConfigPath(); got != path {
+		t.Errorf("ConfigPath() = %s, want %s", got, path)
+	}
+	cfg, err := LoadBase()
+	if err != nil {
+		t.Fatalf("LoadBase() error = %v", err)
+	}
+	if len(cfg.Warnings) > 0 {
+		t.Errorf("Warnings = %v, want none", cfg.Warnings)
+	}
+	if cfg.DailyGoal != goal {
+		t.Errorf("DailyGoal = %d, want %d", cfg.DailyGoal, goal)
+	}
+}
+
+// =============================================================================
+// 環境変数による上書きと値の出どころ
+// =============================================================================
+
+func TestLoadProfileは環境変数をプロファイルより優先する(t *testing.T) {
+	path := writeConfigFileAs(t, "config.toml", `work_duration = "30m"
+daily_goal = 4
+short_break_duration = "6m"
+
+[profiles.deep]
+work_duration = "50m"
+daily_goal = 2
+`)
+	t.Setenv("POMODORO_WORK", "45m")
+	t.Setenv("POMODORO_TAGS", "docs, review")
+	t.Setenv("POMODORO_SOUND", "false")
+	t.Setenv("POMODORO_NOTIFY", "")
+
+	cfg, err := LoadProfile("deep")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		source Source
+	}{
+		{"work_duration", "45m", Source{SourceEnv, "POMODORO_WORK"}},
+		{"daily_goal", "2", Source{SourceProfile, "deep"}},
+		{"short_break_duration", "6m", Source{SourceFile, path}},
+		{"long_break_duration", "15m", Source{}},
+		{"tags", "docs, review", Source{SourceEnv, "POMODORO_TAGS"}},
+		{"sound_enabled", "false", Source{SourceEnv, "POMODORO_SOUND"}},
+		{"notify_enabled", "true", Source{}},
+	}
+	for _, tt := range tests {
+		value, err := cfg.Get(tt.key)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", tt.key, err)
+		}
+		if value != tt.value {
+			t.Errorf("%s = %q, want %q", tt.key, value, tt.value)
+		}
+		if got := cfg.SourceOf(tt.key); got != tt.source {
+			t.Errorf("SourceOf(%q) = %v, want %v", tt.key, got, tt.source)
+		}
+	}
+}
+
+func TestLoadProfileは不正な環境変数を報告する(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("POMODORO_WORK", "soon")
+	t.Setenv("POMODORO_SESSIONS", "0")
+
+	_, err := LoadProfile("")
+	problems := Problems(err)
+	if len(problems) != 2 {
+		t.Fatalf("problems = %v, want 2", problems)
+	}
+	for i, want := range []string{"POMODORO_WORK: work_duration: invalid duration", "POMODORO_SESSIONS: sessions_until_long_break: must be between 1 and 100"} {
+		var envErr *EnvError
+		if !errors.As(problems[i], &envErr) || !strings.Contains(problems[i].Error(), want) {
+			t.Errorf("problems[%d] = %v, want EnvError containing %q", i, problems[i], want)
+		}
+	}
+}
+
+func TestExplainは曜日別の目標を曜日ごとに返す(t *testing.T) {
+	cfg := Default()
+	cfg.WeekdayGoals = map[string]int{"sunday": 0, "friday": 4}
+	cfg.WorkDuration = 50 * time.Minute
+	cfg.SetSource("work_duration", Source{Kind: SourceFlag, Name: "--work"})
+
+	settings, err := cfg.Explain()
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	got := make(map[string]Setting)
+	var keys []string
+	for _, s := range settings {
+		got[s.Key] = s
+		keys = append(keys, s.Key)
+	}
+
+	if s := got["work_duration"]; s.Value != "50m" || s.Source.String() != "flag --work" {
+		t.Errorf("work_duration = %+v", s)
+	}
+	if s := got["sessions_until_long_break"]; s.Value != "4" || s.Source.String() != "default" {
+		t.Errorf("sessions_until_long_break = %+v", s)
+	}
+	joined := strings.Join(keys, " ")
+	if !strings.Contains(joined, "weekday_goals.friday weekday_goals.sunday") {
+		t.Errorf("keys = %s, want weekday goals in weekday order", joined)
+	}
+	for _, hidden := range []string{"version", "profiles"} {
+		if _, ok := got[hidden]; ok {
+			t.Errorf("Explain() includes %q", hidden)
+		}
+	}
+}
+
+func TestExplainはトークンを隠す(t *testing.T) {
+	cfg := Default()
+	cfg.ChatStatusToken = "xoxp-secret"
+	cfg.TeamServer = "https://pomodoro.example.com"
+	cfg.TeamToken = "team-secret"
+
+	settings, err := cfg.Explain()
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	for _, s := range settings {
+		if (s.Key == "chat_status_token" || s.Key == "team_token") && s.Value != "********" {
+			t.Errorf("%s = %q, want masked", s.Key, s.Value)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TOMLの書き換えは行単位で行い、コメントや空行、値の書式をそのまま残す
+// 設定ファイルで使う範囲（キーと値、[table] 見出し、ドット区切りのキー）に対応する
+
+// tomlHeader は [table] の見出し行
+type tomlHeader struct {
+	path []string
+	line int
+}
+
+// tomlEntry はキーと値の行
+type tomlEntry struct {
+	table []string // 属するテーブル
+	key   []string // ドット区切りのキー
+	line  int      // 0始まりの行番号
+	// column はキーの開始位置（0始まりのバイト位置）
+	column int
+	// 値は line 行目の valueStart から endLine 行目の valueEnd まで
+	valueStart int
+	endLine    int
+	valueEnd   int
+	inline     bool // 値がインラインテーブル
+}
+
+// path はテーブルを含むキーのパスを返す
+func (e tomlEntry) path() []string {
+	return append(slices.Clone(e.table), e.key...)
+}
+
+// tomlDocument は行に分割したTOMLと、見出し・キーの位置
+type tomlDocument struct {
+	lines   []string
+	headers []tomlHeader
+	entries []tomlEntry
+}
+
+// parseTOMLDocument はTOMLの見出しとキーの位置を読み取る
+// 構文エラーの検出は toml パッケージに任せ、読めない行は読み飛ばす
+func parseTOMLDocument(data []byte) *tomlDocument {
+	doc := &tomlDocument{lines: strings.Split(string(data), "\n")}
+	var table []string
+	for i := 0; i < len(doc.lines); i++ {
+		line := doc.lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			inner := strings.TrimPrefix(trimmed, "[")
+			inner = strings.TrimPrefix(inner, "[") // [[array]] も見出しとして扱う
+			path, rest, ok := parseTOMLKey(inner)
+			if ok && strings.HasPrefix(strings.TrimSpace(rest), "]") {
+				table = path
+				doc.headers = append(doc.headers, tomlHeader{path: path, line: i})
+			}
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		key, rest, ok := parseTOMLKey(line[indent:])
+		rest = strings.TrimLeft(rest, " \t")
+		if !ok || !strings.HasPrefix(rest, "=") {
+			continue
+		}
+		valueStart := len(line) - len(strings.TrimLeft(rest[1:], " \t"))
+		entry := tomlEntry{
+			table:      table,
+			key:        key,
+			line:       i,
+			column:     indent,
+			valueStart: valueStart,
+			inline:     strings.HasPrefix(line[valueStart:], "{"),
+		}
+		entry.endLine, entry.valueEnd = scanTOMLValue(doc.lines, i, valueStart)
+		doc.entries = append(doc.entries, entry)
+		i = entry.endLine
+	}
+	return doc
+}
+
+// parseTOMLKey は先頭のドット区切りのキーを読み取り、残りの文字列を返す
+func parseTOMLKey(s string) (key []string, rest string, ok bool) {
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, "", false
+		}
+		var part string
+		switch s[0] {
+		case '"', '\'':
+			end := closingQuote(s, 0)
+			if end < 0 {
+				return nil, "", false
+			}
+			part = unquoteTOML(s[:end+1])
+			s = s[end+1:]
+		default:
+			n := 0
+			for n < len(s) && (isBareKeyChar(s[n])) {
+				n++
+			}
+			if n == 0 {
+				return nil, "", false
+			}
+			part, s = s[:n], s[n:]
+		}
+		key = append(key, part)
+		trimmed := strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(trimmed, ".") {
+			return key, s, true
+		}
+		s = trimmed[1:]
+	}
+}
+
+// isBareKeyChar は引用符なしのキーに使える文字かを判定する
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// closingQuote は s[start] の引用符に対応する閉じ引用符の位置を返す（なければ -1）
+func closingQuote(s string, start int) int {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteTOML は引用符で囲まれたキーから引用符とエスケープを外す
+func unquoteTOML(s string) string {
+	inner := s[1 : len(s)-1]
+	if s[0] == '\'' {
+		return inner
+	}
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t").Replace(inner)
+}
+
+// scanTOMLValue は値の終わり（コメントや行末の手前）を返す
+// 複数行にまたがる配列やインラインテーブルは括弧が閉じるまで読む
+func scanTOMLValue(lines []string, lineNo, start int) (endLine, end int) {
+	depth := 0
+	lastValue := start
+	for ; lineNo < len(lines); lineNo, start = lineNo+1, 0 {
+		line := lines[lineNo]
+		for i := start; i < len(line); i++ {
+			switch c := line[i]; c {
+			case '"', '\'':
+				close := closingQuote(line, i)
+				if close < 0 {
+					return lineNo, len(strings.TrimRight(line, " \t\r"))
+				}
+				i = close
+				lastValue = i + 1
+			case '[', '{':
+				depth++
+				lastValue = i + 1
+			case ']', '}':
+				depth--
+				lastValue = i + 1
+			case '#':
+				if depth <= 0 {
+					return lineNo, lastValue
+				}
+				i = len(line)
+			case ' ', '\t', '\r':
+			default:
+				lastValue = i + 1
+			}
+		}
+		if depth <= 0 {
+			return lineNo, lastValue
+		}
+		lastValue = 0
+	}
+	return len(lines) - 1, lastValue
+}
+
+// set はキーの値を書き換える（キーがなければ適切なテーブルに追加する）
+func (d *tomlDocument) set(path []string, value string) error {
+	for _, e := range d.entries {
+		p := e.path()
+		if slices.Equal(p, path) {
+			d.replace(e, value)
+			return nil
+		}
+		if e.inline && len(path) > len(p) && slices.Equal(path[:len(p)], p) {
+			return fmt.Errorf("cannot update %s: %s is an inline table", strings.Join(path, "."), strings.Join(p, "."))
+		}
+	}
+
+	table, key := path[:len(path)-1], path[len(path)-1]
+	// 同じテーブルのキーがあれば、その最後の行の後に追加する
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		e := d.entries[i]
+		p := e.path()
+		if len(e.table) <= len(table) && slices.Equal(e.table, table[:len(e.table)]) &&
+			len(p) > len(table) && slices.Equal(p[:len(table)], table) {
+			rel := append(slices.Clone(table[len(e.table):]), key)
+			d.insert(e.endLine+1, tomlKeyPath(rel)+" = "+value)
+			return nil
+		}
+	}
+	// 空のテーブルの見出しがあればその直後に追加する
+	for _, h := range d.headers {
+		if slices.Equal(h.path, table) {
+			d.insert(h.line+1, tomlKey(key)+" = "+value)
+			return nil
+		}
+	}
+	if len(table) == 0 {
+		// 最初の見出しより前に追加する
+		at := len(d.lines)
+		if len(d.headers) > 0 {
+			at = d.headers[0].line
+			d.insert(at, "")
+		}
+		d.insert(at, tomlKey(key)+" = "+value)
+		return nil
+	}
+	// 新しいテーブルを末尾に追加する
+	d.trimTrailingBlank()
+	if len(d.lines) > 0 {
+		d.lines = append(d.lines, "")
+	}
+	d.lines = append(d.lines, "["+tomlKeyPath(table)+"]", tomlKey(key)+" = "+value, "")
+	return nil
+}
+
+// remove はキーを取り除く（テーブルを指定した場合は見出しと中のキーをすべて取り除く）
+// 取り除くものがなければ false を返す
+func (d *tomlDocument) remove(path []string) (bool, error) {
+	hasPrefix := func(p []string) bool {
+		return len(p) >= len(path) && slices.Equal(p[:len(path)], path)
+	}
+
+	type span struct{ from, to int }
+	var spans []span
+	for _, e := range d.entries {
+		p := e.path()
+		if hasPrefix(p) {
+			spans = append(spans, span{e.line, e.endLine})
+			continue
+		}
+		if e.inline && len(path) > len(p) && slices.Equal(path[:len(p)], p) {
+			return false, fmt.Errorf("cannot remove %s: %s is an inline table", strings.Join(path, "."), strings.Join(p, "."))
+		}
+	}
+	for _, h := range d.headers {
+		if hasPrefix(h.path) {
+			spans = append(spans, span{h.line, h.line})
+		}
+	}
+	if len(spans) == 0 {
+		return false, nil
+	}
+
+	// 後ろから削除して行番号がずれないようにする
+	slices.SortFunc(spans, func(a, b span) int { return b.from - a.from })
+	for _, s := range spans {
+		d.lines = slices.Delete(d.lines, s.from, s.to+1)
+		// 削除した箇所で空行が続く場合は1行にまとめる
+		if s.from > 0 && s.from < len(d.lines) &&
+			strings.TrimSpace(d.lines[s.from-1]) == "" && strings.TrimSpace(d.lines[s.from]) == "" {
+			d.lines = slices.Delete(d.lines, s.from, s.from+1)
+		}
+	}
+	return true, nil
+}
+
+// replace はコメントを残して値だけを置き換える
+func (d *tomlDocument) replace(e tomlEntry, value string) {
+	first := d.lines[e.line][:e.valueStart]
+	last := d.lines[e.endLine][e.valueEnd:]
+	lines := append([]string{first + value + last}, d.lines[e.endLine+1:]...)
+	d.lines = append(d.lines[:e.line], lines...)
+}
+
+// insert は at 行目の前に行を挿入する
+func (d *tomlDocument) insert(at int, line string) {
+	if at > len(d.lines) {
+		at = len(d.lines)
+	}
+	// 末尾の改行による空の最終行の後ろには挿入しない
+	if at == len(d.lines) && at > 0 && d.lines[at-1] == "" {
+		at--
+	}
+	d.lines = slices.Insert(d.lines, at, line)
+}
+
+// trimTrailingBlank は末尾の空行を取り除く
+func (d *tomlDocument) trimTrailingBlank() {
+	for len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) == "" {
+		d.lines = d.lines[:len(d.lines)-1]
+	}
+}
+
+// bytes は書き換えた内容を返す
+func (d *tomlDocument) bytes() []byte {
+	return []byte(strings.Join(d.lines, "\n"))
+}
@@ -0,0 +1,366 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pomodoro-cli/internal/activity"
+	"pomodoro-cli/internal/chatstatus"
+	"pomodoro-cli/internal/dnd"
+	"pomodoro-cli/internal/idle"
+	"pomodoro-cli/internal/keys"
+)
+
+// idleDetections は idle_detection に指定できる値（"off" は空と同じく検出しない）
+var idleDetections = append([]string{"off"}, idle.Providers...)
+
+// idleActions は idle_action に指定できる値
+var idleActions = []string{"ask", "keep", "discard"}
+
+// dndProviders は dnd に指定できる値（"off" は空と同じく通知を止めない）
+var dndProviders = append([]string{"off"}, dnd.Providers...)
+
+// chatStatuses は chat_status に指定できる値（"off" は空と同じく表示しない）
+var chatStatuses = append([]string{"off"}, chatstatus.Providers...)
+
+// guidedBreaks は guided_break に指定できる値（"off" は空と同じく表示しない）
+var guidedBreaks = append([]string{"off"}, activity.GuideNames()...)
+
+// screenLocks は screen_lock に指定できる値（"off" は空と同じく何もしない）
+var screenLocks = []string{"off", "pause", "interrupt"}
+
+// 現実的でない値を弾くための上限
+const (
+	maxDuration          = 24 * time.Hour
+	maxSessionsUntilLong = 100
+	maxDailyGoal         = 100
+	maxWeeklyGoal        = 7 * maxDailyGoal
+)
+
+// FieldError は設定項目ごとのエラー
+type FieldError struct {
+	// Field は "work_duration" や "profiles.deep.work_duration" のようなキーのパス
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError は設定ファイル内の位置付きのエラー
+type ParseError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Line == 0:
+		return e.Path + ": " + e.Err.Error()
+	case e.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Problems は複数のエラーをまとめたエラーを個々のエラーに分解する
+func Problems(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// ----------------------------------------------------------------------------
+// 値の検証
+// ----------------------------------------------------------------------------
+
+// Validate は設定値を検証し、問題のある項目ごとの *FieldError をまとめて返す
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, &FieldError{Field: field, Err: fmt.Errorf(format, args...)})
+	}
+
+	durations := []struct {
+		field string
+		value time.Duration
+	}{
+		{"work_duration", c.WorkDuration},
+		{"short_break_duration", c.ShortBreakDuration},
+		{"long_break_duration", c.LongBreakDuration},
+	}
+	for _, d := range durations {
+		switch {
+		case d.value <= 0:
+			fail(d.field, "must be positive, got %s", formatDuration(d.value))
+		case d.value > maxDuration:
+			fail(d.field, "must be at most %s, got %s", formatDuration(maxDuration), formatDuration(d.value))
+		}
+	}
+
+	if c.SessionsUntilLong < 1 || c.SessionsUntilLong > maxSessionsUntilLong {
+		fail("sessions_until_long_break", "must be between 1 and %d, got %d", maxSessionsUntilLong, c.SessionsUntilLong)
+	}
+	if c.DailyGoal < 0 || c.DailyGoal > maxDailyGoal {
+		fail("daily_goal", "must be between 0 and %d, got %d", maxDailyGoal, c.DailyGoal)
+	}
+	if c.WeeklyGoal < 0 || c.WeeklyGoal > maxWeeklyGoal {
+		fail("weekly_goal", "must be between 0 and %d, got %d", maxWeeklyGoal, c.WeeklyGoal)
+	}
+	for _, day := range slices.Sorted(maps.Keys(c.WeekdayGoals)) {
+		field := "weekday_goals." + day
+		if !slices.Contains(weekdayNames, day) {
+			fail(field, "unknown weekday (use monday ... sunday)")
+			continue
+		}
+		if goal := c.WeekdayGoals[day]; goal < 0 || goal > maxDailyGoal {
+			fail(field, "must be between 0 and %d, got %d", maxDailyGoal, goal)
+		}
+	}
+	if c.IdleDetection != "" && !slices.Contains(idleDetections, c.IdleDetection) {
+		fail("idle_detection", "must be one of %s, got %q", strings.Join(idleDetections, ", "), c.IdleDetection)
+	}
+	switch {
+	case c.IdleThreshold < 0:
+		fail("idle_threshold", "must not be negative, got %s", formatDuration(c.IdleThreshold))
+	case c.IdleThreshold > 0 && c.IdleThreshold < time.Minute:
+		fail("idle_threshold", "must be at least 1m, got %s", formatDuration(c.IdleThreshold))
+	case c.IdleThreshold > maxDuration:
+		fail("idle_threshold", "must be at most %s, got %s", formatDuration(maxDuration), formatDuration(c.IdleThreshold))
+	}
+	if c.IdleAction != "" && !slices.Contains(idleActions, c.IdleAction) {
+		fail("idle_action", "must be one of %s, got %q", strings.Join(idleActions, ", "), c.IdleAction)
+	}
+	if c.ScreenLock != "" && !slices.Contains(screenLocks, c.ScreenLock) {
+		fail("screen_lock", "must be one of %s, got %q", strings.Join(screenLocks, ", "), c.ScreenLock)
+	}
+	if c.DND != "" && !slices.Contains(dndProviders, c.DND) {
+		fail("dnd", "must be one of %s, got %q", strings.Join(dndProviders, ", "), c.DND)
+	}
+	if c.ChatStatus != "" && !slices.Contains(chatStatuses, c.ChatStatus) {
+		fail("chat_status", "must be one of %s, got %q", strings.Join(chatStatuses, ", "), c.ChatStatus)
+	}
+	if c.ChatStatusURL != "" {
+		if u, err := url.Parse(c.ChatStatusURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("chat_status_url", "must be an http or https URL, got %q", c.ChatStatusURL)
+		}
+	}
+	if c.TeamServer != "" {
+		if u, err := url.Parse(c.TeamServer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("team_server", "must be an http or https URL, got %q", c.TeamServer)
+		} else if c.TeamToken == "" {
+			fail("team_token", "is required when team_server is set")
+		}
+	}
+	for _, breakType := range slices.Sorted(maps.Keys(c.BreakActivities)) {
+		field := "break_activities." + breakType
+		if !slices.Contains(activity.BreakTypes, breakType) {
+			fail(field, "unknown break type (use %s)", strings.Join(activity.BreakTypes, " or "))
+			continue
+		}
+		for i, entry := range c.BreakActivities[breakType] {
+			if _, err := activity.Parse(entry); err != nil {
+				fail(fmt.Sprintf("%s.%d", field, i), "%v", err)
+			}
+		}
+	}
+	if c.GuidedBreak != "" && !slices.Contains(guidedBreaks, c.GuidedBreak) {
+		fail("guided_break", "must be one of %s, got %q", strings.Join(guidedBreaks, ", "), c.GuidedBreak)
+	}
+	bound := map[keys.Key]string{}
+	for _, name := range slices.Sorted(maps.Keys(c.Keybindings)) {
+		field := "keybindings." + name
+		k, err := keys.Parse(name)
+		if err != nil {
+			fail(field, "%v", err)
+			continue
+		}
+		if _, err := keys.ParseAction(c.Keybindings[name]); err != nil {
+			fail(field, "%v", err)
+			continue
+		}
+		if other, ok := bound[k]; ok {
+			fail(field, "same key as %q", other)
+			continue
+		}
+		bound[k] = name
+	}
+	if _, err := keys.NewBindings(c.Keybindings); errors.Is(err, keys.ErrNoQuitKey) {
+		fail("keybindings", "%v", err)
+	}
+	for i, tag := range c.Tags {
+		if strings.TrimSpace(tag) == "" {
+			fail(fmt.Sprintf("tags.%d", i), "must not be empty")
+		}
+	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			fail("default_profile", "profile %q is not defined", c.DefaultProfile)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ----------------------------------------------------------------------------
+// 読み込み時のエラー
+// ----------------------------------------------------------------------------
+
+// decodeField は1つのキーの値を cfg に読み込む
+// 失敗した場合は field をパスとする *FieldError を返す
+func decodeField(cfg *Config, field, key string, value any) error {
+	data, err := json.Marshal(map[string]any{key: value})
+	if err != nil {
+		return &FieldError{Field: field, Err: err}
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return &FieldError{Field: field, Err: describeDecodeError(err)}
+	}
+	return nil
+}
+
+// describeDecodeError は型の不一致を読みやすいメッセージにする
+func describeDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	return fmt.Errorf("expected %s, got %s", kindName(typeErr.Type), typeErr.Value)
+}
+
+// kindName は型の説明を返す
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return t.String()
+	}
+}
+
+// locate はエラーに設定ファイル内の位置を付ける
+func locate(path string, data []byte, positions map[string]position, err error) *ParseError {
+	if pos, msg, ok := syntaxErrorPosition(data, err); ok {
+		return &ParseError{Path: path, Line: pos.line, Column: pos.column, Err: errors.New(msg)}
+	}
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		// キーが見つからなければ親のキーの位置を使う
+		for field := fieldErr.Field; field != ""; {
+			if pos, ok := positions[field]; ok {
+				return &ParseError{Path: path, Line: pos.line, Column: pos.column, Err: err}
+			}
+			i := strings.LastIndex(field, ".")
+			if i < 0 {
+				break
+			}
+			field = field[:i]
+		}
+	}
+	return &ParseError{Path: path, Err: err}
+}
+
+// locateAll はエラーに設定ファイル内の位置を付け、ファイル内の順に並べてまとめる
+func locateAll(path string, format Format, data []byte, errs []error) error {
+	positions := keyPositions(format, data)
+	located := make([]*ParseError, len(errs))
+	for i, err := range errs {
+		located[i] = locate(path, data, positions, err)
+	}
+	slices.SortStableFunc(located, func(a, b *ParseError) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	joined := make([]error, len(located))
+	for i, err := range located {
+		joined[i] = err
+	}
+	return errors.Join(joined...)
+}
+
+// fieldOffsets はJSONの各キーの開始位置をキーのパスごとに返す
+func fieldOffsets(data []byte) map[string]int64 {
+	offsets := make(map[string]int64)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(prefix string) error
+	walk = func(prefix string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				start := dec.InputOffset()
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				path := key
+				if prefix != "" {
+					path = prefix + "." + key
+				}
+				offsets[path] = start + int64(len(data[start:])-len(bytes.TrimLeft(data[start:], " \t\r\n,")))
+				if err := walk(path); err != nil {
+					return err
+				}
+			}
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s.%d", prefix, i)); err != nil {
+					return err
+				}
+			}
+		default:
+			return nil
+		}
+		_, err = dec.Token()
+		return err
+	}
+	_ = walk("")
+	return offsets
+}
+
+// lineColumn はオフセットを1始まりの行と列（文字単位）に変換する
+func lineColumn(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Validate - 設定値の検証
+// =============================================================================
+
+func TestValidateはデフォルト設定を受け付ける(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestValidateは問題のある項目ごとにエラーを返す(t *testing.T) {
+	cfg := Default()
+	cfg.WorkDuration = 0
+	cfg.ShortBreakDuration = -5 * time.Minute
+	cfg.LongBreakDuration = 48 * time.Hour
+	cfg.SessionsUntilLong = 0
+	cfg.DailyGoal = -1
+	cfg.WeekdayGoals = map[string]int{"funday": 1, "friday": 500}
+	cfg.DefaultProfile = "missing"
+
+	got := fieldsOf(cfg.Validate())
+	expected := []string{
+		"work_duration",
+		"short_break_duration",
+		"long_break_duration",
+		"sessions_until_long_break",
+		"daily_goal",
+		"weekday_goals.friday",
+		"weekday_goals.funday",
+		"default_profile",
+	}
+	if !slices.Equal(got, expected) {
+		t.Errorf("invalid fields = %v, want %v", got, expected)
+	}
+}
+
+func TestValidateはキーの割り当てのキー名とアクションを検証する(t *testing.T) {
+	cfg := Default()
+	cfg.Keybindings = map[string]string{
+		"ctrl+s":  "skip",
+		"p":       "pause",
+		"hyper+x": "quit",
+		"x":       "explode",
+		"Ctrl+S":  "quit",
+	}
+
+	got := fieldsOf(cfg.Validate())
+	expected := []string{"keybindings.ctrl+s", "keybindings.hyper+x", "keybindings.x"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("invalid fields = %v, want %v", got, expected)
+	}
+}
+
+func TestValidateは終了するキーがなくなる割り当てを拒否する(t *testing.T) {
+	cfg := Default()
+	cfg.Keybindings = map[string]string{"ctrl+c": "none", "q": "none", "Q": "none"}
+
+	got := fieldsOf(cfg.Validate())
+	if !slices.Equal(got, []string{"keybindings"}) {
+		t.Errorf("invalid fields = %v, want [keybindings]", got)
+	}
+
+	cfg.Keybindings["x"] = "quit"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with another quit key error = %v, want nil", err)
+	}
+}
+
+// =============================================================================
+// LoadBase - 位置付きのエラー
+// =============================================================================
+
+func TestValidateは離席の検出の設定を検証する(t *testing.T) {
+	cfg := Default()
+	cfg.IdleDetection = "x11"
+	cfg.IdleThreshold = 10 * time.Minute
+	cfg.IdleAction = "discard"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	cfg.IdleDetection = "screensaver"
+	cfg.IdleThreshold = 30 * time.Second
+	cfg.IdleAction = "ignore"
+	got := fieldsOf(cfg.Validate())
+	expected := []string{"idle_detection", "idle_threshold", "idle_action"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("invalid fields = %v, want %v", got, expected)
+	}
+}
+
+func TestValidateは画面ロックの扱いを検証する(t *testing.T) {
+	cfg := Default()
+	cfg.ScreenLock = "interrupt"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	cfg.ScreenLock = "logout"
+	got := fieldsOf(cfg.Validate())
+	if !slices.Equal(got, []string{"screen_lock"}) {
+		t.Errorf("invalid fields = %v, want [screen_lock]", got)
+	}
+}
+
+func TestValidateは通知を止める方法を検証する(t *testing.T) {
+	cfg := Default()
+	for _, provider := range []string{"off", "auto", "gnome", "dunst", "mako"} {
+		cfg.DND = provider
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate() with dnd %q error = %v", provider, err)
+		}
+	}
+
+	cfg.DND = "kde"
+	if got := fieldsOf(cfg.Validate()); !slices.Equal(got, []string{"dnd"}) {
+		t.Errorf("invalid fields = %v, want [dnd]", got)
+	}
+}
+
+func TestValidateはチャットのステータスの設定を検証する(t *testing.T) {
+	cfg := Default()
+	cfg.ChatStatus = "mattermost"
+	cfg.ChatStatusURL = "https://chat.example.com"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	cfg.ChatStatus = "teams"
+	cfg.ChatStatusURL = "chat.example.com"
+	got := fieldsOf(cfg.Validate())
+	if !slices.Equal(got, []string{"chat_status", "chat_status_url"}) {
+		t.Errorf("invalid fields = %v, want [chat_status chat_status_url]", got)
+	}
+}
+
+func TestValidateはチームサーバーの設定を検証する(t *testing.T) {
+	cfg := Default()
+	cfg.TeamServer = "https://pomodoro.example.com"
+	cfg.TeamToken = "secret"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	cfg.TeamToken = ""
+	if got := fieldsOf(cfg.Validate()); !slices.Equal(got, []string{"team_token"}) {
+		t.Errorf("invalid fields = %v, want [team_token]", got)
+	}
+	cfg.TeamServer = "pomodoro.example.com"
+	if got := fieldsOf(cfg.Validate()); !slices.Equal(got, []string{"team_server"}) {
+		t.Errorf("invalid fields = %v, want [team_server]", got)
+	}
+}
+
+func TestValidateは休憩の過ごし方とガイドを検証する(t *testing.T) {
+	cfg := Default()
+	cfg.BreakActivities = map[string][]string{"short_break": {"3x Stretch", "Drink water"}, "long_break": {}}
+	cfg.GuidedBreak = "4-7-8"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	cfg.BreakActivities = map[string][]string{"short_break": {"Stretch", "0x Water"}, "lunch": {"Eat"}}
+	cfg.GuidedBreak = "yoga"
+	got := fieldsOf(cfg.Validate())
+	want := []string{"break_activities.lunch", "break_activities.short_break.1", "guided_break"}
+	if !slices.Equal(got, want) {
+		t.Errorf("invalid fields = %v, want %v", got, want)
+	}
+}
+
+func TestLoadBaseは構文エラーの行と列を報告する(t *testing.T) {
+	path := writeConfigFile(t, "{\n  \"version\": 2,\n  \"work_duration\": 25m\n}\n")
+
+	cfg, err := LoadBase()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("LoadBase() error = %v, want *ParseError", err)
+	}
+	if parseErr.Path != path || parseErr.Line != 3 || parseErr.Column != 22 {
+		t.Errorf("position = %s:%d:%d, want %s:3:22", parseErr.Path, parseErr.Line, parseErr.Column, path)
+	}
+	if cfg.WorkDuration != 25*time.Minute {
+		t.Errorf("LoadBase() should fall back to the defaults, got %v", cfg.WorkDuration)
+	}
+}
+
+func TestLoadBaseは不正な値をキーの位置とともにすべて報告する(t *testing.T) {
+	writeConfigFile(t, `{
+  "version": 2,
+  "work_duration": "-5m",
+  "sound_enabled": "yes",
+  "sessions_until_long_break": 0,
+  "profiles": {
+    "deep": {"short_break_duration": "0s"}
+  }
+}`)
+
+	_, err := LoadBase()
+	problems := Problems(err)
+	expected := []string{
+		":3:3: work_duration: must be positive, got -5m",
+		":4:3: sound_enabled: expected true or false, got string",
+		":5:3: sessions_until_long_break: must be between 1 and 100, got 0",
+		":7:14: profiles.deep.short_break_duration: must be positive, got 0s",
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("problems = %v, want %d", problems, len(expected))
+	}
+	for i, want := range expected {
+		if !strings.HasSuffix(problems[i].Error(), want) {
+			t.Errorf("problems[%d] = %q, want suffix %q", i, problems[i], want)
+		}
+	}
+}
+
+func TestLoadProfileは不正なファイルのエラーをプロファイル未定義より優先する(t *testing.T) {
+	writeConfigFile(t, `{"version": 2, "sessions_until_long_break": 0}`)
+
+	_, err := LoadProfile("deep")
+	if errors.Is(err, ErrUnknownProfile) || len(Problems(err)) != 1 {
+		t.Errorf("LoadProfile() error = %v, want the validation error", err)
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// fieldsOf は検証エラーの項目名を返す
+func fieldsOf(err error) []string {
+	var fields []string
+	for _, p := range Problems(err) {
+		var fieldErr *FieldError
+		if errors.As(p, &fieldErr) {
+			fields = append(fields, fieldErr.Field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,291 @@
+// Package dnd は作業中に他のアプリの通知を止める（おやすみモード）
+// 通知デーモンごとの Provider で切り替え、止める前の状態を状態ファイルに残して
+// 終了時やクラッシュ後の次回起動時に元に戻す
+package dnd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Provider は通知デーモンのおやすみモードを調べて切り替える
+type Provider interface {
+	Name() string
+	// Enabled はおやすみモード（通知を表示しない状態）かどうかを返す
+	Enabled() (bool, error)
+	// Set はおやすみモードを切り替える
+	Set(enabled bool) error
+}
+
+// CommandOutput は外部コマンドを実行して標準出力を返す（テストで差し替える）
+type CommandOutput func(name string, args ...string) ([]byte, error)
+
+// execOutput はコマンドを実行して標準出力を返す
+func execOutput(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// Providers は設定の dnd に指定できる切り替え方法
+var Providers = []string{"auto", "gnome", "dunst", "mako"}
+
+// New は名前に対応する Provider を返す
+// "auto" は GNOME のデスクトップなら gsettings、なければ dunstctl、makoctl の順に探す
+func New(name string) (Provider, error) {
+	return NewWithOutput(name, execOutput)
+}
+
+// NewWithOutput はコマンドの実行方法を指定して Provider を返す
+func NewWithOutput(name string, output CommandOutput) (Provider, error) {
+	switch name {
+	case "gnome":
+		return &GNOME{output: output}, nil
+	case "dunst":
+		return &Dunst{output: output}, nil
+	case "mako":
+		return &Mako{output: output}, nil
+	case "auto":
+		desktop := strings.ToUpper(os.Getenv("XDG_CURRENT_DESKTOP"))
+		if _, err := exec.LookPath("gsettings"); err == nil && strings.Contains(desktop, "GNOME") {
+			return &GNOME{output: output}, nil
+		}
+		if _, err := exec.LookPath("dunstctl"); err == nil {
+			return &Dunst{output: output}, nil
+		}
+		if _, err := exec.LookPath("makoctl"); err == nil {
+			return &Mako{output: output}, nil
+		}
+		return nil, errors.New("no supported notification daemon found (gnome, dunst or mako)")
+	default:
+		return nil, fmt.Errorf("unknown dnd provider %q (use one of: %s)", name, strings.Join(Providers, ", "))
+	}
+}
+
+// run はコマンドを実行し、失敗したらコマンド名を含むエラーを返す
+func run(output CommandOutput, name string, args ...string) (string, error) {
+	out, err := output(name, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// parseBool は true/false を出力するコマンドの結果を読む
+func parseBool(name, out string) (bool, error) {
+	switch out {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected %s output %q", name, out)
+}
+
+// ----------------------------------------------------------------------------
+// GNOME
+// ----------------------------------------------------------------------------
+
+// GNOME は org.gnome.desktop.notifications の show-banners を gsettings で切り替える
+type GNOME struct {
+	output CommandOutput
+}
+
+// Name は切り替え方法の名前を返す
+func (g *GNOME) Name() string {
+	return "gnome"
+}
+
+// Enabled はバナーを表示しない設定ならおやすみモードとみなす
+func (g *GNOME) Enabled() (bool, error) {
+	out, err := run(g.output, "gsettings", "get", "org.gnome.desktop.notifications", "show-banners")
+	if err != nil {
+		return false, err
+	}
+	show, err := parseBool("gsettings", out)
+	return !show, err
+}
+
+// Set はおやすみモードならバナーを隠す
+func (g *GNOME) Set(enabled bool) error {
+	_, err := run(g.output, "gsettings", "set", "org.gnome.desktop.notifications", "show-banners", fmt.Sprint(!enabled))
+	return err
+}
+
+// ----------------------------------------------------------------------------
+// dunst
+// ----------------------------------------------------------------------------
+
+// Dunst は dunst の通知の一時停止を dunstctl で切り替える
+type Dunst struct {
+	output CommandOutput
+}
+
+// Name は切り替え方法の名前を返す
+func (d *Dunst) Name() string {
+	return "dunst"
+}
+
+// Enabled は通知を一時停止しているかを返す
+func (d *Dunst) Enabled() (bool, error) {
+	out, err := run(d.output, "dunstctl", "is-paused")
+	if err != nil {
+		return false, err
+	}
+	return parseBool("dunstctl", out)
+}
+
+// Set は通知を一時停止または再開する
+func (d *Dunst) Set(enabled bool) error {
+	_, err := run(d.output, "dunstctl", "set-paused", fmt.Sprint(enabled))
+	return err
+}
+
+// ----------------------------------------------------------------------------
+// mako
+// ----------------------------------------------------------------------------
+
+// makoMode はおやすみモードに使う mako のモード名（設定で invisible=1 にしておく）
+const makoMode = "do-not-disturb"
+
+// Mako は mako の do-not-disturb モードを makoctl で切り替える
+type Mako struct {
+	output CommandOutput
+}
+
+// Name は切り替え方法の名前を返す
+func (m *Mako) Name() string {
+	return "mako"
+}
+
+// Enabled は do-not-disturb モードが有効かを返す
+func (m *Mako) Enabled() (bool, error) {
+	out, err := run(m.output, "makoctl", "mode")
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(strings.Fields(out), makoMode), nil
+}
+
+// Set は do-not-disturb モードを追加または削除する
+func (m *Mako) Set(enabled bool) error {
+	flag := "-r"
+	if enabled {
+		flag = "-a"
+	}
+	_, err := run(m.output, "makoctl", "mode", flag, makoMode)
+	return err
+}
+
+// ----------------------------------------------------------------------------
+// 状態の保存と復元
+// ----------------------------------------------------------------------------
+
+// state はおやすみモードにする前の状態（クラッシュ後に元に戻すため保存する）
+type state struct {
+	Provider string `json:"provider"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// StateFile は設定ディレクトリに置く状態ファイルの名前
+const StateFile = "dnd-state.json"
+
+// Controller は作業セッションの間だけおやすみモードにし、終了時に元の状態に戻す
+type Controller struct {
+	provider  Provider
+	statePath string
+	mu        sync.Mutex
+	active    bool
+}
+
+// NewController は statePath に元の状態を残す Controller を作成する
+func NewController(provider Provider, statePath string) *Controller {
+	return &Controller{provider: provider, statePath: statePath}
+}
+
+// Begin はおやすみモードにする（すでにおやすみモードなら何もしない）
+func (c *Controller) Begin() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.active {
+		return nil
+	}
+	enabled, err := c.provider.Enabled()
+	if err != nil {
+		return err
+	}
+	if enabled {
+		return nil
+	}
+	// 切り替える前に元の状態を残しておく
+	data, err := json.Marshal(state{Provider: c.provider.Name(), Enabled: enabled})
+	if err != nil {
+		return fmt.Errorf("failed to encode dnd state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.statePath), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(c.statePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to save dnd state: %w", err)
+	}
+	if err := c.provider.Set(true); err != nil {
+		// 切り替えていないので状態ファイルを消す（残すと次回の起動で Recover が元に戻そうとする）
+		if rmErr := os.Remove(c.statePath); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			return errors.Join(err, fmt.Errorf("failed to remove dnd state: %w", rmErr))
+		}
+		return err
+	}
+	c.active = true
+	return nil
+}
+
+// End は Begin でおやすみモードにしていれば元の状態に戻す
+func (c *Controller) End() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.active {
+		return nil
+	}
+	if err := c.provider.Set(false); err != nil {
+		return err
+	}
+	c.active = false
+	if err := os.Remove(c.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove dnd state: %w", err)
+	}
+	return nil
+}
+
+// Recover は前回の実行が元に戻さずに終了していた場合に通知の状態を戻す（戻した場合 true を返す）
+// provider は保存された名前から Provider を作る
+func Recover(statePath string, provider func(name string) (Provider, error)) (bool, error) {
+	data, err := os.ReadFile(statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read dnd state: %w", err)
+	}
+	var saved state
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return false, fmt.Errorf("failed to parse dnd state %s: %w", statePath, err)
+	}
+	p, err := provider(saved.Provider)
+	if err != nil {
+		return false, err
+	}
+	if err := p.Set(saved.Enabled); err != nil {
+		return false, err
+	}
+	if err := os.Remove(statePath); err != nil {
+		return false, fmt.Errorf("failed to remove dnd state: %w", err)
+	}
+	return true, nil
+}
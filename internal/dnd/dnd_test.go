@@ -0,0 +1,228 @@
+package dnd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// =============================================================================
+// Provider - 通知デーモンの切り替え
+// =============================================================================
+
+// fakeOutput はコマンドを記録し、replies の出力を返す CommandOutput を作る
+func fakeOutput(calls *[]string, replies map[string]string) CommandOutput {
+	return func(name string, args ...string) ([]byte, error) {
+		call := strings.Join(append([]string{name}, args...), " ")
+		*calls = append(*calls, call)
+		return []byte(replies[call]), nil
+	}
+}
+
+func TestProviderは通知デーモンのコマンドで状態を調べて切り替える(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies map[string]string
+		enabled bool
+		calls   []string
+	}{
+		{
+			name:    "gnome",
+			replies: map[string]string{"gsettings get org.gnome.desktop.notifications show-banners": "true\n"},
+			enabled: false,
+			calls: []string{
+				"gsettings get org.gnome.desktop.notifications show-banners",
+				"gsettings set org.gnome.desktop.notifications show-banners false",
+				"gsettings set org.gnome.desktop.notifications show-banners true",
+			},
+		},
+		{
+			name:    "dunst",
+			replies: map[string]string{"dunstctl is-paused": "true\n"},
+			enabled: true,
+			calls:   []string{"dunstctl is-paused", "dunstctl set-paused true", "dunstctl set-paused false"},
+		},
+		{
+			name:    "mako",
+			replies: map[string]string{"makoctl mode": "default\ndo-not-disturb\n"},
+			enabled: true,
+			calls:   []string{"makoctl mode", "makoctl mode -a do-not-disturb", "makoctl mode -r do-not-disturb"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			p, err := NewWithOutput(tt.name, fakeOutput(&calls, tt.replies))
+			if err != nil {
+				t.Fatalf("NewWithOutput() error = %v", err)
+			}
+			enabled, err := p.Enabled()
+			if err != nil {
+				t.Fatalf("Enabled() error = %v", err)
+			}
+			if enabled != tt.enabled {
+				t.Errorf("Enabled() = %v, want %v", enabled, tt.enabled)
+			}
+			if err := p.Set(true); err != nil {
+				t.Fatalf("Set(true) error = %v", err)
+			}
+			if err := p.Set(false); err != nil {
+				t.Fatalf("Set(false) error = %v", err)
+			}
+			if !reflect.DeepEqual(calls, tt.calls) {
+				t.Errorf("calls = %q, want %q", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestProviderはコマンドの失敗と不正な出力をエラーにする(t *testing.T) {
+	p, _ := NewWithOutput("dunst", func(string, ...string) ([]byte, error) {
+		return []byte("maybe"), nil
+	})
+	if _, err := p.Enabled(); err == nil {
+		t.Error("Enabled() error = nil, want error for unexpected output")
+	}
+
+	p, _ = NewWithOutput("gnome", func(string, ...string) ([]byte, error) {
+		return nil, errors.New("not found")
+	})
+	if err := p.Set(true); err == nil || !strings.Contains(err.Error(), "gsettings") {
+		t.Errorf("Set() error = %v, want gsettings error", err)
+	}
+
+	if _, err := New("kde"); err == nil {
+		t.Error("New(kde) error = nil, want error")
+	}
+}
+
+// =============================================================================
+// Controller - 状態の保存と復元
+// =============================================================================
+
+// fakeProvider はメモリ上でおやすみモードを切り替える Provider
+type fakeProvider struct {
+	enabled bool
+	sets    int
+	// setErr は Set が返すエラー、onSet は Set の中で呼ぶ関数
+	setErr error
+	onSet  func()
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) Enabled() (bool, error) { return f.enabled, nil }
+
+func (f *fakeProvider) Set(enabled bool) error {
+	if f.onSet != nil {
+		f.onSet()
+	}
+	if f.setErr != nil {
+		return f.setErr
+	}
+	f.enabled = enabled
+	f.sets++
+	return nil
+}
+
+func TestControllerは元の状態を保存しておやすみモードを切り替える(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), StateFile)
+	provider := &fakeProvider{}
+	c := NewController(provider, statePath)
+
+	if err := c.Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if !provider.enabled {
+		t.Fatal("Begin() でおやすみモードにならない")
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		t.Fatalf("状態ファイルがない: %v", err)
+	}
+	if err := c.Begin(); err != nil || provider.sets != 1 {
+		t.Errorf("2回目の Begin() で切り替えた: sets = %d, err = %v", provider.sets, err)
+	}
+
+	if err := c.End(); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	if provider.enabled {
+		t.Error("End() で元に戻らない")
+	}
+	if _, err := os.Stat(statePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("End() のあとに状態ファイルが残っている: %v", err)
+	}
+}
+
+func TestControllerは切り替えに失敗したら状態ファイルを消す(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), StateFile)
+	setErr := errors.New("makoctl failed")
+	c := NewController(&fakeProvider{setErr: setErr}, statePath)
+
+	if err := c.Begin(); !errors.Is(err, setErr) {
+		t.Fatalf("Begin() error = %v, want %v", err, setErr)
+	}
+	if _, err := os.Stat(statePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("切り替えに失敗したのに状態ファイルが残っている: %v", err)
+	}
+
+	// 状態ファイルを消せなければ、そのことも知らせる
+	provider := &fakeProvider{setErr: setErr, onSet: func() {
+		_ = os.Remove(statePath)
+		if err := os.MkdirAll(filepath.Join(statePath, "busy"), 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+	}}
+	err := NewController(provider, statePath).Begin()
+	if !errors.Is(err, setErr) || !strings.Contains(err.Error(), "failed to remove dnd state") {
+		t.Errorf("Begin() error = %v, want both the switch and the removal errors", err)
+	}
+}
+
+func TestControllerはすでにおやすみモードなら触らない(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), StateFile)
+	provider := &fakeProvider{enabled: true}
+	c := NewController(provider, statePath)
+
+	if err := c.Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if err := c.End(); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	if !provider.enabled || provider.sets != 0 {
+		t.Errorf("enabled = %v, sets = %d, want untouched", provider.enabled, provider.sets)
+	}
+}
+
+func TestRecoverはクラッシュで残った状態を元に戻す(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), StateFile)
+	provider := &fakeProvider{}
+	if err := NewController(provider, statePath).Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	// End() を呼ばずに終了したことにする
+
+	var requested string
+	restored, err := Recover(statePath, func(name string) (Provider, error) {
+		requested = name
+		return provider, nil
+	})
+	if err != nil || !restored {
+		t.Fatalf("Recover() = %v, %v, want true", restored, err)
+	}
+	if requested != "fake" || provider.enabled {
+		t.Errorf("provider = %q, enabled = %v", requested, provider.enabled)
+	}
+
+	restored, err = Recover(statePath, func(string) (Provider, error) {
+		t.Error("状態ファイルがないのに Provider を作った")
+		return provider, nil
+	})
+	if err != nil || restored {
+		t.Errorf("2回目の Recover() = %v, %v, want false", restored, err)
+	}
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/history"
+)
+
+// Format は出力形式を表す
+type Format string
+
+const (
+	FormatCSV         Format = "csv"
+	FormatJSON        Format = "json"
+	FormatICS         Format = "ics"
+	FormatTimewarrior Format = "timew" // `timew import` 用のJSON
+	FormatOrg         Format = "org"   // org-mode の CLOCK: 行
+)
+
+// Formats は対応している出力形式の一覧
+var Formats = []Format{FormatCSV, FormatJSON, FormatICS, FormatTimewarrior, FormatOrg}
+
+// ParseFormat は文字列から出力形式を返す
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format: %q", s)
+}
+
+// Write はレコードを指定された形式で書き込む
+// now はICSのDTSTAMPに使う
+func Write(w io.Writer, format Format, records []history.Record, now time.Time) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, records)
+	case FormatJSON:
+		return WriteJSON(w, records)
+	case FormatICS:
+		return WriteICS(w, records, now)
+	case FormatTimewarrior:
+		return WriteTimewarrior(w, records)
+	case FormatOrg:
+		return WriteOrg(w, records)
+	default:
+		return fmt.Errorf("unknown format: %q", format)
+	}
+}
+
+// Filter は開始時刻が [since, until) の範囲にあるレコードを返す
+// ゼロ値の境界は制限なしとして扱う
+func Filter(records []history.Record, since, until time.Time) []history.Record {
+	var filtered []history.Record
+	for _, r := range records {
+		if !since.IsZero() && r.StartedAt.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !r.StartedAt.Before(until) {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// ----------------------------------------------------------------------------
+// 期間の指定
+// ----------------------------------------------------------------------------
+
+// relativePattern は "7d" や "12h" のような相対指定
+var relativePattern = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// ParseSince は --since の値を解析する
+// 日付（2006-01-02）はその日の0時、相対指定（7d, 2w, 36h）は now からの経過前を表す
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	t, _, err := parseTime(s, now)
+	return t, err
+}
+
+// ParseUntil は --until の値を解析する
+// 日付のみの指定はその日を含めるため翌日の0時を返す
+func ParseUntil(s string, now time.Time) (time.Time, error) {
+	t, dateOnly, err := parseTime(s, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if dateOnly {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// parseTime は日付・日時・相対指定を解析する（日付のみの場合 dateOnly が true）
+func parseTime(s string, now time.Time) (t time.Time, dateOnly bool, err error) {
+	if s == "" {
+		return time.Time{}, false, nil
+	}
+	if d, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return d, true, nil
+	}
+	if d, err := time.Parse(time.RFC3339, s); err == nil {
+		return d, false, nil
+	}
+	if m := relativePattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		return now.AddDate(0, 0, -n), false, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid time %q (use YYYY-MM-DD, RFC 3339 or a relative value like 7d)", s)
+}
+
+// ----------------------------------------------------------------------------
+// CSV / JSON
+// ----------------------------------------------------------------------------
+
+// csvHeader はCSVのヘッダー行
+var csvHeader = []string{
+	"type", "task", "tags", "start", "end",
+	"planned_seconds", "actual_seconds", "interruptions", "outcome",
+}
+
+// WriteCSV はレコードをCSVで書き込む（タグは ; 区切り）
+func WriteCSV(w io.Writer, records []history.Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{
+			sessionTypeText(r),
+			r.Task,
+			strings.Join(r.Tags, ";"),
+			r.StartedAt.Format(time.RFC3339),
+			r.EndedAt.Format(time.RFC3339),
+			strconv.Itoa(r.PlannedSeconds),
+			strconv.Itoa(r.ActualSeconds),
+			strconv.Itoa(r.Interruptions),
+			string(r.Outcome),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON はレコードをJSON配列で書き込む
+func WriteJSON(w io.Writer, records []history.Record) error {
+	if records == nil {
+		records = []history.Record{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// sessionTypeText はセッション種類の識別子を返す
+func sessionTypeText(r history.Record) string {
+	text, err := r.Type.MarshalText()
+	if err != nil {
+		return "unknown"
+	}
+	return string(text)
+}
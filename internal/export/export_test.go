@@ -0,0 +1,245 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/plan"
+	"pomodoro-cli/internal/timer"
+)
+
+var (
+	jst     = time.FixedZone("JST", 9*60*60)
+	started = time.Date(2026, 1, 5, 9, 0, 0, 0, jst)
+	now     = time.Date(2026, 1, 10, 12, 0, 0, 0, jst)
+)
+
+// =============================================================================
+// ParseFormat - 出力形式の解析
+// =============================================================================
+
+func TestParseFormatは対応形式を受け付ける(t *testing.T) {
+	for _, s := range []string{"csv", "JSON", "ics", "timew", "org"} {
+		if _, err := ParseFormat(s); err != nil {
+			t.Errorf("ParseFormat(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") error = nil, want error")
+	}
+}
+
+// =============================================================================
+// Filter / ParseSince / ParseUntil - 期間の指定
+// =============================================================================
+
+func TestFilterは開始時刻で範囲を絞り込む(t *testing.T) {
+	records := []history.Record{
+		{StartedAt: started.AddDate(0, 0, -1)},
+		{StartedAt: started},
+		{StartedAt: started.AddDate(0, 0, 1)},
+	}
+
+	got := Filter(records, started, started.AddDate(0, 0, 1))
+	if len(got) != 1 || !got[0].StartedAt.Equal(started) {
+		t.Errorf("Filter() = %+v, want only the middle record", got)
+	}
+
+	if got := Filter(records, time.Time{}, time.Time{}); len(got) != 3 {
+		t.Errorf("Filter() without bounds = %d records, want 3", len(got))
+	}
+}
+
+func TestParseUntilは日付のみなら当日を含める(t *testing.T) {
+	got, err := ParseUntil("2026-01-05", now)
+	if err != nil {
+		t.Fatalf("ParseUntil() error = %v", err)
+	}
+	want := time.Date(2026, 1, 6, 0, 0, 0, 0, jst)
+	if !got.Equal(want) {
+		t.Errorf("ParseUntil() = %v, want %v", got, want)
+	}
+}
+
+func TestParseSinceは相対指定と日時を受け付ける(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"2026-01-05", time.Date(2026, 1, 5, 0, 0, 0, 0, jst)},
+		{"2026-01-05T09:30:00+09:00", time.Date(2026, 1, 5, 9, 30, 0, 0, jst)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"36h", now.Add(-36 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseSince(%q) error = %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+
+	if _, err := ParseSince("last tuesday", now); err == nil {
+		t.Error("ParseSince(\"last tuesday\") error = nil, want error")
+	}
+}
+
+// =============================================================================
+// WriteCSV / WriteJSON
+// =============================================================================
+
+func TestWriteCSVは1セッション1行で書き込む(t *testing.T) {
+	var b strings.Builder
+	if err := WriteCSV(&b, []history.Record{sampleRecord()}); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	expected := "type,task,tags,start,end,planned_seconds,actual_seconds,interruptions,outcome\n" +
+		"work,\"Write docs, part 1\",docs;writing,2026-01-05T09:00:00+09:00,2026-01-05T09:27:00+09:00,1500,1500,1,completed\n"
+	if got := b.String(); got != expected {
+		t.Errorf("WriteCSV() =\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestWriteJSONは空でも配列を書き込む(t *testing.T) {
+	var b strings.Builder
+	if err := WriteJSON(&b, nil); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if got := strings.TrimSpace(b.String()); got != "[]" {
+		t.Errorf("WriteJSON(nil) = %q, want []", got)
+	}
+}
+
+func TestWriteJSONは履歴と同じフィールドで書き込む(t *testing.T) {
+	var b strings.Builder
+	if err := WriteJSON(&b, []history.Record{sampleRecord()}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded []history.Record
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Task != "Write docs, part 1" || decoded[0].Type != timer.SessionWork {
+		t.Errorf("decoded = %+v", decoded)
+	}
+}
+
+// =============================================================================
+// WriteICS - iCalendar
+// =============================================================================
+
+func TestWriteICSはVEVENTをCRLFで書き込む(t *testing.T) {
+	var b strings.Builder
+	if err := WriteICS(&b, []history.Record{sampleRecord()}, now); err != nil {
+		t.Fatalf("WriteICS() error = %v", err)
+	}
+	out := b.String()
+
+	expectedLines := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"VERSION:2.0\r\n",
+		"BEGIN:VEVENT\r\n",
+		"UID:20260105T000000Z-work@pomodoro-cli\r\n",
+		"DTSTAMP:20260110T030000Z\r\n",
+		"DTSTART:20260105T000000Z\r\n",
+		"DTEND:20260105T002700Z\r\n",
+		"SUMMARY:Work: Write docs\\, part 1\r\n",
+		"CATEGORIES:docs,writing\r\n",
+		"END:VEVENT\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(out, line) {
+			t.Errorf("ICS output missing %q", line)
+		}
+	}
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Error("ICS output contains bare LF")
+	}
+}
+
+func TestWriteICSは長い行をマルチバイト文字の途中で折り返さない(t *testing.T) {
+	r := sampleRecord()
+	r.Task = strings.Repeat("集中", 40)
+
+	var b strings.Builder
+	if err := WriteICS(&b, []history.Record{r}, now); err != nil {
+		t.Fatalf("WriteICS() error = %v", err)
+	}
+
+	for _, line := range strings.Split(b.String(), "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("line exceeds %d octets: %q", icsLineLimit, line)
+		}
+		if !utf8.ValidString(strings.TrimPrefix(line, " ")) {
+			t.Errorf("line split inside a multi-byte character: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:Work: "+r.Task+"\r\n") {
+		t.Error("unfolded SUMMARY does not match the task")
+	}
+}
+
+func TestWritePlanICSは作業セッションだけを予定ありにする(t *testing.T) {
+	p := plan.Plan{Slots: []plan.Slot{
+		{Type: timer.SessionWork, Start: started, End: started.Add(25 * time.Minute), Number: 1},
+		{Type: timer.SessionShortBreak, Start: started.Add(25 * time.Minute), End: started.Add(30 * time.Minute)},
+		{Type: timer.SessionWork, Start: started.Add(30 * time.Minute), End: started.Add(55 * time.Minute), Number: 2},
+	}}
+
+	var b strings.Builder
+	if err := WritePlanICS(&b, p, "Write docs", []string{"docs"}, now); err != nil {
+		t.Fatalf("WritePlanICS() error = %v", err)
+	}
+	out := b.String()
+
+	expectedLines := []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:plan-20260105T000000Z-work@pomodoro-cli\r\n",
+		"DTSTART:20260105T000000Z\r\n",
+		"SUMMARY:Focus 1/2: Write docs\r\nCATEGORIES:docs\r\nTRANSP:OPAQUE\r\n",
+		"SUMMARY:Short Break\r\nTRANSP:TRANSPARENT\r\n",
+		"SUMMARY:Focus 2/2: Write docs\r\n",
+		"END:VCALENDAR\r\n",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(out, line) {
+			t.Errorf("ICS output missing %q", line)
+		}
+	}
+	if got := strings.Count(out, "BEGIN:VEVENT"); got != 3 {
+		t.Errorf("VEVENT count = %d, want 3", got)
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func sampleRecord() history.Record {
+	return history.Record{
+		Type:           timer.SessionWork,
+		Task:           "Write docs, part 1",
+		Tags:           []string{"docs", "writing"},
+		StartedAt:      started,
+		EndedAt:        started.Add(27 * time.Minute),
+		PlannedSeconds: 1500,
+		ActualSeconds:  1500,
+		PausedSeconds:  120,
+		Interruptions:  1,
+		Outcome:        history.OutcomeCompleted,
+	}
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/timer"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// =============================================================================
+// Golden Files - Timewarrior / org-mode
+// =============================================================================
+
+func TestWriteTimewarriorはゴールデンファイルと一致する(t *testing.T) {
+	var b strings.Builder
+	if err := WriteTimewarrior(&b, goldenRecords()); err != nil {
+		t.Fatalf("WriteTimewarrior() error = %v", err)
+	}
+	assertGolden(t, "timewarrior.golden", b.String())
+}
+
+func TestWriteOrgはゴールデンファイルと一致する(t *testing.T) {
+	var b strings.Builder
+	if err := WriteOrg(&b, goldenRecords()); err != nil {
+		t.Fatalf("WriteOrg() error = %v", err)
+	}
+	assertGolden(t, "orgmode.golden", b.String())
+}
+
+func TestWriteTimewarriorは作業セッションがなくても配列を書き込む(t *testing.T) {
+	var b strings.Builder
+	if err := WriteTimewarrior(&b, nil); err != nil {
+		t.Fatalf("WriteTimewarrior() error = %v", err)
+	}
+	if got := strings.TrimSpace(b.String()); got != "[]" {
+		t.Errorf("WriteTimewarrior(nil) = %q, want []", got)
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// goldenRecords は2つのタスクにまたがる作業セッションと休憩を返す
+func goldenRecords() []history.Record {
+	day := time.Date(2026, 1, 5, 9, 0, 0, 0, jst)
+	work := func(offset time.Duration, task string, tags ...string) history.Record {
+		start := day.Add(offset)
+		return history.Record{
+			Type:      timer.SessionWork,
+			Task:      task,
+			Tags:      tags,
+			StartedAt: start,
+			EndedAt:   start.Add(25*time.Minute + 30*time.Second),
+			Outcome:   history.OutcomeCompleted,
+		}
+	}
+	return []history.Record{
+		work(0, "Write docs", "docs", "deep work"),
+		{
+			Type:      timer.SessionShortBreak,
+			StartedAt: day.Add(26 * time.Minute),
+			EndedAt:   day.Add(31 * time.Minute),
+			Outcome:   history.OutcomeCompleted,
+		},
+		work(31*time.Minute, "Review PRs"),
+		work(62*time.Minute, "Write docs", "docs"),
+		work(2*time.Hour, ""),
+	}
+}
+
+// assertGolden は出力を testdata のゴールデンファイルと比較する
+// -update を付けて実行するとゴールデンファイルを更新する
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
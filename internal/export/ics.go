@@ -0,0 +1,145 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/plan"
+	"pomodoro-cli/internal/timer"
+)
+
+// icsTimeLayout はUTCのiCalendar日時形式
+const icsTimeLayout = "20060102T150405Z"
+
+// icsLineLimit はiCalendarの1行の最大オクテット数（RFC 5545 3.1）
+const icsLineLimit = 75
+
+// WriteICS はレコードをiCalendar（VEVENT）形式で書き込む
+func WriteICS(w io.Writer, records []history.Record, now time.Time) error {
+	var b strings.Builder
+	writeICSHeader(&b)
+	for _, r := range records {
+		writeICSEvent(&b, r, now)
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeICSEvent は1件のVEVENTを書き込む
+func writeICSEvent(b *strings.Builder, r history.Record, now time.Time) {
+	summary := r.Type.String()
+	if r.Task != "" {
+		summary += ": " + r.Task
+	}
+	description := fmt.Sprintf("Planned: %s\nActual: %s\nInterruptions: %d\nOutcome: %s",
+		time.Duration(r.PlannedSeconds)*time.Second,
+		time.Duration(r.ActualSeconds)*time.Second,
+		r.Interruptions,
+		r.Outcome)
+
+	writeICSLine(b, "BEGIN:VEVENT")
+	writeICSLine(b, "UID:"+eventUID(r))
+	writeICSLine(b, "DTSTAMP:"+now.UTC().Format(icsTimeLayout))
+	writeICSLine(b, "DTSTART:"+r.StartedAt.UTC().Format(icsTimeLayout))
+	writeICSLine(b, "DTEND:"+r.EndedAt.UTC().Format(icsTimeLayout))
+	writeICSLine(b, "SUMMARY:"+escapeICSText(summary))
+	writeICSLine(b, "DESCRIPTION:"+escapeICSText(description))
+	writeICSCategories(b, r.Tags)
+	writeICSLine(b, "TRANSP:TRANSPARENT")
+	writeICSLine(b, "END:VEVENT")
+}
+
+// WritePlanICS は予定表をiCalendar形式で書き込む（カレンダーの時間を確保するため）
+// 作業セッションは予定あり（OPAQUE）、休憩は空き時間（TRANSPARENT）として書き込む
+func WritePlanICS(w io.Writer, p plan.Plan, task string, tags []string, now time.Time) error {
+	var b strings.Builder
+	writeICSHeader(&b)
+	for _, slot := range p.Slots {
+		summary := slot.Type.String()
+		transparency := "TRANSPARENT"
+		if slot.Type == timer.SessionWork {
+			summary = fmt.Sprintf("Focus %d/%d", slot.Number, p.Sessions())
+			if task != "" {
+				summary += ": " + task
+			}
+			transparency = "OPAQUE"
+		}
+		text, _ := slot.Type.MarshalText()
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		writeICSLine(&b, fmt.Sprintf("UID:plan-%s-%s@pomodoro-cli", slot.Start.UTC().Format(icsTimeLayout), text))
+		writeICSLine(&b, "DTSTAMP:"+now.UTC().Format(icsTimeLayout))
+		writeICSLine(&b, "DTSTART:"+slot.Start.UTC().Format(icsTimeLayout))
+		writeICSLine(&b, "DTEND:"+slot.End.UTC().Format(icsTimeLayout))
+		writeICSLine(&b, "SUMMARY:"+escapeICSText(summary))
+		if slot.Type == timer.SessionWork {
+			writeICSCategories(&b, tags)
+		}
+		writeICSLine(&b, "TRANSP:"+transparency)
+		writeICSLine(&b, "END:VEVENT")
+	}
+	writeICSLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeICSHeader はVCALENDARの開始と共通のプロパティを書き込む
+func writeICSHeader(b *strings.Builder) {
+	writeICSLine(b, "BEGIN:VCALENDAR")
+	writeICSLine(b, "VERSION:2.0")
+	writeICSLine(b, "PRODID:-//pomodoro-cli//pomodoro//EN")
+	writeICSLine(b, "CALSCALE:GREGORIAN")
+	writeICSLine(b, "METHOD:PUBLISH")
+}
+
+// writeICSCategories はタグをCATEGORIESとして書き込む（タグがなければ何もしない）
+func writeICSCategories(b *strings.Builder, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	escaped := make([]string, len(tags))
+	for i, tag := range tags {
+		escaped[i] = escapeICSText(tag)
+	}
+	writeICSLine(b, "CATEGORIES:"+strings.Join(escaped, ","))
+}
+
+// eventUID はセッションごとに一意で、再エクスポートしても変わらないUIDを返す
+// 同じ履歴を再度インポートした場合にカレンダー側で重複しないようにする
+func eventUID(r history.Record) string {
+	return fmt.Sprintf("%s-%s@pomodoro-cli", r.StartedAt.UTC().Format(icsTimeLayout), sessionTypeText(r))
+}
+
+// icsEscaper はTEXT値のエスケープ規則（RFC 5545 3.3.11）
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// escapeICSText はTEXT値をエスケープする
+func escapeICSText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// writeICSLine は75オクテットで折り返してCRLFで1行を書き込む
+// マルチバイト文字の途中では折り返さない
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// 継続行は先頭の空白の分だけ短くする
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,95 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/timer"
+)
+
+// orgTimeLayout はorg-modeの非アクティブなタイムスタンプ形式
+const orgTimeLayout = "[2006-01-02 Mon 15:04]"
+
+// noTaskHeading はタスク名のないセッションをまとめる見出し
+const noTaskHeading = "No task"
+
+// orgTask はタスクごとにまとめた作業セッション
+type orgTask struct {
+	name    string
+	tags    []string
+	records []history.Record
+}
+
+// WriteOrg は作業セッションをタスクごとの見出しと CLOCK: 行で書き込む
+// タスクは最初に現れた順、CLOCK: 行は org-mode と同じく新しい順に並べる
+func WriteOrg(w io.Writer, records []history.Record) error {
+	var b strings.Builder
+	for _, task := range groupByTask(records) {
+		b.WriteString("* " + task.name)
+		if len(task.tags) > 0 {
+			b.WriteString(" :" + strings.Join(task.tags, ":") + ":")
+		}
+		b.WriteString("\n  :LOGBOOK:\n")
+		for i := len(task.records) - 1; i >= 0; i-- {
+			b.WriteString("  " + clockLine(task.records[i]) + "\n")
+		}
+		b.WriteString("  :END:\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// groupByTask は作業セッションをタスクごとにまとめる
+func groupByTask(records []history.Record) []*orgTask {
+	var tasks []*orgTask
+	byName := make(map[string]*orgTask)
+	for _, r := range records {
+		if r.Type != timer.SessionWork {
+			continue
+		}
+		name := r.Task
+		if name == "" {
+			name = noTaskHeading
+		}
+		task, ok := byName[name]
+		if !ok {
+			task = &orgTask{name: name}
+			byName[name] = task
+			tasks = append(tasks, task)
+		}
+		task.records = append(task.records, r)
+		for _, tag := range r.Tags {
+			tag = orgTag(tag)
+			if tag != "" && !slices.Contains(task.tags, tag) {
+				task.tags = append(task.tags, tag)
+			}
+		}
+	}
+	return tasks
+}
+
+// clockLine は1セッション分の CLOCK: 行を返す
+// 時刻は記録時のタイムゾーンのまま分単位に切り捨てる
+func clockLine(r history.Record) string {
+	start := r.StartedAt.Truncate(time.Minute)
+	end := r.EndedAt.Truncate(time.Minute)
+	minutes := int(end.Sub(start).Minutes())
+	return fmt.Sprintf("CLOCK: %s--%s => %2d:%02d",
+		start.Format(orgTimeLayout), end.Format(orgTimeLayout), minutes/60, minutes%60)
+}
+
+// orgTag はorg-modeのタグで使えない文字を _ に置き換える
+func orgTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_@#%", r) {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(tag))
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/timer"
+	"pomodoro-cli/internal/timewarrior"
+)
+
+// timewInterval はTimewarriorのインポート形式の1インターバル
+type timewInterval struct {
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// WriteTimewarrior は作業セッションを `timew import` 用のJSONで書き込む
+func WriteTimewarrior(w io.Writer, records []history.Record) error {
+	intervals := []timewInterval{}
+	for _, r := range records {
+		if r.Type != timer.SessionWork {
+			continue
+		}
+		intervals = append(intervals, timewInterval{
+			Start: r.StartedAt.UTC().Format(icsTimeLayout),
+			End:   r.EndedAt.UTC().Format(icsTimeLayout),
+			Tags:  timewarrior.Tags(r.Task, r.Tags),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(intervals)
+}
@@ -0,0 +1,132 @@
+package goal
+
+import (
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/timer"
+)
+
+// dayLayout は日付ごとの集計に使うキーの形式
+const dayLayout = "2006-01-02"
+
+// Progress は目標に対する進捗を表す
+type Progress struct {
+	Today      int // 今日完了した作業セッション数
+	DailyGoal  int // 今日の目標（0 は目標なし）
+	Week       int // 今週（月曜始まり）完了した作業セッション数
+	WeeklyGoal int // 週の目標（0 は目標なし）
+	Streak     int // 目標を達成した連続日数
+	Strict     int // 今日 strict モードで完了した作業セッション数
+	Abandoned  int // 今日 strict モードで途中で終了した作業セッション数
+}
+
+// DailyGoalReached は今日の完了数がちょうど目標に達したかを返す
+// セッション完了直後に達成を祝うために使う
+func (p Progress) DailyGoalReached() bool {
+	return p.DailyGoal > 0 && p.Today == p.DailyGoal
+}
+
+// WeeklyGoalReached は今週の完了数がちょうど目標に達したかを返す
+func (p Progress) WeeklyGoalReached() bool {
+	return p.WeeklyGoal > 0 && p.Week == p.WeeklyGoal
+}
+
+// Compute は履歴から now 時点の進捗を計算する
+func Compute(records []history.Record, cfg *config.Config, now time.Time) Progress {
+	counts := countByDay(records, now.Location())
+	today := startOfDay(now)
+	weekStart := today.AddDate(0, 0, -daysSinceMonday(today))
+
+	p := Progress{
+		Today:      counts[today.Format(dayLayout)],
+		DailyGoal:  cfg.GoalFor(today.Weekday()),
+		WeeklyGoal: cfg.WeeklyGoal,
+		Streak:     streak(counts, cfg, today, earliest(records, now.Location())),
+	}
+	for d := weekStart; !d.After(today); d = d.AddDate(0, 0, 1) {
+		p.Week += counts[d.Format(dayLayout)]
+	}
+	for _, r := range records {
+		if r.Type != timer.SessionWork || !startOfDay(r.EndedAt.In(now.Location())).Equal(today) {
+			continue
+		}
+		switch {
+		case r.Outcome == history.OutcomeAbandoned:
+			p.Abandoned++
+		case r.Strict && r.IsCompletedWork():
+			p.Strict++
+		}
+	}
+	return p
+}
+
+// streak は今日から遡って目標を達成した連続日数を数える
+// 今日がまだ未達成の場合は連続を途切れさせず、昨日から数える
+// 曜日別の目標が 0 の日は休みとして数えずに飛ばす
+func streak(counts map[string]int, cfg *config.Config, today, first time.Time) int {
+	n := 0
+	for d := today; !d.Before(first); d = d.AddDate(0, 0, -1) {
+		if isRestDay(cfg, d.Weekday()) {
+			continue
+		}
+		threshold := max(cfg.GoalFor(d.Weekday()), 1)
+		switch {
+		case counts[d.Format(dayLayout)] >= threshold:
+			n++
+		case d.Equal(today):
+			continue
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+// isRestDay は曜日別の目標が明示的に 0 に設定されているかを返す
+func isRestDay(cfg *config.Config, day time.Weekday) bool {
+	goal, ok := cfg.WeekdayGoals[strings.ToLower(day.String())]
+	return ok && goal == 0
+}
+
+// countByDay は日付ごとの完了した作業セッション数を数える
+func countByDay(records []history.Record, loc *time.Location) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range records {
+		if r.IsCompletedWork() {
+			counts[r.EndedAt.In(loc).Format(dayLayout)]++
+		}
+	}
+	return counts
+}
+
+// earliest は最も古い完了した作業セッションの日付を返す
+func earliest(records []history.Record, loc *time.Location) time.Time {
+	var first time.Time
+	for _, r := range records {
+		if !r.IsCompletedWork() {
+			continue
+		}
+		if day := startOfDay(r.EndedAt.In(loc)); first.IsZero() || day.Before(first) {
+			first = day
+		}
+	}
+	if first.IsZero() {
+		// 記録がなければ連続日数の計算を行わない
+		return time.Date(9999, 1, 1, 0, 0, 0, 0, loc)
+	}
+	return first
+}
+
+// startOfDay は指定時刻の日の0時を返す
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysSinceMonday は月曜日からの経過日数を返す
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
@@ -0,0 +1,153 @@
+package goal
+
+import (
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/timer"
+)
+
+// 2026-01-07 は水曜日
+var now = time.Date(2026, 1, 7, 15, 0, 0, 0, time.UTC)
+
+// =============================================================================
+// Compute - 進捗の計算
+// =============================================================================
+
+func TestComputeは今日と今週の完了数を数える(t *testing.T) {
+	cfg := &config.Config{DailyGoal: 8, WeeklyGoal: 40}
+	records := []history.Record{
+		work(now, 0),
+		work(now, 0),
+		work(now, -1),
+		work(now, -3), // 先週の日曜日
+		{Type: timer.SessionWork, EndedAt: now, Outcome: history.OutcomeSkipped},
+		{Type: timer.SessionShortBreak, EndedAt: now, Outcome: history.OutcomeCompleted},
+	}
+
+	p := Compute(records, cfg, now)
+
+	if p.Today != 2 || p.DailyGoal != 8 {
+		t.Errorf("today = %d/%d, want 2/8", p.Today, p.DailyGoal)
+	}
+	if p.Week != 3 || p.WeeklyGoal != 40 {
+		t.Errorf("week = %d/%d, want 3/40", p.Week, p.WeeklyGoal)
+	}
+}
+
+func TestComputeはstrictモードの完了と放棄を今日の分だけ数える(t *testing.T) {
+	strict := work(now, 0)
+	strict.Strict = true
+	yesterday := work(now, -1)
+	yesterday.Strict = true
+	records := []history.Record{
+		strict,
+		yesterday,
+		work(now, 0),
+		{Type: timer.SessionWork, EndedAt: now, Outcome: history.OutcomeAbandoned, Strict: true},
+	}
+
+	p := Compute(records, &config.Config{}, now)
+
+	if p.Strict != 1 || p.Abandoned != 1 {
+		t.Errorf("strict = %d, abandoned = %d, want 1, 1", p.Strict, p.Abandoned)
+	}
+	if p.Today != 2 {
+		t.Errorf("Today = %d, want 2 (abandoned sessions do not count)", p.Today)
+	}
+}
+
+func TestComputeは曜日別の目標を使う(t *testing.T) {
+	cfg := &config.Config{DailyGoal: 8, WeekdayGoals: map[string]int{"wednesday": 4}}
+
+	p := Compute(nil, cfg, now)
+
+	if p.DailyGoal != 4 {
+		t.Errorf("DailyGoal = %d, want 4", p.DailyGoal)
+	}
+}
+
+func TestDailyGoalReachedは目標に達した時だけtrueを返す(t *testing.T) {
+	tests := []struct {
+		progress Progress
+		expected bool
+	}{
+		{Progress{Today: 7, DailyGoal: 8}, false},
+		{Progress{Today: 8, DailyGoal: 8}, true},
+		{Progress{Today: 9, DailyGoal: 8}, false},
+		{Progress{Today: 0, DailyGoal: 0}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.progress.DailyGoalReached(); got != tt.expected {
+			t.Errorf("%+v.DailyGoalReached() = %v, want %v", tt.progress, got, tt.expected)
+		}
+	}
+}
+
+// =============================================================================
+// Streak - 連続日数
+// =============================================================================
+
+func TestStreakは目標を達成した連続日数を数える(t *testing.T) {
+	cfg := &config.Config{DailyGoal: 2}
+	records := []history.Record{
+		work(now, -4), work(now, -4),
+		work(now, -3), // 未達成で途切れる
+		work(now, -2), work(now, -2),
+		work(now, -1), work(now, -1),
+		work(now, 0), work(now, 0),
+	}
+
+	if got := Compute(records, cfg, now).Streak; got != 3 {
+		t.Errorf("Streak = %d, want 3", got)
+	}
+}
+
+func TestStreakは今日が未達成でも昨日までの連続を維持する(t *testing.T) {
+	cfg := &config.Config{DailyGoal: 1}
+	records := []history.Record{work(now, -2), work(now, -1)}
+
+	if got := Compute(records, cfg, now).Streak; got != 2 {
+		t.Errorf("Streak = %d, want 2", got)
+	}
+}
+
+func TestStreakは目標なしの場合1回以上で達成とみなす(t *testing.T) {
+	cfg := &config.Config{}
+	records := []history.Record{work(now, -1), work(now, 0)}
+
+	if got := Compute(records, cfg, now).Streak; got != 2 {
+		t.Errorf("Streak = %d, want 2", got)
+	}
+}
+
+func TestStreakは目標0の曜日を休みとして飛ばす(t *testing.T) {
+	cfg := &config.Config{DailyGoal: 1, WeekdayGoals: map[string]int{"saturday": 0, "sunday": 0}}
+	// 金曜日と月曜日から水曜日まで達成
+	records := []history.Record{work(now, -5), work(now, -2), work(now, -1), work(now, 0)}
+
+	if got := Compute(records, cfg, now).Streak; got != 4 {
+		t.Errorf("Streak = %d, want 4", got)
+	}
+}
+
+func TestStreakは履歴がなければ0を返す(t *testing.T) {
+	if got := Compute(nil, &config.Config{DailyGoal: 1}, now).Streak; got != 0 {
+		t.Errorf("Streak = %d, want 0", got)
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+// work は base から days 日ずれた日に完了した作業セッションを返す
+func work(base time.Time, days int) history.Record {
+	return history.Record{
+		Type:    timer.SessionWork,
+		EndedAt: base.AddDate(0, 0, days),
+		Outcome: history.OutcomeCompleted,
+	}
+}
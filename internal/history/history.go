@@ -0,0 +1,195 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+)
+
+// Outcome はセッションの結果を表す
+type Outcome string
+
+const (
+	OutcomeCompleted Outcome = "completed"
+	OutcomeSkipped   Outcome = "skipped"
+	// OutcomeAbandoned は strict モードの作業セッションを途中で終了したことを表す
+	OutcomeAbandoned Outcome = "abandoned"
+	// OutcomeInterrupted は画面のロックで作業セッションを中断したことを表す
+	OutcomeInterrupted Outcome = "interrupted"
+)
+
+// Record は1セッション分の履歴を表す
+type Record struct {
+	Type           timer.SessionType `json:"type"`
+	Task           string            `json:"task,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	StartedAt      time.Time         `json:"started_at"`
+	EndedAt        time.Time         `json:"ended_at"`
+	PlannedSeconds int               `json:"planned_seconds"`
+	ActualSeconds  int               `json:"actual_seconds"`
+	PausedSeconds  int               `json:"paused_seconds"`
+	Interruptions  int               `json:"interruptions"`
+	Outcome        Outcome           `json:"outcome"`
+	// Strict は strict モードで実行したセッションかどうか
+	Strict bool `json:"strict,omitempty"`
+	// IdleKeptSeconds は離席中の時間のうち作業時間として数えた秒数
+	IdleKeptSeconds int `json:"idle_kept_seconds,omitempty"`
+	// IdleDiscardedSeconds は離席中の時間のうち作業時間から除いた秒数
+	IdleDiscardedSeconds int `json:"idle_discarded_seconds,omitempty"`
+}
+
+// NewRecord はセッションから履歴レコードを作成する
+func NewRecord(session timer.Session, outcome Outcome, endedAt time.Time) Record {
+	return Record{
+		Type:                 session.Type,
+		Task:                 session.Task,
+		Tags:                 session.Tags,
+		StartedAt:            session.StartedAt,
+		EndedAt:              endedAt,
+		PlannedSeconds:       int(session.Duration.Seconds()),
+		ActualSeconds:        int(session.Elapsed().Seconds()),
+		PausedSeconds:        int(session.PausedTotal.Seconds()),
+		Interruptions:        session.Interruptions,
+		Outcome:              outcome,
+		IdleKeptSeconds:      int(session.IdleKept.Seconds()),
+		IdleDiscardedSeconds: int(session.IdleDiscarded.Seconds()),
+	}
+}
+
+// IsCompletedWork は完了した作業セッションかどうかを返す
+func (r Record) IsCompletedWork() bool {
+	return r.Type == timer.SessionWork && r.Outcome == OutcomeCompleted
+}
+
+// Path は履歴ファイルのパスを返す
+func Path() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// Store は履歴ファイル（JSON Lines）とメモリ上の記録を管理する
+type Store struct {
+	path    string
+	mu      sync.Mutex
+	records []Record
+	// warnings は読み込めずに飛ばした行の警告
+	warnings []string
+	// unterminated はファイルが改行で終わっていないこと（途中で書き込みが止まった行）を表す
+	unterminated bool
+}
+
+// Open は履歴ファイルを読み込んだStoreを返す
+// ファイルが存在しない場合は空のStoreを返す
+// 読み込めない行（書き込み中に止まって途切れた行など）は飛ばして Warnings に記録する
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("%s:%d: skipped a malformed record: %v", path, line, err))
+			continue
+		}
+		s.records = append(s.records, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	s.unterminated = len(data) > 0 && data[len(data)-1] != '\n'
+	return s, nil
+}
+
+// Warnings は読み込み時に飛ばした行の警告を返す
+func (s *Store) Warnings() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.warnings)
+}
+
+// Append はレコードをファイルに追記する
+func (s *Store) Append(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if s.unterminated {
+		// 途切れた行に続けて書くと、この記録まで読めなくなる
+		data = append([]byte{'\n'}, data...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	s.unterminated = false
+	s.records = append(s.records, r)
+	return nil
+}
+
+// Records は記録済みのレコードのコピーを返す（古い順）
+func (s *Store) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]Record, len(s.records))
+	copy(records, s.records)
+	return records
+}
+
+// Tasks は記録されたタスク名を重複なしで返す（最近使った順）
+func (s *Store) Tasks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []string
+	seen := make(map[string]bool)
+	for i := len(s.records) - 1; i >= 0; i-- {
+		task := s.records[i].Task
+		if task == "" || seen[task] {
+			continue
+		}
+		seen[task] = true
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
@@ -0,0 +1,157 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// NewRecord - レコードの作成
+// =============================================================================
+
+func TestNewRecordはセッションの計測値を秒で記録する(t *testing.T) {
+	started := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	session := timer.Session{
+		Type:          timer.SessionWork,
+		Duration:      25 * time.Minute,
+		Remaining:     5 * time.Minute,
+		StartedAt:     started,
+		PausedTotal:   2 * time.Minute,
+		Interruptions: 1,
+		Task:          "docs",
+	}
+
+	r := NewRecord(session, OutcomeSkipped, started.Add(22*time.Minute))
+
+	if r.PlannedSeconds != 1500 {
+		t.Errorf("PlannedSeconds = %d, want 1500", r.PlannedSeconds)
+	}
+	if r.ActualSeconds != 1200 {
+		t.Errorf("ActualSeconds = %d, want 1200", r.ActualSeconds)
+	}
+	if r.PausedSeconds != 120 {
+		t.Errorf("PausedSeconds = %d, want 120", r.PausedSeconds)
+	}
+	if r.Task != "docs" || r.Interruptions != 1 || r.Outcome != OutcomeSkipped {
+		t.Errorf("record = %+v", r)
+	}
+	if r.IsCompletedWork() {
+		t.Error("skipped session should not be completed work")
+	}
+}
+
+// =============================================================================
+// Store - 履歴ファイルの読み書き
+// =============================================================================
+
+func TestOpenはファイルがない場合空のStoreを返す(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if len(store.Records()) != 0 {
+		t.Errorf("Records() = %d, want 0", len(store.Records()))
+	}
+}
+
+func TestAppendしたレコードを再度Openで読み込める(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pomodoro", "history.jsonl")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	ended := time.Date(2026, 1, 5, 9, 25, 0, 0, time.UTC)
+	for _, outcome := range []Outcome{OutcomeCompleted, OutcomeSkipped} {
+		r := Record{Type: timer.SessionWork, EndedAt: ended, Outcome: outcome}
+		if err := store.Append(r); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	records := reopened.Records()
+	if len(records) != 2 {
+		t.Fatalf("Records() = %d, want 2", len(records))
+	}
+	if !records[0].IsCompletedWork() || records[1].Outcome != OutcomeSkipped {
+		t.Errorf("records = %+v", records)
+	}
+	if !records[0].EndedAt.Equal(ended) {
+		t.Errorf("EndedAt = %v, want %v", records[0].EndedAt, ended)
+	}
+}
+
+func TestOpenは壊れた行を飛ばして行番号を警告に含める(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	data := `{"type":"work","outcome":"completed"}` + "\n" + "not json\n" +
+		`{"type":"short_break","outcome":"completed"}` + "\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if got := len(store.Records()); got != 2 {
+		t.Errorf("Records() = %d, want 2", got)
+	}
+	warnings := store.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "history.jsonl:2") {
+		t.Errorf("Warnings() = %q, want one warning with the line number", warnings)
+	}
+}
+
+func TestAppendは途切れた最後の行の次の行に追記する(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	data := `{"type":"work","outcome":"completed"}` + "\n" + `{"type":"work","outc`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := store.Append(Record{Type: timer.SessionWork, Outcome: OutcomeSkipped}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	records := reopened.Records()
+	if len(records) != 2 || records[1].Outcome != OutcomeSkipped {
+		t.Errorf("records = %+v, want the appended record after the truncated line", records)
+	}
+	if len(reopened.Warnings()) != 1 {
+		t.Errorf("Warnings() = %q, want one warning for the truncated line", reopened.Warnings())
+	}
+}
+
+func TestTasksはタスク名を重複なしで最近使った順に返す(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for _, task := range []string{"docs", "", "review", "docs"} {
+		if err := store.Append(Record{Type: timer.SessionWork, Task: task}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	got := store.Tasks()
+	if strings.Join(got, ",") != "docs,review" {
+		t.Errorf("Tasks() = %v, want [docs review]", got)
+	}
+}
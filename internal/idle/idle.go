@@ -0,0 +1,243 @@
+// Package idle はユーザーが操作していない時間（アイドル時間）を調べ、
+// しきい値を超えて離席したことと戻ってきたことを検出する
+package idle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider はユーザーが最後に操作してからの時間を返す
+type Provider interface {
+	Name() string
+	IdleTime(now time.Time) (time.Duration, error)
+}
+
+// CommandOutput は外部コマンドを実行して標準出力を返す（テストで差し替える）
+type CommandOutput func(name string, args ...string) ([]byte, error)
+
+// execOutput はコマンドを実行して標準出力を返す
+func execOutput(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// Providers は設定の idle_detection に指定できるアイドル時間の取得方法
+var Providers = []string{"auto", "x11", "logind", "terminal"}
+
+// New は名前に対応する Provider を返す
+// "auto" は X11 のディスプレイがあれば xprintidle、ログインセッションがあれば logind を使い、
+// どちらもなければ nil を返す（離席を検出しない）
+// 作業中はタイマーのターミナルに入力しないのが普通なので、"terminal" は指定したときだけ使う
+func New(name string, terminal *Terminal) (Provider, error) {
+	switch name {
+	case "x11":
+		return NewX11(), nil
+	case "logind":
+		return NewLogind(), nil
+	case "terminal":
+		return terminal, nil
+	case "auto":
+		if _, err := exec.LookPath("xprintidle"); err == nil && os.Getenv("DISPLAY") != "" {
+			return NewX11(), nil
+		}
+		if _, err := exec.LookPath("loginctl"); err == nil && os.Getenv("XDG_SESSION_ID") != "" {
+			return NewLogind(), nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown idle detection %q (use one of: %s)", name, strings.Join(Providers, ", "))
+	}
+}
+
+// ----------------------------------------------------------------------------
+// X11
+// ----------------------------------------------------------------------------
+
+// X11 は XScreenSaver 拡張のアイドル時間を xprintidle で取得する
+type X11 struct {
+	output CommandOutput
+}
+
+// NewX11 は xprintidle を実行する X11 を作成する
+func NewX11() *X11 {
+	return &X11{output: execOutput}
+}
+
+// NewX11WithOutput はコマンドの実行方法を指定して X11 を作成する
+func NewX11WithOutput(output CommandOutput) *X11 {
+	return &X11{output: output}
+}
+
+// Name は取得方法の名前を返す
+func (x *X11) Name() string {
+	return "x11"
+}
+
+// IdleTime は xprintidle が出力するミリ秒のアイドル時間を返す
+func (x *X11) IdleTime(time.Time) (time.Duration, error) {
+	out, err := x.output("xprintidle")
+	if err != nil {
+		return 0, fmt.Errorf("failed to run xprintidle: %w", err)
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected xprintidle output %q", strings.TrimSpace(string(out)))
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// ----------------------------------------------------------------------------
+// logind
+// ----------------------------------------------------------------------------
+
+// Logind は systemd-logind のセッションの IdleHint を loginctl で取得する
+// IdleHint はデスクトップ環境やスクリーンセーバーが設定する
+type Logind struct {
+	output  CommandOutput
+	session string
+}
+
+// NewLogind は XDG_SESSION_ID のセッション（なければ自分のセッション）を調べる Logind を作成する
+func NewLogind() *Logind {
+	return NewLogindWithOutput(execOutput, os.Getenv("XDG_SESSION_ID"))
+}
+
+// NewLogindWithOutput はコマンドの実行方法とセッションを指定して Logind を作成する
+func NewLogindWithOutput(output CommandOutput, session string) *Logind {
+	if session == "" {
+		session = "self"
+	}
+	return &Logind{output: output, session: session}
+}
+
+// Name は取得方法の名前を返す
+func (l *Logind) Name() string {
+	return "logind"
+}
+
+// IdleTime は IdleHint が yes なら IdleSinceHint からの時間を返す（そうでなければ 0）
+func (l *Logind) IdleTime(now time.Time) (time.Duration, error) {
+	out, err := l.output("loginctl", "show-session", l.session, "-p", "IdleHint", "-p", "IdleSinceHint")
+	if err != nil {
+		return 0, fmt.Errorf("failed to run loginctl: %w", err)
+	}
+	props := make(map[string]string)
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			props[key] = value
+		}
+	}
+	if props["IdleHint"] != "yes" {
+		return 0, nil
+	}
+	// IdleSinceHint はエポックからのマイクロ秒
+	usec, err := strconv.ParseInt(props["IdleSinceHint"], 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, errors.New("loginctl did not report IdleSinceHint")
+	}
+	return max(now.Sub(time.UnixMicro(usec)), 0), nil
+}
+
+// ----------------------------------------------------------------------------
+// ターミナル
+// ----------------------------------------------------------------------------
+
+// Terminal はターミナルへの最後のキー入力からの時間をアイドル時間とする
+type Terminal struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewTerminal は now に入力があったものとして Terminal を作成する
+func NewTerminal(now time.Time) *Terminal {
+	return &Terminal{last: now}
+}
+
+// Touch はキー入力があったことを記録する
+func (t *Terminal) Touch(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = now
+}
+
+// Name は取得方法の名前を返す
+func (t *Terminal) Name() string {
+	return "terminal"
+}
+
+// IdleTime は最後のキー入力からの時間を返す
+func (t *Terminal) IdleTime(now time.Time) (time.Duration, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return max(now.Sub(t.last), 0), nil
+}
+
+// ----------------------------------------------------------------------------
+// 離席の検出
+// ----------------------------------------------------------------------------
+
+// Change はアイドル状態の変化
+type Change int
+
+const (
+	NoChange Change = iota
+	// Away はアイドル時間がしきい値を超えた（離席した）
+	Away
+	// Back は離席後に操作が再開された
+	Back
+)
+
+// pollInterval はアイドル時間を調べる間隔（外部コマンドを頻繁に実行しないため）
+const pollInterval = 2 * time.Second
+
+// Detector は一定間隔でアイドル時間を調べ、しきい値をまたいだ変化を返す
+type Detector struct {
+	provider  Provider
+	threshold time.Duration
+	lastPoll  time.Time
+	away      bool
+	since     time.Time
+}
+
+// NewDetector は threshold 以上操作がなければ離席とみなす Detector を作成する
+func NewDetector(provider Provider, threshold time.Duration) *Detector {
+	return &Detector{provider: provider, threshold: threshold}
+}
+
+// Provider はアイドル時間の取得方法を返す
+func (d *Detector) Provider() Provider {
+	return d.provider
+}
+
+// Poll は前回から pollInterval 以上経っていればアイドル時間を調べ、状態の変化を返す
+func (d *Detector) Poll(now time.Time) (Change, error) {
+	if !d.lastPoll.IsZero() && now.Sub(d.lastPoll) < pollInterval {
+		return NoChange, nil
+	}
+	d.lastPoll = now
+	idle, err := d.provider.IdleTime(now)
+	if err != nil {
+		return NoChange, err
+	}
+	switch {
+	case !d.away && idle >= d.threshold:
+		d.away = true
+		d.since = now.Add(-idle)
+		return Away, nil
+	case d.away && idle < d.threshold:
+		d.away = false
+		return Back, nil
+	}
+	return NoChange, nil
+}
+
+// Since は最後に離席した（操作がなくなった）時刻を返す
+func (d *Detector) Since() time.Time {
+	return d.since
+}
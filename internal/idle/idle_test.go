@@ -0,0 +1,151 @@
+package idle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Provider - アイドル時間の取得
+// =============================================================================
+
+func TestX11はxprintidleのミリ秒を読む(t *testing.T) {
+	x := NewX11WithOutput(func(name string, args ...string) ([]byte, error) {
+		if name != "xprintidle" {
+			t.Errorf("command = %s, want xprintidle", name)
+		}
+		return []byte("90500\n"), nil
+	})
+
+	idle, err := x.IdleTime(time.Now())
+	if err != nil {
+		t.Fatalf("IdleTime() error = %v", err)
+	}
+	if idle != 90500*time.Millisecond {
+		t.Errorf("IdleTime() = %v, want 1m30.5s", idle)
+	}
+}
+
+func TestX11は不正な出力をエラーにする(t *testing.T) {
+	x := NewX11WithOutput(func(string, ...string) ([]byte, error) {
+		return []byte("couldn't open display\n"), nil
+	})
+	if _, err := x.IdleTime(time.Now()); err == nil {
+		t.Error("IdleTime() error = nil, want error")
+	}
+
+	x = NewX11WithOutput(func(string, ...string) ([]byte, error) {
+		return nil, errors.New("not found")
+	})
+	if _, err := x.IdleTime(time.Now()); err == nil || !strings.Contains(err.Error(), "xprintidle") {
+		t.Errorf("IdleTime() error = %v, want xprintidle error", err)
+	}
+}
+
+func TestLogindはIdleHintとIdleSinceHintを読む(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	since := now.Add(-7 * time.Minute)
+
+	tests := []struct {
+		name   string
+		output string
+		want   time.Duration
+	}{
+		{"アイドル", fmt.Sprintf("IdleHint=yes\nIdleSinceHint=%d\n", since.UnixMicro()), 7 * time.Minute},
+		{"操作中", fmt.Sprintf("IdleHint=no\nIdleSinceHint=%d\n", since.UnixMicro()), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []string
+			l := NewLogindWithOutput(func(name string, a ...string) ([]byte, error) {
+				args = a
+				return []byte(tt.output), nil
+			}, "")
+
+			idle, err := l.IdleTime(now)
+			if err != nil {
+				t.Fatalf("IdleTime() error = %v", err)
+			}
+			if idle != tt.want {
+				t.Errorf("IdleTime() = %v, want %v", idle, tt.want)
+			}
+			if len(args) < 2 || args[1] != "self" {
+				t.Errorf("args = %q, want session self", args)
+			}
+		})
+	}
+}
+
+func TestTerminalは最後の入力からの時間を返す(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	term := NewTerminal(start)
+
+	if idle, _ := term.IdleTime(start.Add(3 * time.Minute)); idle != 3*time.Minute {
+		t.Errorf("IdleTime() = %v, want 3m", idle)
+	}
+	term.Touch(start.Add(3 * time.Minute))
+	if idle, _ := term.IdleTime(start.Add(4 * time.Minute)); idle != time.Minute {
+		t.Errorf("IdleTime() after Touch = %v, want 1m", idle)
+	}
+}
+
+func TestNewは不明な名前をエラーにする(t *testing.T) {
+	term := NewTerminal(time.Now())
+	if p, err := New("terminal", term); err != nil || p != term {
+		t.Errorf("New(terminal) = %v, %v", p, err)
+	}
+	if _, err := New("screensaver", term); err == nil {
+		t.Error("New(screensaver) error = nil, want error")
+	}
+}
+
+func TestNewのautoはシステムのアイドル時間がなければ検出しない(t *testing.T) {
+	t.Setenv("DISPLAY", "")
+	t.Setenv("XDG_SESSION_ID", "")
+	if p, err := New("auto", NewTerminal(time.Now())); err != nil || p != nil {
+		t.Errorf("New(auto) = %v, %v, want nil, nil", p, err)
+	}
+}
+
+// =============================================================================
+// Detector - 離席の検出
+// =============================================================================
+
+func TestDetectorはしきい値をまたいだときだけ変化を返す(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	term := NewTerminal(start)
+	d := NewDetector(term, 5*time.Minute)
+
+	steps := []struct {
+		at    time.Duration
+		touch bool
+		want  Change
+	}{
+		{4 * time.Minute, false, NoChange},
+		{5 * time.Minute, false, Away},
+		{6 * time.Minute, false, NoChange},
+		// pollInterval 以内は調べない
+		{6*time.Minute + time.Second, true, NoChange},
+		{6*time.Minute + 3*time.Second, false, Back},
+		{7 * time.Minute, false, NoChange},
+	}
+	for _, step := range steps {
+		now := start.Add(step.at)
+		if step.touch {
+			term.Touch(now)
+		}
+		got, err := d.Poll(now)
+		if err != nil {
+			t.Fatalf("Poll(%v) error = %v", step.at, err)
+		}
+		if got != step.want {
+			t.Errorf("Poll(%v) = %v, want %v", step.at, got, step.want)
+		}
+	}
+	if !d.Since().Equal(start) {
+		t.Errorf("Since() = %v, want %v", d.Since(), start)
+	}
+}
@@ -0,0 +1,159 @@
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Action はキーに割り当てる操作の名前
+type Action string
+
+const (
+	// ActionNone はキーの割り当てを外す（デフォルトの割り当てを無効にする）
+	ActionNone  Action = "none"
+	ActionPause Action = "pause"
+	ActionSkip  Action = "skip"
+	ActionReset Action = "reset"
+	ActionQuit  Action = "quit"
+	ActionGoal  Action = "goal"
+	ActionHelp  Action = "help"
+	// ActionCommand はコマンドパレットを開く
+	ActionCommand Action = "command"
+)
+
+// ActionInfo はアクションとその説明
+type ActionInfo struct {
+	Action Action
+	// Label はショートカットの一覧に表示する短い名前
+	Label       string
+	Description string
+}
+
+// Actions はキーに割り当てられるアクションの一覧（ヘルプに表示する順）
+var Actions = []ActionInfo{
+	{ActionPause, "Pause/Resume", "Pause or resume the timer"},
+	{ActionSkip, "Skip", "Skip to the next session"},
+	{ActionReset, "Reset", "Restart the current session"},
+	{ActionQuit, "Quit", "Quit pomodoro"},
+	{ActionGoal, "Goal", "Show goal progress"},
+	{ActionHelp, "Help", "Show this help"},
+	{ActionCommand, "Command", "Open the command palette"},
+}
+
+// ParseAction はアクションの名前を検証する
+func ParseAction(s string) (Action, error) {
+	a := Action(s)
+	if a == ActionNone {
+		return a, nil
+	}
+	for _, info := range Actions {
+		if info.Action == a {
+			return a, nil
+		}
+	}
+	names := []string{string(ActionNone)}
+	for _, info := range Actions {
+		names = append(names, string(info.Action))
+	}
+	return "", fmt.Errorf("unknown action %q (use one of: %s)", s, strings.Join(names, ", "))
+}
+
+// ErrNoQuitKey は終了するキーがひとつも残らない割り当てのエラー
+// raw モードでは Ctrl+C でもシグナルが届かないので、終了できなくなる
+var ErrNoQuitKey = errors.New(`no key is left to quit (bind at least one key to "quit")`)
+
+// Bindings はキーとアクションの対応
+type Bindings map[Key]Action
+
+// DefaultBindings はデフォルトのキーの割り当てを返す
+func DefaultBindings() Bindings {
+	return Bindings{
+		"space":  ActionPause,
+		"s":      ActionSkip,
+		"S":      ActionSkip,
+		"r":      ActionReset,
+		"R":      ActionReset,
+		"q":      ActionQuit,
+		"Q":      ActionQuit,
+		"ctrl+c": ActionQuit,
+		"g":      ActionGoal,
+		"?":      ActionHelp,
+		":":      ActionCommand,
+	}
+}
+
+// NewBindings はデフォルトの割り当てに設定の keybindings（キー → アクション）を重ねる
+// アクションに "none" を指定したキーは割り当てを外す
+// 終了するキーがひとつも残らなければ ErrNoQuitKey を返す
+func NewBindings(overrides map[string]string) (Bindings, error) {
+	b := DefaultBindings()
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		k, err := Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		a, err := ParseAction(overrides[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if a == ActionNone {
+			delete(b, k)
+			continue
+		}
+		b[k] = a
+	}
+	if len(b.KeysFor(ActionQuit)) == 0 {
+		return nil, ErrNoQuitKey
+	}
+	return b, nil
+}
+
+// Action はキーに割り当てられたアクションを返す（なければ ActionNone）
+func (b Bindings) Action(k Key) Action {
+	if a, ok := b[k]; ok {
+		return a
+	}
+	return ActionNone
+}
+
+// KeysFor はアクションに割り当てられたキーを返す
+// 特殊キー、小文字、その他の順に並べる（"Space"、"q"、"Q"、"Ctrl+C"）
+func (b Bindings) KeysFor(a Action) []Key {
+	var keys []Key
+	for k, action := range b {
+		if action == a {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(x, y Key) int {
+		if rx, ry := keyRank(x), keyRank(y); rx != ry {
+			return rx - ry
+		}
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+		return 0
+	})
+	return keys
+}
+
+// keyRank はヘルプに表示する順番のための分類を返す
+func keyRank(k Key) int {
+	modifiers, base := k.split()
+	switch {
+	case len(modifiers) > 0:
+		return 3
+	case len(base) > 1:
+		return 0
+	case base >= "a" && base <= "z":
+		return 1
+	default:
+		return 2
+	}
+}
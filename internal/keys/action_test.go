@@ -0,0 +1,55 @@
+package keys
+
+import (
+	"slices"
+	"testing"
+)
+
+// =============================================================================
+// Bindings - キーとアクションの対応
+// =============================================================================
+
+func TestDefaultBindingsはデフォルトのキーを割り当てる(t *testing.T) {
+	b := DefaultBindings()
+	tests := map[Key]Action{
+		"space": ActionPause, "s": ActionSkip, "R": ActionReset, "ctrl+c": ActionQuit, "x": ActionNone,
+	}
+	for k, want := range tests {
+		if got := b.Action(k); got != want {
+			t.Errorf("Action(%q) = %q, want %q", k, got, want)
+		}
+	}
+}
+
+func TestNewBindingsは設定の割り当てをデフォルトに重ねる(t *testing.T) {
+	b, err := NewBindings(map[string]string{"Ctrl+S": "skip", "q": "none", "p": "pause"})
+	if err != nil {
+		t.Fatalf("NewBindings() error = %v", err)
+	}
+	if got := b.Action("ctrl+s"); got != ActionSkip {
+		t.Errorf(`Action("ctrl+s") = %q, want skip`, got)
+	}
+	if got := b.Action("q"); got != ActionNone {
+		t.Errorf(`Action("q") = %q, want none`, got)
+	}
+	if got := b.KeysFor(ActionPause); !slices.Equal(got, []Key{"space", "p"}) {
+		t.Errorf("KeysFor(pause) = %q, want [space p]", got)
+	}
+	if got := b.KeysFor(ActionQuit); !slices.Equal(got, []Key{"Q", "ctrl+c"}) {
+		t.Errorf("KeysFor(quit) = %q, want [Q ctrl+c]", got)
+	}
+}
+
+func TestNewBindingsは不正なキーとアクションをエラーにする(t *testing.T) {
+	for _, overrides := range []map[string]string{
+		{"hyper+x": "skip"},
+		{"x": "explode"},
+		// 終了するキーがひとつも残らない
+		{"ctrl+c": "none", "q": "none", "Q": "none"},
+		{"ctrl+c": "pause", "q": "skip", "Q": "none"},
+	} {
+		if _, err := NewBindings(overrides); err == nil {
+			t.Errorf("NewBindings(%v) error = nil, want error", overrides)
+		}
+	}
+}
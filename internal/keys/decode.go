@@ -0,0 +1,171 @@
+package keys
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const esc = 0x1b
+
+// maxSequence はエスケープシーケンスとみなす最大の長さ（これを超えたら読み捨てる）
+const maxSequence = 32
+
+// Decode はバイト列の先頭から1つのキーを読み取り、キーと使ったバイト数を返す
+// エスケープシーケンスやマルチバイト文字の途中で終わっている場合は n = 0 を返す
+// 単独の ESC も続きが来る可能性があるため n = 0 になる（判断は Decoder が行う）
+func Decode(b []byte) (k Key, n int) {
+	if len(b) == 0 {
+		return Unknown, 0
+	}
+	switch c := b[0]; {
+	case c == esc:
+		return decodeEscape(b)
+	case c == ' ':
+		return "space", 1
+	case c == '\r' || c == '\n':
+		return "enter", 1
+	case c == '\t':
+		return "tab", 1
+	case c == 0x7f || c == 0x08:
+		return "backspace", 1
+	case c == 0:
+		return "ctrl+space", 1
+	case c >= 1 && c <= 26:
+		return Key("ctrl+" + string(rune('a'+c-1))), 1
+	case c < 0x20:
+		return Unknown, 1
+	}
+
+	if !utf8.FullRune(b) {
+		return Unknown, 0
+	}
+	r, size := utf8.DecodeRune(b)
+	if r == utf8.RuneError && size <= 1 {
+		return Unknown, 1
+	}
+	return Key(string(r)), size
+}
+
+// decodeEscape は ESC で始まるキーを読み取る
+func decodeEscape(b []byte) (Key, int) {
+	if len(b) < 2 {
+		return Unknown, 0
+	}
+	switch b[1] {
+	case '[':
+		return decodeCSI(b)
+	case 'O':
+		if len(b) < 3 {
+			return Unknown, 0
+		}
+		if name, ok := finalKeys[b[2]]; ok {
+			return Key(name), 3
+		}
+		return Unknown, 3
+	case esc:
+		return "esc", 1
+	}
+
+	// ESC の後に続くキーは Alt を押しながらの入力
+	k, n := Decode(b[1:])
+	if n == 0 {
+		return Unknown, 0
+	}
+	if k == Unknown {
+		return Unknown, n + 1
+	}
+	modifiers, base := k.split()
+	return withModifiers(append(modifiers, "alt"), base), n + 1
+}
+
+// decodeCSI は "ESC [ パラメータ 終端文字" の形のシーケンスを読み取る
+func decodeCSI(b []byte) (Key, int) {
+	end := -1
+	for i := 2; i < len(b) && i < maxSequence; i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		if len(b) >= maxSequence {
+			return Unknown, len(b)
+		}
+		return Unknown, 0
+	}
+
+	params := strings.Split(string(b[2:end]), ";")
+	var name string
+	switch final := b[end]; final {
+	case '~':
+		name = tildeKeys[params[0]]
+	case 'Z':
+		return "shift+tab", end + 1
+	default:
+		name = finalKeys[final]
+	}
+	if name == "" {
+		return Unknown, end + 1
+	}
+
+	// 2つ目のパラメータは修飾キー（1 + Shift(1) + Alt(2) + Ctrl(4)）
+	var modifiers []string
+	if len(params) > 1 {
+		if m, err := strconv.Atoi(params[1]); err == nil && m > 1 {
+			bits := m - 1
+			if bits&4 != 0 {
+				modifiers = append(modifiers, "ctrl")
+			}
+			if bits&2 != 0 {
+				modifiers = append(modifiers, "alt")
+			}
+			if bits&1 != 0 {
+				modifiers = append(modifiers, "shift")
+			}
+		}
+	}
+	return withModifiers(modifiers, name), end + 1
+}
+
+// finalKeys は "ESC [ A" や "ESC O P"（アプリケーションモード）のように終端文字で決まるキー
+var finalKeys = map[byte]string{
+	'A': "up", 'B': "down", 'C': "right", 'D': "left",
+	'H': "home", 'F': "end",
+	'P': "f1", 'Q': "f2", 'R': "f3", 'S': "f4",
+}
+
+// tildeKeys は "ESC [ 3 ~" の形で番号によって決まるキー
+var tildeKeys = map[string]string{
+	"1": "home", "2": "insert", "3": "delete", "4": "end", "5": "pgup", "6": "pgdown",
+	"7": "home", "8": "end",
+	"11": "f1", "12": "f2", "13": "f3", "14": "f4", "15": "f5",
+	"17": "f6", "18": "f7", "19": "f8", "20": "f9", "21": "f10", "23": "f11", "24": "f12",
+}
+
+// Decoder はターミナルから読み取ったバイト列をキーに変換する
+// 読み取りの境界で分かれたマルチバイト文字やシーケンスは次の読み取りとつなげる
+type Decoder struct {
+	pending []byte
+}
+
+// Feed は読み取ったバイト列を加え、確定したキーを返す
+// 読み取りの最後に残った単独の ESC はエスケープキーとみなす
+// （ターミナルはエスケープシーケンスをまとめて送るため）
+func (d *Decoder) Feed(b []byte) []Key {
+	d.pending = append(d.pending, b...)
+	var keys []Key
+	for len(d.pending) > 0 {
+		k, n := Decode(d.pending)
+		if n == 0 {
+			if len(d.pending) == 1 && d.pending[0] == esc {
+				keys = append(keys, "esc")
+				d.pending = d.pending[:0]
+			}
+			break
+		}
+		keys = append(keys, k)
+		d.pending = d.pending[n:]
+	}
+	return keys
+}
@@ -0,0 +1,88 @@
+package keys
+
+import (
+	"slices"
+	"testing"
+)
+
+// =============================================================================
+// Decode - バイト列からキーへの変換
+// =============================================================================
+
+func TestDecodeは1つのキーと使ったバイト数を返す(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Key
+		n     int
+	}{
+		{"q", "q", 1},
+		{" ", "space", 1},
+		{"\r", "enter", 1},
+		{"\x7f", "backspace", 1},
+		{"\x03", "ctrl+c", 1},
+		{"\x1b[A", "up", 3},
+		{"\x1bOB", "down", 3},
+		{"\x1b[1;5C", "ctrl+right", 6},
+		{"\x1b[1;3D", "alt+left", 6},
+		{"\x1b[3~", "delete", 4},
+		{"\x1b[15;2~", "shift+f5", 7},
+		{"\x1bOP", "f1", 3},
+		{"\x1b[Z", "shift+tab", 3},
+		{"\x1bx", "alt+x", 2},
+		{"\x1b\x03", "ctrl+alt+c", 2},
+		{"\x1b\x1b", "esc", 1},
+		{"あいう", "あ", 3},
+		{"\x1b[200~", Unknown, 6},
+		{"\xff", Unknown, 1},
+	}
+	for _, tt := range tests {
+		got, n := Decode([]byte(tt.input))
+		if got != tt.want || n != tt.n {
+			t.Errorf("Decode(%q) = %q, %d, want %q, %d", tt.input, got, n, tt.want, tt.n)
+		}
+	}
+}
+
+func TestDecodeは途中で終わっている入力に0を返す(t *testing.T) {
+	for _, input := range []string{"\x1b", "\x1b[", "\x1b[1;5", "\x1bO", "\xe3\x81"} {
+		if got, n := Decode([]byte(input)); n != 0 {
+			t.Errorf("Decode(%q) = %q, %d, want n = 0", input, got, n)
+		}
+	}
+}
+
+// =============================================================================
+// Decoder - 読み取りをまたぐ入力
+// =============================================================================
+
+func TestDecoderは読み取りで分かれた文字とシーケンスをつなげる(t *testing.T) {
+	var d Decoder
+	var got []Key
+	for _, chunk := range []string{"a\xe3\x81", "\x82\x1b[", "1;5A", "q"} {
+		got = append(got, d.Feed([]byte(chunk))...)
+	}
+
+	want := []Key{"a", "あ", "ctrl+up", "q"}
+	if !slices.Equal(got, want) {
+		t.Errorf("keys = %q, want %q", got, want)
+	}
+}
+
+func TestDecoderは読み取りの最後の単独のESCをエスケープキーにする(t *testing.T) {
+	var d Decoder
+	if got := d.Feed([]byte("s\x1b")); !slices.Equal(got, []Key{"s", "esc"}) {
+		t.Errorf("Feed() = %q, want [s esc]", got)
+	}
+	if got := d.Feed([]byte("q")); !slices.Equal(got, []Key{"q"}) {
+		t.Errorf("Feed() after esc = %q, want [q]", got)
+	}
+}
+
+func TestDecoderは長すぎるシーケンスを読み捨てる(t *testing.T) {
+	var d Decoder
+	long := "\x1b[" + string(make([]byte, maxSequence))
+	d.Feed([]byte(long))
+	if got := d.Feed([]byte("q")); !slices.Equal(got, []Key{"q"}) {
+		t.Errorf("Feed() = %q, want [q]", got)
+	}
+}
@@ -0,0 +1,165 @@
+// Package keys はターミナルの入力をキーに変換し、キーを名前付きのアクションに割り当てる
+package keys
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Key は "q"、"space"、"ctrl+c"、"alt+up" のような正規化したキーの名前
+// 1文字のキーは大文字と小文字を区別する（"Q" は shift+q）
+type Key string
+
+// Unknown は解釈できない入力（未対応のエスケープシーケンスなど）
+const Unknown Key = ""
+
+// namedKeys は名前で表す特殊キー
+var namedKeys = []string{
+	"space", "enter", "tab", "backspace", "esc",
+	"up", "down", "left", "right", "home", "end", "pgup", "pgdown", "insert", "delete",
+	"f1", "f2", "f3", "f4", "f5", "f6", "f7", "f8", "f9", "f10", "f11", "f12",
+}
+
+// keyAliases は設定で使える特殊キーの別名
+var keyAliases = map[string]string{
+	" ":        "space",
+	"return":   "enter",
+	"escape":   "esc",
+	"bs":       "backspace",
+	"del":      "delete",
+	"ins":      "insert",
+	"pageup":   "pgup",
+	"pagedown": "pgdown",
+}
+
+// modifierOrder は修飾キーを並べる順（"ctrl+alt+shift+up"）
+var modifierOrder = []string{"ctrl", "alt", "shift"}
+
+// modifierAliases は設定で使える修飾キーの別名
+var modifierAliases = map[string]string{
+	"control": "ctrl",
+	"meta":    "alt",
+	"option":  "alt",
+}
+
+// ctrlNamed はターミナルが特殊キーと同じバイトを送る Ctrl キー
+var ctrlNamed = map[string]string{
+	"ctrl+h": "backspace",
+	"ctrl+i": "tab",
+	"ctrl+j": "enter",
+	"ctrl+m": "enter",
+	"ctrl+[": "esc",
+}
+
+// Parse は設定に書かれたキーの名前を正規化する
+// 修飾キーと特殊キーの名前は大文字と小文字を区別しない（"Ctrl+C" は "ctrl+c"）
+func Parse(s string) (Key, error) {
+	if s == " " {
+		return "space", nil
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Unknown, fmt.Errorf("empty key")
+	}
+
+	rawModifiers, base := Key(s).split()
+	var modifiers []string
+	for _, m := range rawModifiers {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if alias, ok := modifierAliases[m]; ok {
+			m = alias
+		}
+		if !slices.Contains(modifierOrder, m) {
+			return Unknown, fmt.Errorf("unknown modifier %q in %q (use ctrl, alt or shift)", m, s)
+		}
+		if !slices.Contains(modifiers, m) {
+			modifiers = append(modifiers, m)
+		}
+	}
+
+	if utf8.RuneCountInString(base) == 1 && base != " " {
+		r, _ := utf8.DecodeRuneInString(base)
+		switch {
+		case slices.Contains(modifiers, "ctrl") && !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '['):
+			return Unknown, fmt.Errorf("%q: ctrl can only be combined with a letter", s)
+		case slices.Contains(modifiers, "ctrl"):
+			// ターミナルは Ctrl+C と Ctrl+Shift+C を区別できない
+			base = string(unicode.ToLower(r))
+			modifiers = slices.DeleteFunc(modifiers, func(m string) bool { return m == "shift" })
+		case slices.Contains(modifiers, "shift"):
+			return Unknown, fmt.Errorf("%q: write the shifted character instead of shift+", s)
+		case !unicode.IsPrint(r):
+			return Unknown, fmt.Errorf("%q is not a printable character", s)
+		}
+	} else {
+		base = strings.ToLower(base)
+		if alias, ok := keyAliases[base]; ok {
+			base = alias
+		}
+		if !slices.Contains(namedKeys, base) {
+			return Unknown, fmt.Errorf("unknown key %q", s)
+		}
+	}
+
+	k := withModifiers(modifiers, base)
+	if named, ok := ctrlNamed[string(k)]; ok {
+		k = Key(named)
+	}
+	return k, nil
+}
+
+// withModifiers は修飾キーを決まった順に並べたキーを返す
+func withModifiers(modifiers []string, base string) Key {
+	var parts []string
+	for _, m := range modifierOrder {
+		if slices.Contains(modifiers, m) {
+			parts = append(parts, m)
+		}
+	}
+	return Key(strings.Join(append(parts, base), "+"))
+}
+
+// split はキーを修飾キーと本体に分ける（"ctrl++" の本体は "+"）
+func (k Key) split() (modifiers []string, base string) {
+	s := string(k)
+	i := strings.LastIndex(strings.TrimSuffix(s, "+"), "+")
+	if i < 0 {
+		return nil, s
+	}
+	return strings.Split(s[:i], "+"), s[i+1:]
+}
+
+// Rune は修飾キーのない文字のキー（"space" を含む）をその文字として返す
+// 入力欄への文字の入力に使う
+func (k Key) Rune() (rune, bool) {
+	if k == "space" {
+		return ' ', true
+	}
+	if utf8.RuneCountInString(string(k)) != 1 {
+		return 0, false
+	}
+	r, _ := utf8.DecodeRuneInString(string(k))
+	return r, unicode.IsPrint(r)
+}
+
+// Display は "Space" や "Ctrl+C" のような表示用の名前を返す
+func (k Key) Display() string {
+	if k == Unknown {
+		return "?"
+	}
+	modifiers, base := k.split()
+	var parts []string
+	for _, m := range modifiers {
+		parts = append(parts, strings.ToUpper(m[:1])+m[1:])
+	}
+	switch {
+	case utf8.RuneCountInString(base) > 1:
+		base = strings.ToUpper(base[:1]) + base[1:]
+	case slices.Contains(modifiers, "ctrl"):
+		base = strings.ToUpper(base)
+	}
+	return strings.Join(append(parts, base), "+")
+}
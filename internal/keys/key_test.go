@@ -0,0 +1,99 @@
+package keys
+
+import (
+	"testing"
+)
+
+// =============================================================================
+// Parse - 設定のキー名の正規化
+// =============================================================================
+
+func TestParseはキー名を正規化する(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Key
+	}{
+		{"q", "q"},
+		{"Q", "Q"},
+		{" ", "space"},
+		{"Space", "space"},
+		{"Ctrl+C", "ctrl+c"},
+		{"ctrl+shift+c", "ctrl+c"},
+		{"alt+ctrl+x", "ctrl+alt+x"},
+		{"meta+Up", "alt+up"},
+		{"shift+tab", "shift+tab"},
+		{"PageDown", "pgdown"},
+		{"ctrl+m", "enter"},
+		{"alt++", "alt++"},
+		{"+", "+"},
+		{"あ", "あ"},
+		{"F5", "f5"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseは不正なキー名をエラーにする(t *testing.T) {
+	for _, input := range []string{"", "hyper+x", "shift+a", "ctrl+1", "ctrl++", "f13", "foo", "\x01"} {
+		if k, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) = %q, want error", input, k)
+		}
+	}
+}
+
+// =============================================================================
+// Display - 表示用の名前
+// =============================================================================
+
+func TestDisplayは表示用の名前を返す(t *testing.T) {
+	tests := []struct {
+		key  Key
+		want string
+	}{
+		{"space", "Space"},
+		{"q", "q"},
+		{"Q", "Q"},
+		{"ctrl+c", "Ctrl+C"},
+		{"alt+up", "Alt+Up"},
+		{"alt++", "Alt++"},
+		{Unknown, "?"},
+	}
+	for _, tt := range tests {
+		if got := tt.key.Display(); got != tt.want {
+			t.Errorf("Key(%q).Display() = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+// =============================================================================
+// Rune - 入力欄への文字
+// =============================================================================
+
+func TestRuneは修飾キーのない文字だけを返す(t *testing.T) {
+	tests := []struct {
+		key  Key
+		want rune
+		ok   bool
+	}{
+		{"a", 'a', true},
+		{"space", ' ', true},
+		{"あ", 'あ', true},
+		{"+", '+', true},
+		{"ctrl+a", 0, false},
+		{"enter", 0, false},
+		{Unknown, 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.key.Rune(); got != tt.want || ok != tt.ok {
+			t.Errorf("Key(%q).Rune() = %q, %v, want %q, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
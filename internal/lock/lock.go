@@ -0,0 +1,190 @@
+// Package lock は logind のセッションのロック（画面ロック）を監視し、画面をロックする
+package lock
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Event は画面ロックの状態の変化
+type Event int
+
+const (
+	// Locked は画面がロックされた
+	Locked Event = iota + 1
+	// Unlocked は画面のロックが解除された
+	Unlocked
+)
+
+// String はイベントを文字列に変換する
+func (e Event) String() string {
+	switch e {
+	case Locked:
+		return "locked"
+	case Unlocked:
+		return "unlocked"
+	default:
+		return "unknown"
+	}
+}
+
+// Watcher は画面ロックの変化を通知し、画面をロックする（テストで差し替える）
+type Watcher interface {
+	// Events は画面ロックの変化を受け取るチャネルを返す（Close で閉じる）
+	Events() <-chan Event
+	// Lock は画面をロックする
+	Lock() error
+	Close() error
+}
+
+const (
+	loginService     = "org.freedesktop.login1"
+	managerPath      = "/org/freedesktop/login1"
+	managerInterface = "org.freedesktop.login1.Manager"
+	sessionInterface = "org.freedesktop.login1.Session"
+	propsInterface   = "org.freedesktop.DBus.Properties"
+)
+
+// Logind はシステムバスで logind のセッションの Lock/Unlock シグナルと
+// LockedHint プロパティの変化を監視する
+type Logind struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+	signals chan *dbus.Signal
+	events  chan Event
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewLogind はシステムバスに接続し、XDG_SESSION_ID のセッション（なければ自分のプロセスのセッション）を監視する
+func NewLogind() (*Logind, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the system bus: %w", err)
+	}
+	session, err := sessionPath(conn)
+	if err != nil {
+		// 接続できなかった理由のほうを返すので、閉じるエラーは使わない
+		_ = conn.Close()
+		return nil, err
+	}
+	for _, opts := range [][]dbus.MatchOption{
+		{dbus.WithMatchObjectPath(session), dbus.WithMatchInterface(sessionInterface)},
+		{dbus.WithMatchObjectPath(session), dbus.WithMatchInterface(propsInterface), dbus.WithMatchMember("PropertiesChanged")},
+	} {
+		if err := conn.AddMatchSignal(opts...); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to watch the login session: %w", err)
+		}
+	}
+
+	l := &Logind{
+		conn:    conn,
+		session: session,
+		signals: make(chan *dbus.Signal, 8),
+		events:  make(chan Event, 1),
+		done:    make(chan struct{}),
+	}
+	conn.Signal(l.signals)
+	go l.forward()
+	return l, nil
+}
+
+// sessionPath は監視するセッションのオブジェクトパスを logind に問い合わせる
+func sessionPath(conn *dbus.Conn) (dbus.ObjectPath, error) {
+	manager := conn.Object(loginService, managerPath)
+	var call *dbus.Call
+	if id := os.Getenv("XDG_SESSION_ID"); id != "" {
+		call = manager.Call(managerInterface+".GetSession", 0, id)
+	} else {
+		call = manager.Call(managerInterface+".GetSessionByPID", 0, uint32(os.Getpid()))
+	}
+	var path dbus.ObjectPath
+	if err := call.Store(&path); err != nil {
+		return "", fmt.Errorf("failed to find the login session: %w", err)
+	}
+	return path, nil
+}
+
+// forward はシグナルをイベントに変換して送る（同じ状態が続く通知は送らない）
+func (l *Logind) forward() {
+	defer close(l.events)
+	var last Event
+	for {
+		select {
+		case <-l.done:
+			return
+		case sig, ok := <-l.signals:
+			if !ok {
+				return
+			}
+			if sig.Path != l.session {
+				continue
+			}
+			event, ok := eventFromSignal(sig)
+			if !ok || event == last {
+				continue
+			}
+			last = event
+			select {
+			case l.events <- event:
+			case <-l.done:
+				return
+			}
+		}
+	}
+}
+
+// eventFromSignal は logind のシグナルを画面ロックのイベントに変換する
+// Lock/Unlock はロックの要求、LockedHint はスクリーンロッカーが報告する実際の状態
+func eventFromSignal(sig *dbus.Signal) (Event, bool) {
+	switch sig.Name {
+	case sessionInterface + ".Lock":
+		return Locked, true
+	case sessionInterface + ".Unlock":
+		return Unlocked, true
+	case propsInterface + ".PropertiesChanged":
+		if len(sig.Body) < 2 {
+			return 0, false
+		}
+		if iface, _ := sig.Body[0].(string); iface != sessionInterface {
+			return 0, false
+		}
+		changed, _ := sig.Body[1].(map[string]dbus.Variant)
+		hint, ok := changed["LockedHint"]
+		if !ok {
+			return 0, false
+		}
+		locked, ok := hint.Value().(bool)
+		if !ok {
+			return 0, false
+		}
+		if locked {
+			return Locked, true
+		}
+		return Unlocked, true
+	}
+	return 0, false
+}
+
+// Events は画面ロックの変化を受け取るチャネルを返す
+func (l *Logind) Events() <-chan Event {
+	return l.events
+}
+
+// Lock は logind にセッションのロックを要求する（スクリーンロッカーが画面をロックする）
+func (l *Logind) Lock() error {
+	if err := l.conn.Object(loginService, l.session).Call(sessionInterface+".Lock", 0).Err; err != nil {
+		return fmt.Errorf("failed to lock the session: %w", err)
+	}
+	return nil
+}
+
+// Close は監視をやめてシステムバスから切断する
+func (l *Logind) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return l.conn.Close()
+}
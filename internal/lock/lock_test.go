@@ -0,0 +1,68 @@
+package lock
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// =============================================================================
+// eventFromSignal - シグナルの変換
+// =============================================================================
+
+func TestEventFromSignalはlogindのシグナルをイベントに変換する(t *testing.T) {
+	changed := func(iface string, props map[string]dbus.Variant) *dbus.Signal {
+		return &dbus.Signal{Name: propsInterface + ".PropertiesChanged", Body: []any{iface, props, []string{}}}
+	}
+
+	tests := []struct {
+		name   string
+		signal *dbus.Signal
+		want   Event
+		ok     bool
+	}{
+		{"Lock", &dbus.Signal{Name: sessionInterface + ".Lock"}, Locked, true},
+		{"Unlock", &dbus.Signal{Name: sessionInterface + ".Unlock"}, Unlocked, true},
+		{"LockedHint true", changed(sessionInterface, map[string]dbus.Variant{"LockedHint": dbus.MakeVariant(true)}), Locked, true},
+		{"LockedHint false", changed(sessionInterface, map[string]dbus.Variant{"LockedHint": dbus.MakeVariant(false)}), Unlocked, true},
+		{"他のプロパティ", changed(sessionInterface, map[string]dbus.Variant{"IdleHint": dbus.MakeVariant(true)}), 0, false},
+		{"他のインターフェース", changed("org.example.Other", map[string]dbus.Variant{"LockedHint": dbus.MakeVariant(true)}), 0, false},
+		{"他のシグナル", &dbus.Signal{Name: sessionInterface + ".PauseDevice"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := eventFromSignal(tt.signal)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("eventFromSignal() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestForwardは同じ状態の通知をまとめる(t *testing.T) {
+	session := dbus.ObjectPath("/org/freedesktop/login1/session/_31")
+	l := &Logind{
+		session: session,
+		signals: make(chan *dbus.Signal, 8),
+		events:  make(chan Event, 8),
+		done:    make(chan struct{}),
+	}
+	for _, sig := range []*dbus.Signal{
+		{Path: session, Name: sessionInterface + ".Lock"},
+		{Path: session, Name: propsInterface + ".PropertiesChanged", Body: []any{sessionInterface, map[string]dbus.Variant{"LockedHint": dbus.MakeVariant(true)}, []string{}}},
+		{Path: "/org/freedesktop/login1/session/_32", Name: sessionInterface + ".Unlock"},
+		{Path: session, Name: sessionInterface + ".Unlock"},
+	} {
+		l.signals <- sig
+	}
+	close(l.signals)
+	l.forward()
+
+	var got []Event
+	for event := range l.events {
+		got = append(got, event)
+	}
+	if len(got) != 2 || got[0] != Locked || got[1] != Unlocked {
+		t.Errorf("events = %v, want [locked unlocked]", got)
+	}
+}
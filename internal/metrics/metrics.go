@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// counter はカウンターメトリクスの定義
+type counter struct {
+	name string
+	help string
+}
+
+// 収集するカウンター（出力順）
+var (
+	sessionsCompleted = counter{"pomodoro_sessions_completed_total", "Number of sessions that ran to completion."}
+	sessionsSkipped   = counter{"pomodoro_sessions_skipped_total", "Number of sessions stopped or skipped before completion."}
+	focusSeconds      = counter{"pomodoro_focus_seconds_total", "Seconds spent focusing in work sessions, excluding pauses."}
+	pauseSeconds      = counter{"pomodoro_pause_seconds_total", "Seconds sessions spent paused."}
+	interruptions     = counter{"pomodoro_interruptions_total", "Number of times a session was paused."}
+
+	counters = []counter{sessionsCompleted, sessionsSkipped, focusSeconds, pauseSeconds, interruptions}
+)
+
+// timerStates は状態ゲージとして出力する状態の一覧
+var timerStates = []timer.TimerState{timer.StateIdle, timer.StateRunning, timer.StatePaused, timer.StateCompleted}
+
+// labels はセッション種類とタスクのラベル
+type labels struct {
+	sessionType string
+	task        string
+}
+
+// Collector はタイマーイベントからフォーカスの統計を集計する
+type Collector struct {
+	mu     sync.Mutex
+	values map[string]map[labels]float64
+}
+
+// NewCollector は新しいCollectorを作成する
+func NewCollector() *Collector {
+	return &Collector{
+		values: make(map[string]map[labels]float64),
+	}
+}
+
+// Run はイベントチャンネルが閉じられるまでイベントを集計する
+func (c *Collector) Run(events <-chan timer.Event) {
+	for event := range events {
+		c.Observe(event)
+	}
+}
+
+// Observe は1件のイベントを集計する
+// セッションの統計はセッション終了時（完了または停止）にまとめて加算する
+func (c *Collector) Observe(event timer.Event) {
+	if event.Type != timer.EventCompleted && event.Type != timer.EventStopped {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session := event.Session
+	l := labels{sessionType: sessionTypeLabel(session.Type), task: session.Task}
+	if event.Type == timer.EventCompleted {
+		c.add(sessionsCompleted, l, 1)
+	} else {
+		c.add(sessionsSkipped, l, 1)
+	}
+	if session.Type == timer.SessionWork {
+		c.add(focusSeconds, l, session.Elapsed().Seconds())
+	}
+	c.add(pauseSeconds, l, session.PausedTotal.Seconds())
+	c.add(interruptions, l, float64(session.Interruptions))
+}
+
+// add はカウンターに値を加算する（c.mu を保持した状態で呼ぶこと）
+func (c *Collector) add(m counter, l labels, v float64) {
+	series, ok := c.values[m.name]
+	if !ok {
+		series = make(map[labels]float64)
+		c.values[m.name] = series
+	}
+	series[l] += v
+}
+
+// Write は集計値と現在の状態をPrometheusのテキスト形式で書き込む
+func (c *Collector) Write(w io.Writer, state *timer.PomodoroState) error {
+	var b strings.Builder
+
+	c.mu.Lock()
+	for _, m := range counters {
+		writeHeader(&b, m.name, m.help, "counter")
+		series := c.values[m.name]
+		keys := make([]labels, 0, len(series))
+		for l := range series {
+			keys = append(keys, l)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].sessionType != keys[j].sessionType {
+				return keys[i].sessionType < keys[j].sessionType
+			}
+			return keys[i].task < keys[j].task
+		})
+		for _, l := range keys {
+			writeSample(&b, m.name, []string{"type", l.sessionType, "task", l.task}, series[l])
+		}
+	}
+	c.mu.Unlock()
+
+	writeHeader(&b, "pomodoro_timer_state", "Current timer state (1 for the active state).", "gauge")
+	for _, s := range timerStates {
+		v := 0.0
+		if state.TimerState == s {
+			v = 1
+		}
+		writeSample(&b, "pomodoro_timer_state", []string{"state", s.String()}, v)
+	}
+
+	writeHeader(&b, "pomodoro_session_remaining_seconds", "Seconds remaining in the current session.", "gauge")
+	if session := state.CurrentSession; session != nil && state.TimerState != timer.StateIdle {
+		l := []string{"type", sessionTypeLabel(session.Type), "task", session.Task}
+		writeSample(&b, "pomodoro_session_remaining_seconds", l, session.Remaining.Seconds())
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler は /metrics 用のハンドラを返す
+func (c *Collector) Handler(t *timer.Timer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = c.Write(w, t.State())
+	})
+}
+
+// ----------------------------------------------------------------------------
+// テキスト形式のヘルパー関数
+// ----------------------------------------------------------------------------
+
+// writeHeader はHELPとTYPE行を書き込む
+func writeHeader(b *strings.Builder, name, help, metricType string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+}
+
+// writeSample はラベル付きのサンプル行を書き込む（pairs は名前と値の交互）
+func writeSample(b *strings.Builder, name string, pairs []string, v float64) {
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, "%s=\"%s\"", pairs[i], escapeLabel(pairs[i+1]))
+	}
+	b.WriteString("} ")
+	b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	b.WriteByte('\n')
+}
+
+// labelEscaper はラベル値のエスケープ規則
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel はラベル値をエスケープする
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// sessionTypeLabel はセッション種類のラベル値を返す
+func sessionTypeLabel(s timer.SessionType) string {
+	text, err := s.MarshalText()
+	if err != nil {
+		return "unknown"
+	}
+	return string(text)
+}
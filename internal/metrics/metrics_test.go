@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Observe - イベントの集計
+// =============================================================================
+
+func TestWriteは集計値をテキスト形式で出力する(t *testing.T) {
+	c := NewCollector()
+	c.Observe(timer.Event{
+		Type: timer.EventCompleted,
+		Session: timer.Session{
+			Type:          timer.SessionWork,
+			Duration:      25 * time.Minute,
+			Remaining:     0,
+			PausedTotal:   90 * time.Second,
+			Interruptions: 2,
+			Task:          "docs",
+		},
+	})
+	c.Observe(timer.Event{
+		Type: timer.EventStopped,
+		Session: timer.Session{
+			Type:      timer.SessionShortBreak,
+			Duration:  5 * time.Minute,
+			Remaining: 3 * time.Minute,
+		},
+	})
+
+	state := &timer.PomodoroState{
+		TimerState: timer.StateRunning,
+		CurrentSession: &timer.Session{
+			Type:      timer.SessionWork,
+			Duration:  25 * time.Minute,
+			Remaining: 10 * time.Minute,
+			Task:      "docs",
+		},
+	}
+
+	var b strings.Builder
+	if err := c.Write(&b, state); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	expected := `# HELP pomodoro_sessions_completed_total Number of sessions that ran to completion.
+# TYPE pomodoro_sessions_completed_total counter
+pomodoro_sessions_completed_total{type="work",task="docs"} 1
+# HELP pomodoro_sessions_skipped_total Number of sessions stopped or skipped before completion.
+# TYPE pomodoro_sessions_skipped_total counter
+pomodoro_sessions_skipped_total{type="short_break",task=""} 1
+# HELP pomodoro_focus_seconds_total Seconds spent focusing in work sessions, excluding pauses.
+# TYPE pomodoro_focus_seconds_total counter
+pomodoro_focus_seconds_total{type="work",task="docs"} 1500
+# HELP pomodoro_pause_seconds_total Seconds sessions spent paused.
+# TYPE pomodoro_pause_seconds_total counter
+pomodoro_pause_seconds_total{type="short_break",task=""} 0
+pomodoro_pause_seconds_total{type="work",task="docs"} 90
+# HELP pomodoro_interruptions_total Number of times a session was paused.
+# TYPE pomodoro_interruptions_total counter
+pomodoro_interruptions_total{type="short_break",task=""} 0
+pomodoro_interruptions_total{type="work",task="docs"} 2
+# HELP pomodoro_timer_state Current timer state (1 for the active state).
+# TYPE pomodoro_timer_state gauge
+pomodoro_timer_state{state="idle"} 0
+pomodoro_timer_state{state="running"} 1
+pomodoro_timer_state{state="paused"} 0
+pomodoro_timer_state{state="completed"} 0
+# HELP pomodoro_session_remaining_seconds Seconds remaining in the current session.
+# TYPE pomodoro_session_remaining_seconds gauge
+pomodoro_session_remaining_seconds{type="work",task="docs"} 600
+`
+	if got := b.String(); got != expected {
+		t.Errorf("Write() output mismatch\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestObserveは開始や一時停止イベントを集計しない(t *testing.T) {
+	c := NewCollector()
+	session := timer.Session{Type: timer.SessionWork, Duration: time.Minute, Remaining: time.Minute}
+	c.Observe(timer.Event{Type: timer.EventStarted, Session: session})
+	c.Observe(timer.Event{Type: timer.EventPaused, Session: session})
+
+	var b strings.Builder
+	if err := c.Write(&b, &timer.PomodoroState{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if strings.Contains(b.String(), "_total{") {
+		t.Errorf("counters should be empty, got:\n%s", b.String())
+	}
+}
+
+func TestWriteはラベル値をエスケープする(t *testing.T) {
+	c := NewCollector()
+	c.Observe(timer.Event{
+		Type:    timer.EventCompleted,
+		Session: timer.Session{Type: timer.SessionWork, Task: "say \"hi\"\\\n"},
+	})
+
+	var b strings.Builder
+	if err := c.Write(&b, &timer.PomodoroState{}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(b.String(), `task="say \"hi\"\\\n"`) {
+		t.Errorf("escaped label not found in:\n%s", b.String())
+	}
+}
+
+// =============================================================================
+// Handler - /metrics エンドポイント
+// =============================================================================
+
+func TestHandlerはタイマーの現在状態を出力する(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	c := NewCollector()
+	events, unsubscribe := tmr.Subscribe()
+	go c.Run(events)
+
+	tmr.Start(timer.SessionWork)
+	tmr.Pause()
+
+	rec := httptest.NewRecorder()
+	c.Handler(tmr).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	tmr.Stop()
+	unsubscribe()
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `pomodoro_timer_state{state="paused"} 1`) {
+		t.Errorf("paused gauge not found in:\n%s", body)
+	}
+	if !strings.Contains(body, `pomodoro_session_remaining_seconds{type="work",task=""} 1500`) {
+		t.Errorf("remaining gauge not found in:\n%s", body)
+	}
+}
@@ -0,0 +1,187 @@
+// Package plan はタイマーを実際に動かさずにセッションの予定表を作る
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+)
+
+// Slot は予定表の1つのセッション
+type Slot struct {
+	Type  timer.SessionType
+	Start time.Time
+	End   time.Time
+	// Number は作業セッションの通し番号（1から、休憩は 0）
+	Number int
+}
+
+// Duration はセッションの長さを返す
+func (s Slot) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Plan はセッションの予定表
+type Plan struct {
+	Slots []Slot
+}
+
+// Options は予定表を作る条件
+type Options struct {
+	// Start は最初の作業セッションの開始時刻
+	Start time.Time
+	// Until は最後の作業セッションが終わっていなければならない時刻（ゼロ値なら制限なし）
+	Until time.Time
+	// Count は作業セッションの数（0 なら Until まで入るだけ）
+	Count int
+}
+
+// ErrNoLimit は終了時刻も作業セッションの数も指定しなかったときのエラー
+var ErrNoLimit = errors.New("specify an end time or a number of sessions")
+
+// Build は設定の時間と NextSessionType に従ってセッションの並びを作る
+// 作業セッションの後には休憩を入れるが、最後の作業セッションの後の休憩は含めない
+func Build(cfg *config.Config, opts Options) (Plan, error) {
+	if opts.Until.IsZero() && opts.Count <= 0 {
+		return Plan{}, ErrNoLimit
+	}
+	if !opts.Until.IsZero() && !opts.Until.After(opts.Start) {
+		return Plan{}, fmt.Errorf("end time %s is not after start time %s",
+			opts.Until.Format("15:04"), opts.Start.Format("15:04"))
+	}
+	if cfg.WorkDuration <= 0 {
+		return Plan{}, errors.New("work duration must be positive")
+	}
+
+	var p Plan
+	state := &timer.PomodoroState{}
+	at := opts.Start
+	for {
+		sessionType := state.NextSessionType(cfg.SessionsUntilLong)
+		// 直前の作業セッションを完了として数える（NextSessionType は数える前の値で休憩を決める）
+		if state.CurrentSession != nil && state.CurrentSession.Type == timer.SessionWork {
+			state.CompletedWork++
+		}
+
+		slot := Slot{Type: sessionType, Start: at, End: at.Add(durationOf(cfg, sessionType))}
+		if sessionType == timer.SessionWork {
+			if opts.Count > 0 && state.CompletedWork >= opts.Count {
+				break
+			}
+			if !opts.Until.IsZero() && slot.End.After(opts.Until) {
+				break
+			}
+			slot.Number = state.CompletedWork + 1
+		}
+		p.Slots = append(p.Slots, slot)
+		state.CurrentSession = &timer.Session{Type: sessionType}
+		at = slot.End
+	}
+
+	// 最後の作業セッションの後の休憩は予定に含めない
+	for len(p.Slots) > 0 && p.Slots[len(p.Slots)-1].Type != timer.SessionWork {
+		p.Slots = p.Slots[:len(p.Slots)-1]
+	}
+	if len(p.Slots) == 0 {
+		return Plan{}, fmt.Errorf("no %s work session fits before %s",
+			formatMinutes(cfg.WorkDuration), opts.Until.Format("15:04"))
+	}
+	return p, nil
+}
+
+// durationOf はセッション種類に応じた設定の時間を返す
+func durationOf(cfg *config.Config, sessionType timer.SessionType) time.Duration {
+	switch sessionType {
+	case timer.SessionShortBreak:
+		return cfg.ShortBreakDuration
+	case timer.SessionLongBreak:
+		return cfg.LongBreakDuration
+	default:
+		return cfg.WorkDuration
+	}
+}
+
+// Sessions は作業セッションの数を返す
+func (p Plan) Sessions() int {
+	n := 0
+	for _, s := range p.Slots {
+		if s.Type == timer.SessionWork {
+			n++
+		}
+	}
+	return n
+}
+
+// Focus は作業セッションの合計時間を返す
+func (p Plan) Focus() time.Duration {
+	var total time.Duration
+	for _, s := range p.Slots {
+		if s.Type == timer.SessionWork {
+			total += s.Duration()
+		}
+	}
+	return total
+}
+
+// Breaks は休憩の合計時間を返す
+func (p Plan) Breaks() time.Duration {
+	var total time.Duration
+	for _, s := range p.Slots {
+		if s.Type != timer.SessionWork {
+			total += s.Duration()
+		}
+	}
+	return total
+}
+
+// Start は最初のセッションの開始時刻を返す
+func (p Plan) Start() time.Time {
+	if len(p.Slots) == 0 {
+		return time.Time{}
+	}
+	return p.Slots[0].Start
+}
+
+// End は最後のセッションの終了時刻を返す
+func (p Plan) End() time.Time {
+	if len(p.Slots) == 0 {
+		return time.Time{}
+	}
+	return p.Slots[len(p.Slots)-1].End
+}
+
+// ----------------------------------------------------------------------------
+// 時刻の指定
+// ----------------------------------------------------------------------------
+
+// ParseClock は "13:00" のような時刻を now と同じ日の日時にする
+// "now" または空文字列なら now を分単位に切り上げた時刻を返す
+// "2026-01-05 13:00" のように日付も指定できる
+func ParseClock(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "now" {
+		t := now.Truncate(time.Minute)
+		if t.Before(now) {
+			t = t.Add(time.Minute)
+		}
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use HH:MM, YYYY-MM-DD HH:MM or now)", s)
+	}
+	y, m, d := now.Date()
+	return time.Date(y, m, d, clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+}
+
+// formatMinutes は時間を "25m" や "1h30m" の形にする
+func formatMinutes(d time.Duration) string {
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
@@ -0,0 +1,116 @@
+package plan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/timer"
+)
+
+var afternoon = time.Date(2026, 1, 5, 13, 0, 0, 0, time.UTC)
+
+// =============================================================================
+// Build - 予定表の作成
+// =============================================================================
+
+func TestBuildは終了時刻までに終わる作業セッションを並べる(t *testing.T) {
+	p, err := Build(config.Default(), Options{Start: afternoon, Until: afternoon.Add(5 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// 25分の作業と5分/15分の休憩で 13:00-17:45 に9セッション入る
+	if p.Sessions() != 9 {
+		t.Errorf("Sessions() = %d, want 9", p.Sessions())
+	}
+	if got := p.End(); !got.Equal(afternoon.Add(4*time.Hour + 45*time.Minute)) {
+		t.Errorf("End() = %s, want 17:45", got.Format("15:04"))
+	}
+	if p.Focus() != 225*time.Minute || p.Breaks() != 60*time.Minute {
+		t.Errorf("Focus() = %v, Breaks() = %v, want 3h45m / 1h", p.Focus(), p.Breaks())
+	}
+	if last := p.Slots[len(p.Slots)-1]; last.Type != timer.SessionWork || last.Number != 9 {
+		t.Errorf("last slot = %+v, want work session 9", last)
+	}
+}
+
+func TestBuildは4セッションごとに長い休憩を入れる(t *testing.T) {
+	p, err := Build(config.Default(), Options{Start: afternoon, Count: 5})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := []timer.SessionType{
+		timer.SessionWork, timer.SessionShortBreak,
+		timer.SessionWork, timer.SessionShortBreak,
+		timer.SessionWork, timer.SessionShortBreak,
+		timer.SessionWork, timer.SessionLongBreak,
+		timer.SessionWork,
+	}
+	if len(p.Slots) != len(want) {
+		t.Fatalf("len(Slots) = %d, want %d", len(p.Slots), len(want))
+	}
+	for i, slot := range p.Slots {
+		if slot.Type != want[i] {
+			t.Errorf("Slots[%d] = %v, want %v", i, slot.Type, want[i])
+		}
+		if i > 0 && !slot.Start.Equal(p.Slots[i-1].End) {
+			t.Errorf("Slots[%d] starts at %s, want right after the previous slot", i, slot.Start.Format("15:04"))
+		}
+	}
+}
+
+func TestBuildは数と終了時刻の早い方で止まる(t *testing.T) {
+	p, err := Build(config.Default(), Options{Start: afternoon, Until: afternoon.Add(time.Hour), Count: 8})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if p.Sessions() != 2 {
+		t.Errorf("Sessions() = %d, want 2", p.Sessions())
+	}
+}
+
+func TestBuildは条件がおかしければエラーを返す(t *testing.T) {
+	cfg := config.Default()
+	if _, err := Build(cfg, Options{Start: afternoon}); !errors.Is(err, ErrNoLimit) {
+		t.Errorf("no limit: error = %v, want ErrNoLimit", err)
+	}
+	if _, err := Build(cfg, Options{Start: afternoon, Until: afternoon.Add(-time.Hour)}); err == nil {
+		t.Error("until before start: want error")
+	}
+	if _, err := Build(cfg, Options{Start: afternoon, Until: afternoon.Add(10 * time.Minute)}); err == nil {
+		t.Error("no session fits: want error")
+	}
+}
+
+// =============================================================================
+// ParseClock - 時刻の指定
+// =============================================================================
+
+func TestParseClockは時刻と日時とnowを受け付ける(t *testing.T) {
+	now := time.Date(2026, 1, 5, 9, 12, 30, 0, time.UTC)
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"13:00", time.Date(2026, 1, 5, 13, 0, 0, 0, time.UTC)},
+		{"2026-01-06 08:30", time.Date(2026, 1, 6, 8, 30, 0, 0, time.UTC)},
+		{"now", time.Date(2026, 1, 5, 9, 13, 0, 0, time.UTC)},
+		{"", time.Date(2026, 1, 5, 9, 13, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseClock(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseClock(%q) error = %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("ParseClock(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+	if _, err := ParseClock("1pm", now); err == nil {
+		t.Error("ParseClock(1pm) want error")
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// keepAliveInterval はSSE接続を維持するためのコメント送信間隔
+const keepAliveInterval = 15 * time.Second
+
+// eventResponse はタイマーイベントのJSON表現
+type eventResponse struct {
+	Type          string           `json:"type"`
+	CompletedWork int              `json:"completed_work"`
+	Session       *sessionResponse `json:"session"`
+	At            time.Time        `json:"at"`
+}
+
+// handleEvents はタイマーイベントをServer-Sent Eventsで配信する
+// 接続直後に現在の状態を "state" イベントとして送信する
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	events, unsubscribe := s.timer.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSSE(w, "state", newStateResponse(s.timer.State())); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSE(w, event.Type.String(), newEventResponse(event)); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// newEventResponse はイベントをレスポンス形式に変換する
+func newEventResponse(event timer.Event) eventResponse {
+	return eventResponse{
+		Type:          event.Type.String(),
+		CompletedWork: event.CompletedWork,
+		Session:       newSessionResponse(event.Session),
+		At:            event.At,
+	}
+}
+
+// writeSSE はSSE形式で1件のイベントを書き込む
+func writeSSE(w io.Writer, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+	return err
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/metrics"
+	"pomodoro-cli/internal/timer"
+)
+
+// Server はタイマーを操作するHTTP/JSON APIを提供する
+type Server struct {
+	timer   *timer.Timer
+	cfg     *config.Config
+	token   string
+	metrics *metrics.Collector
+}
+
+// New は新しいServerを作成する
+func New(t *timer.Timer, cfg *config.Config, token string) *Server {
+	return &Server{
+		timer: t,
+		cfg:   cfg,
+		token: token,
+	}
+}
+
+// EnableMetrics は /metrics エンドポイントを有効にする
+func (s *Server) EnableMetrics(c *metrics.Collector) {
+	s.metrics = c
+}
+
+// Handler はトークン認証付きのAPIハンドラを返す
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/state", s.handleState)
+	mux.HandleFunc("POST /api/start", s.handleStart)
+	mux.HandleFunc("POST /api/pause", s.handlePause)
+	mux.HandleFunc("POST /api/resume", s.handleResume)
+	mux.HandleFunc("POST /api/stop", s.handleStop)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	if s.metrics != nil {
+		mux.Handle("GET /metrics", s.metrics.Handler(s.timer))
+	}
+	return s.authorize(mux)
+}
+
+// authorize はBearerトークンまたはtokenクエリを検証する
+// ブラウザのEventSourceはヘッダーを付けられないためクエリも受け付ける
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ----------------------------------------------------------------------------
+// ハンドラ
+// ----------------------------------------------------------------------------
+
+// handleState は現在の状態を返す
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, newStateResponse(s.timer.State()))
+}
+
+// startRequest は /api/start のリクエストボディ
+type startRequest struct {
+	Type *timer.SessionType `json:"type"`
+	Task *string            `json:"task"`
+	Tags []string           `json:"tags"`
+}
+
+// handleStart はセッションを開始する（種類の指定がなければ次のセッション）
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	var req startRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	state := s.timer.State()
+	sessionType := state.NextSessionType(s.cfg.SessionsUntilLong)
+	if req.Type != nil {
+		sessionType = *req.Type
+	}
+	// 実行中のセッションは先に停止して、TUI と同じくスキップとして記録されるようにする
+	// （タスクやタグを変える前に止めて、停止したセッションの記録を書き換えない）
+	if state.TimerState == timer.StateRunning || state.TimerState == timer.StatePaused {
+		s.timer.Stop()
+	}
+	if req.Task != nil {
+		s.timer.SetTask(*req.Task)
+	}
+	if req.Tags != nil {
+		s.timer.SetTags(req.Tags)
+	}
+	s.timer.Start(sessionType)
+	writeJSON(w, http.StatusOK, newStateResponse(s.timer.State()))
+}
+
+// handlePause は実行中のセッションを一時停止する
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	if s.timer.State().TimerState != timer.StateRunning {
+		writeError(w, http.StatusConflict, "timer is not running")
+		return
+	}
+	s.timer.Pause()
+	writeJSON(w, http.StatusOK, newStateResponse(s.timer.State()))
+}
+
+// handleResume は一時停止中のセッションを再開する
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	if s.timer.State().TimerState != timer.StatePaused {
+		writeError(w, http.StatusConflict, "timer is not paused")
+		return
+	}
+	s.timer.Resume()
+	writeJSON(w, http.StatusOK, newStateResponse(s.timer.State()))
+}
+
+// handleStop は現在のセッションを停止する
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	s.timer.Stop()
+	writeJSON(w, http.StatusOK, newStateResponse(s.timer.State()))
+}
+
+// ----------------------------------------------------------------------------
+// レスポンス
+// ----------------------------------------------------------------------------
+
+// sessionResponse はセッションのJSON表現
+type sessionResponse struct {
+	Type             timer.SessionType `json:"type"`
+	Task             string            `json:"task,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	DurationSeconds  int               `json:"duration_seconds"`
+	RemainingSeconds int               `json:"remaining_seconds"`
+	StartedAt        time.Time         `json:"started_at"`
+}
+
+// stateResponse はタイマー状態のJSON表現
+type stateResponse struct {
+	State         string           `json:"state"`
+	CompletedWork int              `json:"completed_work"`
+	Session       *sessionResponse `json:"session,omitempty"`
+}
+
+// newSessionResponse はセッションをレスポンス形式に変換する
+func newSessionResponse(session timer.Session) *sessionResponse {
+	return &sessionResponse{
+		Type:             session.Type,
+		Task:             session.Task,
+		Tags:             session.Tags,
+		DurationSeconds:  int(session.Duration.Seconds()),
+		RemainingSeconds: int(session.Remaining.Seconds()),
+		StartedAt:        session.StartedAt,
+	}
+}
+
+// newStateResponse は状態をレスポンス形式に変換する
+func newStateResponse(state *timer.PomodoroState) stateResponse {
+	resp := stateResponse{
+		State:         state.TimerState.String(),
+		CompletedWork: state.CompletedWork,
+	}
+	if state.CurrentSession != nil {
+		resp.Session = newSessionResponse(*state.CurrentSession)
+	}
+	return resp
+}
+
+// writeJSON はJSONレスポンスを書き込む
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError はエラーレスポンスを書き込む
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
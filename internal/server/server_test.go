@@ -0,0 +1,336 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/metrics"
+	"pomodoro-cli/internal/timer"
+)
+
+const testToken = "secret"
+
+// =============================================================================
+// Authorization - トークン認証
+// =============================================================================
+
+func TestトークンがないリクエストはUnauthorizedを返す(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/api/state")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func Testトークンはクエリパラメータでも受け付ける(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/api/state?token=" + testToken)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+// =============================================================================
+// Control - タイマーの操作
+// =============================================================================
+
+func TestStateはアイドル状態を返す(t *testing.T) {
+	srv := newTestServer(t)
+
+	state := doRequest(t, srv, http.MethodGet, "/api/state", "", http.StatusOK)
+	if state.State != "idle" {
+		t.Errorf("state = %q, want idle", state.State)
+	}
+	if state.Session != nil {
+		t.Error("session should be omitted when idle")
+	}
+}
+
+func TestStartは種類の指定がなければWorkを開始する(t *testing.T) {
+	srv := newTestServer(t)
+
+	state := doRequest(t, srv, http.MethodPost, "/api/start", "", http.StatusOK)
+	if state.State != "running" {
+		t.Errorf("state = %q, want running", state.State)
+	}
+	if state.Session == nil || state.Session.Type != timer.SessionWork {
+		t.Fatalf("session = %+v, want work session", state.Session)
+	}
+	if state.Session.DurationSeconds != 25*60 {
+		t.Errorf("duration_seconds = %d, want %d", state.Session.DurationSeconds, 25*60)
+	}
+}
+
+func TestStartは指定された種類のセッションを開始する(t *testing.T) {
+	srv := newTestServer(t)
+
+	state := doRequest(t, srv, http.MethodPost, "/api/start", `{"type":"long_break"}`, http.StatusOK)
+	if state.Session == nil || state.Session.Type != timer.SessionLongBreak {
+		t.Fatalf("session = %+v, want long break session", state.Session)
+	}
+}
+
+func TestStartはタスクを設定する(t *testing.T) {
+	srv := newTestServer(t)
+
+	state := doRequest(t, srv, http.MethodPost, "/api/start", `{"task":"docs"}`, http.StatusOK)
+	if state.Session == nil || state.Session.Task != "docs" {
+		t.Fatalf("session = %+v, want task docs", state.Session)
+	}
+}
+
+func TestStartは実行中のセッションを停止してから開始する(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	srv := httptest.NewServer(New(tmr, cfg, testToken).Handler())
+	t.Cleanup(func() {
+		tmr.Stop()
+		srv.Close()
+	})
+	doRequest(t, srv, http.MethodPost, "/api/start", `{"task":"docs"}`, http.StatusOK)
+
+	events, unsubscribe := tmr.Subscribe()
+	defer unsubscribe()
+	doRequest(t, srv, http.MethodPost, "/api/start", `{"task":"review"}`, http.StatusOK)
+
+	// 停止したセッションは元のタスクのまま Stopped で知らせる（serve が skipped として記録する）
+	expected := []struct {
+		eventType timer.EventType
+		task      string
+	}{{timer.EventStopped, "docs"}, {timer.EventStarted, "review"}}
+	for _, want := range expected {
+		select {
+		case event := <-events:
+			if event.Type != want.eventType || event.Session.Task != want.task {
+				t.Errorf("event = %v (%q), want %v (%q)", event.Type, event.Session.Task, want.eventType, want.task)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %v was not received", want.eventType)
+		}
+	}
+}
+
+func TestStartは不明な種類をBadRequestにする(t *testing.T) {
+	srv := newTestServer(t)
+
+	doRequest(t, srv, http.MethodPost, "/api/start", `{"type":"nap"}`, http.StatusBadRequest)
+}
+
+func TestPauseとResumeで状態が切り替わる(t *testing.T) {
+	srv := newTestServer(t)
+	doRequest(t, srv, http.MethodPost, "/api/start", "", http.StatusOK)
+
+	state := doRequest(t, srv, http.MethodPost, "/api/pause", "", http.StatusOK)
+	if state.State != "paused" {
+		t.Errorf("after pause state = %q, want paused", state.State)
+	}
+
+	state = doRequest(t, srv, http.MethodPost, "/api/resume", "", http.StatusOK)
+	if state.State != "running" {
+		t.Errorf("after resume state = %q, want running", state.State)
+	}
+}
+
+func TestPauseは実行中でなければConflictを返す(t *testing.T) {
+	srv := newTestServer(t)
+
+	doRequest(t, srv, http.MethodPost, "/api/pause", "", http.StatusConflict)
+	doRequest(t, srv, http.MethodPost, "/api/resume", "", http.StatusConflict)
+}
+
+func TestStopはアイドル状態に戻す(t *testing.T) {
+	srv := newTestServer(t)
+	doRequest(t, srv, http.MethodPost, "/api/start", "", http.StatusOK)
+
+	state := doRequest(t, srv, http.MethodPost, "/api/stop", "", http.StatusOK)
+	if state.State != "idle" {
+		t.Errorf("state = %q, want idle", state.State)
+	}
+}
+
+// =============================================================================
+// Events - Server-Sent Events
+// =============================================================================
+
+func TestEventsは現在の状態とタイマーイベントを配信する(t *testing.T) {
+	srv := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/events", nil)
+	if err != nil {
+		t.Fatalf("NewRequest error = %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if name := readEventName(t, reader); name != "state" {
+		t.Errorf("first event = %q, want state", name)
+	}
+
+	doRequest(t, srv, http.MethodPost, "/api/start", "", http.StatusOK)
+	if name := readEventName(t, reader); name != "started" {
+		t.Errorf("event = %q, want started", name)
+	}
+}
+
+// =============================================================================
+// Metrics - /metrics エンドポイント
+// =============================================================================
+
+func TestMetricsは有効にした場合のみ公開される(t *testing.T) {
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	api := New(tmr, cfg, testToken)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	rec := httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status without metrics = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	api.EnableMetrics(metrics.NewCollector())
+	rec = httptest.NewRecorder()
+	api.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status with metrics = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !strings.Contains(rec.Body.String(), "pomodoro_timer_state") {
+		t.Error("metrics output missing pomodoro_timer_state")
+	}
+}
+
+// =============================================================================
+// Token - トークンの保存
+// =============================================================================
+
+func TestLoadOrCreateTokenは初回にトークンを生成して保存する(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pomodoro", "token")
+
+	token, err := LoadOrCreateToken(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateToken() error = %v", err)
+	}
+	if len(token) != tokenBytes*2 {
+		t.Errorf("token length = %d, want %d", len(token), tokenBytes*2)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("token file perm = %o, want 600", perm)
+	}
+
+	again, err := LoadOrCreateToken(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateToken() second call error = %v", err)
+	}
+	if again != token {
+		t.Error("LoadOrCreateToken() should return the saved token")
+	}
+}
+
+// =============================================================================
+// Test Helpers
+// =============================================================================
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := config.Default()
+	tmr := timer.New(cfg)
+	srv := httptest.NewServer(New(tmr, cfg, testToken).Handler())
+	t.Cleanup(func() {
+		tmr.Stop()
+		srv.CloseClientConnections()
+		srv.Close()
+	})
+	return srv
+}
+
+func doRequest(t *testing.T, srv *httptest.Server, method, path, body string, wantStatus int) stateResponse {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest error = %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s status = %d, want %d", method, path, resp.StatusCode, wantStatus)
+	}
+
+	var state stateResponse
+	if wantStatus == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+			t.Fatalf("Decode error = %v", err)
+		}
+	}
+	return state
+}
+
+func readEventName(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	lines := make(chan string)
+	go func() {
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				lines <- strings.TrimSpace(name)
+				return
+			}
+		}
+	}()
+
+	select {
+	case name, ok := <-lines:
+		if !ok {
+			t.Fatal("stream closed before event")
+		}
+		return name
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return ""
+	}
+}
@@ -0,0 +1,249 @@
+package team
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// 再接続の待ち時間（失敗するたびに倍にする）と時計のずれを測る間隔
+const (
+	dialTimeout  = 5 * time.Second
+	minRetry     = time.Second
+	maxRetry     = 30 * time.Second
+	pingInterval = 10 * time.Second
+	// offsetSamples は時計のずれの推定に使う直近の測定数（往復が最も速いものを使う）
+	offsetSamples = 8
+)
+
+// ErrNotConnected はホストに接続していないときに提案したことを表す
+var ErrNotConnected = errors.New("not connected to the host")
+
+// Connection は参加者の接続状態の変化
+type Connection struct {
+	Connected bool
+	// Err は接続が切れた（または接続できなかった）理由
+	Err error
+}
+
+// Client はホストに接続して状態を受け取る参加者
+// 接続が切れると待ち時間を伸ばしながら再接続する
+type Client struct {
+	addr  string
+	name  string
+	retry time.Duration
+
+	messages    chan Message
+	connections chan Connection
+	done        chan struct{}
+	once        sync.Once
+
+	mu      sync.Mutex
+	conn    *conn
+	samples []sample
+	offset  time.Duration
+}
+
+// sample は1回の ping で測った往復時間と時計のずれ
+type sample struct {
+	rtt, offset time.Duration
+}
+
+// Join は addr のホストに name として参加する（接続は別の goroutine で行う）
+func Join(addr, name string) *Client {
+	return join(addr, name, minRetry)
+}
+
+// join は再接続の最初の待ち時間 retry を指定して参加する
+func join(addr, name string, retry time.Duration) *Client {
+	c := &Client{
+		addr:        addr,
+		name:        name,
+		retry:       retry,
+		messages:    make(chan Message, 16),
+		connections: make(chan Connection, 4),
+		done:        make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Messages はホストから受け取ったメッセージのチャネルを返す（pong は含まない）
+func (c *Client) Messages() <-chan Message {
+	return c.messages
+}
+
+// Connections は接続状態の変化のチャネルを返す
+func (c *Client) Connections() <-chan Connection {
+	return c.connections
+}
+
+// Offset はホストの時計 - 参加者の時計の推定値を返す
+func (c *Client) Offset() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.offset
+}
+
+// Propose はホストにアクションを提案する
+func (c *Client) Propose(action string) error {
+	c.mu.Lock()
+	cn := c.conn
+	c.mu.Unlock()
+	if cn == nil {
+		return ErrNotConnected
+	}
+	return cn.send(Message{Type: MsgPropose, Name: c.name, Action: action})
+}
+
+// Close は接続を閉じて再接続をやめる
+func (c *Client) Close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.mu.Lock()
+		if c.conn != nil {
+			// 接続を閉じて session の受信を終わらせるだけなので、閉じるエラーは使わない
+			_ = c.conn.close()
+		}
+		c.mu.Unlock()
+	})
+}
+
+// run は接続が切れるたびに再接続する
+func (c *Client) run() {
+	wait := c.retry
+	for {
+		connected, err := c.session()
+		if c.closed() {
+			return
+		}
+		if connected {
+			wait = c.retry
+		}
+		c.report(Connection{Err: err})
+		select {
+		case <-c.done:
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxRetry)
+	}
+}
+
+// session は1回の接続でメッセージを受け取り続ける（接続できたかと切れた理由を返す）
+func (c *Client) session() (bool, error) {
+	nc, err := net.DialTimeout("tcp", c.addr, dialTimeout)
+	if err != nil {
+		return false, err
+	}
+	cn := newConn(nc)
+	// Close で先に閉じていることがあるので、閉じるエラーは使わない
+	defer func() { _ = cn.close() }()
+	if err := cn.send(Message{Type: MsgHello, Name: c.name}); err != nil {
+		return false, err
+	}
+	if !c.setConn(cn) {
+		return false, nil
+	}
+	defer c.setConn(nil)
+	c.report(Connection{Connected: true})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.ping(cn, stop)
+
+	for {
+		msg, err := cn.receive()
+		if err != nil {
+			return true, err
+		}
+		if msg.Type == MsgPong {
+			c.measure(msg, time.Now())
+			continue
+		}
+		select {
+		case c.messages <- msg:
+		case <-c.done:
+			return true, nil
+		}
+	}
+}
+
+// setConn は現在の接続を切り替える（閉じた後なら false）
+func (c *Client) setConn(cn *conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cn != nil && c.closed() {
+		return false
+	}
+	c.conn = cn
+	return true
+}
+
+// ping は接続している間、定期的に時計のずれを測る
+func (c *Client) ping(cn *conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		if err := cn.send(Message{Type: MsgPing, Sent: time.Now()}); err != nil {
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// measure は pong からホストとの時計のずれを推定する
+// 往復の中間でホストが時刻を読んだとみなし、直近の測定のうち往復が最も速いものを使う
+func (c *Client) measure(pong Message, received time.Time) {
+	if pong.Sent.IsZero() || pong.HostTime.IsZero() {
+		return
+	}
+	rtt := received.Sub(pong.Sent)
+	if rtt < 0 {
+		return
+	}
+	offset := pong.HostTime.Sub(pong.Sent.Add(rtt / 2))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samples = append(c.samples, sample{rtt: rtt, offset: offset})
+	if len(c.samples) > offsetSamples {
+		c.samples = c.samples[1:]
+	}
+	best := c.samples[0]
+	for _, s := range c.samples[1:] {
+		if s.rtt < best.rtt {
+			best = s
+		}
+	}
+	c.offset = best.offset
+}
+
+// report は接続状態の変化を知らせる（受け取りが追いつかなければ古い知らせを捨てる）
+func (c *Client) report(conn Connection) {
+	for {
+		select {
+		case c.connections <- conn:
+			return
+		default:
+		}
+		select {
+		case <-c.connections:
+		default:
+		}
+	}
+}
+
+func (c *Client) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,251 @@
+package team
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// broadcastInterval は参加者に現在の状態を送り直す間隔
+// イベントを取りこぼした参加者や再接続した参加者もこの間隔で追いつく
+const broadcastInterval = time.Second
+
+// helloTimeout は接続してから名前を伝えるまで待つ時間
+const helloTimeout = 10 * time.Second
+
+// NoticeKind はホストに知らせる参加者の動き
+type NoticeKind int
+
+const (
+	// Joined は参加者が接続した
+	Joined NoticeKind = iota
+	// Left は参加者の接続が切れた
+	Left
+	// Proposed は参加者がアクションを提案した
+	Proposed
+)
+
+// Notice は参加者の接続・切断・提案の知らせ
+type Notice struct {
+	Kind   NoticeKind
+	Name   string
+	Action string
+}
+
+// Host は参加者の接続を受け付け、ホストのタイマーの状態を配信する
+type Host struct {
+	ln       net.Listener
+	snapshot func() *timer.PomodoroState
+	now      func() time.Time
+	notices  chan Notice
+
+	mu      sync.Mutex
+	clients map[*conn]string
+	done    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+// Listen は addr で待ち受けを始める（snapshot はホストのタイマーの現在の状態を返す）
+func Listen(addr string, snapshot func() *timer.PomodoroState) (*Host, error) {
+	return listen(addr, snapshot, time.Now)
+}
+
+// listen はホストの時計 now を指定して待ち受けを始める
+func listen(addr string, snapshot func() *timer.PomodoroState, now func() time.Time) (*Host, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	h := &Host{
+		ln:       ln,
+		snapshot: snapshot,
+		now:      now,
+		notices:  make(chan Notice, 16),
+		clients:  make(map[*conn]string),
+		done:     make(chan struct{}),
+	}
+	h.wg.Add(2)
+	go h.accept()
+	go h.broadcastLoop()
+	return h, nil
+}
+
+// Addr は待ち受けているアドレスを返す
+func (h *Host) Addr() net.Addr {
+	return h.ln.Addr()
+}
+
+// Notices は参加者の接続・切断・提案を受け取るチャネルを返す
+func (h *Host) Notices() <-chan Notice {
+	return h.notices
+}
+
+// Members は接続中の参加者の数を返す
+func (h *Host) Members() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// Run はタイマーイベントを参加者に送る（timer.Attach に渡す）
+func (h *Host) Run(events <-chan timer.Event) {
+	for event := range events {
+		h.broadcast(Message{Type: MsgEvent, Event: event.Type.String(), State: eventState(event)})
+	}
+}
+
+// Decide は提案を承認したか却下したかを参加者に知らせる
+func (h *Host) Decide(n Notice, approved bool) {
+	h.broadcast(Message{Type: MsgDecision, Name: n.Name, Action: n.Action, Approved: approved})
+}
+
+// Close は待ち受けをやめ、すべての参加者との接続を閉じる
+func (h *Host) Close() error {
+	var err error
+	h.once.Do(func() {
+		close(h.done)
+		err = h.ln.Close()
+		h.mu.Lock()
+		for c := range h.clients {
+			// 切断は serve が受信の失敗で検知するので、閉じるエラーは使わない
+			_ = c.close()
+		}
+		h.mu.Unlock()
+		h.wg.Wait()
+	})
+	return err
+}
+
+// accept は接続を受け付ける
+func (h *Host) accept() {
+	defer h.wg.Done()
+	for {
+		nc, err := h.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		h.wg.Add(1)
+		go h.serve(newConn(nc))
+	}
+}
+
+// serve は1人の参加者とのやり取りを行う
+func (h *Host) serve(c *conn) {
+	defer h.wg.Done()
+	// Close や broadcast で先に閉じていることがあるので、閉じるエラーは使わない
+	defer func() { _ = c.close() }()
+
+	if err := c.c.SetReadDeadline(time.Now().Add(helloTimeout)); err != nil {
+		return
+	}
+	hello, err := c.receive()
+	if err != nil || hello.Type != MsgHello {
+		return
+	}
+	if err := c.c.SetReadDeadline(time.Time{}); err != nil {
+		return
+	}
+	name := hello.Name
+	if name == "" {
+		name = c.c.RemoteAddr().String()
+	}
+	if !h.add(c, name) {
+		return
+	}
+	defer h.remove(c, name)
+	h.notify(Notice{Kind: Joined, Name: name})
+	// 送れなかった参加者は外す（再接続すれば状態を受け取り直す）
+	if err := c.send(h.stateMessage()); err != nil {
+		return
+	}
+
+	for {
+		msg, err := c.receive()
+		if err != nil {
+			return
+		}
+		switch msg.Type {
+		case MsgPing:
+			if err := c.send(Message{Type: MsgPong, Sent: msg.Sent, HostTime: h.now()}); err != nil {
+				return
+			}
+		case MsgPropose:
+			if msg.Action == ActionPause || msg.Action == ActionSkip {
+				h.notify(Notice{Kind: Proposed, Name: name, Action: msg.Action})
+			}
+		}
+	}
+}
+
+// add は参加者を登録する（閉じた後なら false）
+func (h *Host) add(c *conn, name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-h.done:
+		return false
+	default:
+	}
+	h.clients[c] = name
+	return true
+}
+
+// remove は参加者の登録を外して切断を知らせる
+func (h *Host) remove(c *conn, name string) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+	h.notify(Notice{Kind: Left, Name: name})
+}
+
+// notify はホストに知らせる（受け取りが追いつかなければ捨てる）
+func (h *Host) notify(n Notice) {
+	select {
+	case h.notices <- n:
+	default:
+	}
+}
+
+// broadcastLoop は定期的に現在の状態を送る
+func (h *Host) broadcastLoop() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(broadcastInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+			h.broadcast(h.stateMessage())
+		}
+	}
+}
+
+// stateMessage は現在の状態のメッセージを作る
+func (h *Host) stateMessage() Message {
+	return Message{Type: MsgState, State: NewState(h.snapshot(), h.now())}
+}
+
+// broadcast はすべての参加者にメッセージを送る（送れなかった接続は閉じる）
+func (h *Host) broadcast(msg Message) {
+	h.mu.Lock()
+	clients := make([]*conn, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+	for _, c := range clients {
+		if err := c.send(msg); err != nil {
+			// 閉じると serve の受信が失敗して参加者を外すので、閉じるエラーは使わない
+			_ = c.close()
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package team はチームで同じポモドーロを共有する
+// ホストのタイマーが正で、参加者は同じカウントダウンを表示し、一時停止やスキップを提案できる
+// TCP 上で1行に1つの JSON メッセージをやり取りする
+package team
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// DefaultListen はホストのデフォルトの待ち受けアドレス
+const DefaultListen = ":7626"
+
+// MessageType はメッセージの種類
+type MessageType string
+
+const (
+	// MsgHello は参加者が接続直後に名前を伝える
+	MsgHello MessageType = "hello"
+	// MsgState はホストが現在の状態を送る（接続時と定期的に）
+	MsgState MessageType = "state"
+	// MsgEvent はホストがタイマーイベントを送る（参加者は完了を自分の履歴に記録する）
+	MsgEvent MessageType = "event"
+	// MsgPropose は参加者が一時停止・スキップを提案する
+	MsgPropose MessageType = "propose"
+	// MsgDecision はホストが提案を承認したか却下したかを送る
+	MsgDecision MessageType = "decision"
+	// MsgPing と MsgPong は参加者とホストの時計のずれを測る
+	MsgPing MessageType = "ping"
+	MsgPong MessageType = "pong"
+)
+
+// Message はやり取りする1行のメッセージ
+type Message struct {
+	Type MessageType `json:"type"`
+	// Name は参加者の名前（hello、propose、decision）
+	Name  string `json:"name,omitempty"`
+	State *State `json:"state,omitempty"`
+	// Event はタイマーイベントの名前（event）
+	Event string `json:"event,omitempty"`
+	// Action は提案するアクション（propose、decision）
+	Action   string `json:"action,omitempty"`
+	Approved bool   `json:"approved,omitempty"`
+	// Sent は ping を送った参加者の時刻（pong でそのまま返す）、HostTime は pong を返したホストの時刻
+	Sent     time.Time `json:"sent,omitzero"`
+	HostTime time.Time `json:"host_time,omitzero"`
+}
+
+// 提案できるアクション（pause は実行中なら一時停止、一時停止中なら再開）
+const (
+	ActionPause = "pause"
+	ActionSkip  = "skip"
+)
+
+// State はホストのタイマーの状態
+type State struct {
+	Timer         timer.TimerState  `json:"timer"`
+	Session       timer.SessionType `json:"session"`
+	Task          string            `json:"task,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Duration      time.Duration     `json:"duration"`
+	Remaining     time.Duration     `json:"remaining"`
+	StartedAt     time.Time         `json:"started_at,omitzero"`
+	CompletedWork int               `json:"completed_work"`
+	// EndsAt は実行中のセッションが終わるホストの時計での時刻
+	// 参加者は時計のずれを補正してここから残り時間を計算する
+	EndsAt time.Time `json:"ends_at,omitzero"`
+}
+
+// NewState はタイマーの状態から State を作る（セッションがなければ nil）
+func NewState(state *timer.PomodoroState, now time.Time) *State {
+	if state.CurrentSession == nil {
+		return nil
+	}
+	session := state.CurrentSession
+	s := &State{
+		Timer:         state.TimerState,
+		Session:       session.Type,
+		Task:          session.Task,
+		Tags:          session.Tags,
+		Duration:      session.Duration,
+		Remaining:     session.Remaining,
+		StartedAt:     session.StartedAt,
+		CompletedWork: state.CompletedWork,
+	}
+	if state.TimerState == timer.StateRunning {
+		s.EndsAt = now.Add(session.Remaining)
+	}
+	return s
+}
+
+// eventState はタイマーイベントの時点の State を作る
+func eventState(event timer.Event) *State {
+	session := event.Session
+	s := &State{
+		Session:       session.Type,
+		Task:          session.Task,
+		Tags:          session.Tags,
+		Duration:      session.Duration,
+		Remaining:     session.Remaining,
+		StartedAt:     session.StartedAt,
+		CompletedWork: event.CompletedWork,
+	}
+	switch event.Type {
+	case timer.EventStarted, timer.EventResumed:
+		s.Timer = timer.StateRunning
+		s.EndsAt = event.At.Add(session.Remaining)
+	case timer.EventPaused:
+		s.Timer = timer.StatePaused
+	case timer.EventCompleted:
+		s.Timer = timer.StateCompleted
+		s.Remaining = 0
+	case timer.EventStopped:
+		s.Timer = timer.StateIdle
+	case timer.EventExtended:
+		s.Timer = event.TimerState
+		if s.Timer == timer.StateRunning {
+			s.EndsAt = event.At.Add(session.Remaining)
+		}
+	}
+	return s
+}
+
+// RemainingAt は参加者の時刻 now での残り時間を返す（offset はホストの時計 - 参加者の時計）
+func (s *State) RemainingAt(now time.Time, offset time.Duration) time.Duration {
+	if s.Timer != timer.StateRunning || s.EndsAt.IsZero() {
+		return s.Remaining
+	}
+	return min(max(s.EndsAt.Sub(now.Add(offset)), 0), s.Duration)
+}
+
+// SessionAt は参加者の時刻 now でのセッションを返す（表示や履歴の記録に使う）
+func (s *State) SessionAt(now time.Time, offset time.Duration) timer.Session {
+	return timer.Session{
+		Type:      s.Session,
+		Duration:  s.Duration,
+		Remaining: s.RemainingAt(now, offset),
+		StartedAt: s.StartedAt,
+		Task:      s.Task,
+		Tags:      s.Tags,
+	}
+}
+
+// ----------------------------------------------------------------------------
+// 接続
+// ----------------------------------------------------------------------------
+
+// writeTimeout は1つのメッセージの送信を待つ時間（応答しない相手で止まらないため）
+const writeTimeout = 5 * time.Second
+
+// maxMessageSize は受け取るメッセージの最大サイズ
+const maxMessageSize = 64 * 1024
+
+// conn は JSON Lines でメッセージを読み書きする接続
+type conn struct {
+	c       net.Conn
+	scanner *bufio.Scanner
+	mu      sync.Mutex
+}
+
+func newConn(c net.Conn) *conn {
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 4096), maxMessageSize)
+	return &conn{c: c, scanner: scanner}
+}
+
+// send はメッセージを1行で送る（複数の goroutine から呼べる）
+func (c *conn) send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.c.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err = c.c.Write(append(data, '\n'))
+	return err
+}
+
+// receive は次のメッセージを読む（接続が閉じられたら io.EOF）
+func (c *conn) receive() (Message, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return Message{}, err
+		}
+		return Message{}, io.EOF
+	}
+	var msg Message
+	if err := json.Unmarshal(c.scanner.Bytes(), &msg); err != nil {
+		return Message{}, fmt.Errorf("invalid message: %w", err)
+	}
+	return msg, nil
+}
+
+func (c *conn) close() error {
+	return c.c.Close()
+}
@@ -0,0 +1,243 @@
+package team
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// テスト用のヘルパー
+// =============================================================================
+
+// fakeTimer はホストのタイマーの状態を返す
+type fakeTimer struct {
+	mu    sync.Mutex
+	state timer.PomodoroState
+}
+
+func (f *fakeTimer) snapshot() *timer.PomodoroState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.state
+	if state.CurrentSession != nil {
+		session := *state.CurrentSession
+		state.CurrentSession = &session
+	}
+	return &state
+}
+
+func runningWork(remaining time.Duration) *fakeTimer {
+	return &fakeTimer{state: timer.PomodoroState{
+		TimerState: timer.StateRunning,
+		CurrentSession: &timer.Session{
+			Type:      timer.SessionWork,
+			Duration:  25 * time.Minute,
+			Remaining: remaining,
+			StartedAt: time.Now().Add(-25*time.Minute + remaining),
+			Task:      "docs",
+		},
+	}}
+}
+
+func startHost(t *testing.T, ft *fakeTimer, now func() time.Time) *Host {
+	t.Helper()
+	h, err := listen("127.0.0.1:0", ft.snapshot, now)
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	return h
+}
+
+func joinHost(t *testing.T, h *Host, name string) *Client {
+	t.Helper()
+	c := Join(h.Addr().String(), name)
+	t.Cleanup(c.Close)
+	waitConnection(t, c, true)
+	return c
+}
+
+func waitConnection(t *testing.T, c *Client, connected bool) {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case conn := <-c.Connections():
+			if conn.Connected == connected {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("connected = %v not reported", connected)
+		}
+	}
+}
+
+// waitMessage は typ のメッセージが届くまで待つ
+func waitMessage(t *testing.T, c *Client, typ MessageType) Message {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-c.Messages():
+			if msg.Type == typ {
+				return msg
+			}
+		case <-deadline:
+			t.Fatalf("no %s message", typ)
+		}
+	}
+}
+
+func waitNotice(t *testing.T, h *Host, kind NoticeKind) Notice {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case n := <-h.Notices():
+			if n.Kind == kind {
+				return n
+			}
+		case <-deadline:
+			t.Fatalf("no notice of kind %d", kind)
+		}
+	}
+}
+
+// =============================================================================
+// Host と Client
+// =============================================================================
+
+func Test参加するとホストの状態を受け取る(t *testing.T) {
+	h := startHost(t, runningWork(10*time.Minute), time.Now)
+	c := joinHost(t, h, "alice")
+
+	if n := waitNotice(t, h, Joined); n.Name != "alice" {
+		t.Errorf("joined = %q, want alice", n.Name)
+	}
+	msg := waitMessage(t, c, MsgState)
+	if msg.State == nil || msg.State.Session != timer.SessionWork || msg.State.Timer != timer.StateRunning || msg.State.Task != "docs" {
+		t.Fatalf("state = %+v", msg.State)
+	}
+	remaining := msg.State.RemainingAt(time.Now(), c.Offset())
+	if remaining > 10*time.Minute || remaining < 10*time.Minute-5*time.Second {
+		t.Errorf("remaining = %v, want about 10m", remaining)
+	}
+}
+
+func Test提案はホストに届き決定が参加者に届く(t *testing.T) {
+	h := startHost(t, runningWork(10*time.Minute), time.Now)
+	c := joinHost(t, h, "bob")
+
+	if err := c.Propose(ActionSkip); err != nil {
+		t.Fatalf("Propose() error = %v", err)
+	}
+	n := waitNotice(t, h, Proposed)
+	if n.Name != "bob" || n.Action != ActionSkip {
+		t.Fatalf("notice = %+v", n)
+	}
+
+	h.Decide(n, true)
+	msg := waitMessage(t, c, MsgDecision)
+	if !msg.Approved || msg.Action != ActionSkip || msg.Name != "bob" {
+		t.Errorf("decision = %+v", msg)
+	}
+}
+
+func Testタイマーイベントを参加者に送る(t *testing.T) {
+	h := startHost(t, runningWork(time.Minute), time.Now)
+	c := joinHost(t, h, "carol")
+	waitMessage(t, c, MsgState)
+
+	events := make(chan timer.Event, 1)
+	events <- timer.Event{
+		Type:    timer.EventCompleted,
+		Session: timer.Session{Type: timer.SessionWork, Duration: 25 * time.Minute, Task: "docs"},
+		At:      time.Now(),
+	}
+	close(events)
+	h.Run(events)
+
+	msg := waitMessage(t, c, MsgEvent)
+	if msg.Event != timer.EventCompleted.String() || msg.State.Timer != timer.StateCompleted || msg.State.Remaining != 0 {
+		t.Errorf("event = %+v, state = %+v", msg, msg.State)
+	}
+}
+
+func Test延長したセッションの終了時刻を参加者に送る(t *testing.T) {
+	at := time.Now()
+	session := timer.Session{Type: timer.SessionWork, Duration: 30 * time.Minute, Remaining: 12 * time.Minute}
+
+	s := eventState(timer.Event{Type: timer.EventExtended, Session: session, At: at, TimerState: timer.StateRunning})
+	if s.Timer != timer.StateRunning || !s.EndsAt.Equal(at.Add(12*time.Minute)) || s.Duration != 30*time.Minute {
+		t.Errorf("running extended state = %+v", s)
+	}
+
+	s = eventState(timer.Event{Type: timer.EventExtended, Session: session, At: at, TimerState: timer.StatePaused})
+	if s.Timer != timer.StatePaused || !s.EndsAt.IsZero() || s.Remaining != 12*time.Minute {
+		t.Errorf("paused extended state = %+v", s)
+	}
+}
+
+func Testホストとの時計のずれを補正する(t *testing.T) {
+	skew := time.Hour
+	h := startHost(t, runningWork(10*time.Minute), func() time.Time { return time.Now().Add(skew) })
+	c := joinHost(t, h, "dave")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Offset() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if offset := c.Offset(); offset < skew-time.Second || offset > skew+time.Second {
+		t.Fatalf("Offset() = %v, want about %v", offset, skew)
+	}
+
+	// ホストの時計で表した終了時刻から、参加者の時計でも同じ残り時間になる
+	msg := waitMessage(t, c, MsgState)
+	remaining := msg.State.RemainingAt(time.Now(), c.Offset())
+	if remaining > 10*time.Minute || remaining < 10*time.Minute-5*time.Second {
+		t.Errorf("remaining = %v, want about 10m", remaining)
+	}
+}
+
+func Testホストが再起動すると再接続する(t *testing.T) {
+	ft := runningWork(10 * time.Minute)
+	h := startHost(t, ft, time.Now)
+	addr := h.Addr().String()
+	c := join(addr, "erin", 10*time.Millisecond)
+	t.Cleanup(c.Close)
+	waitConnection(t, c, true)
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	waitConnection(t, c, false)
+	if err := c.Propose(ActionPause); err != ErrNotConnected {
+		t.Errorf("Propose() while disconnected error = %v, want ErrNotConnected", err)
+	}
+
+	h2, err := listen(addr, ft.snapshot, time.Now)
+	if err != nil {
+		t.Skipf("address %s not reusable: %v", addr, err)
+	}
+	defer func() { _ = h2.Close() }()
+	waitConnection(t, c, true)
+	if msg := waitMessage(t, c, MsgState); msg.State == nil {
+		t.Error("再接続後に状態が届かない")
+	}
+}
+
+func TestStateは一時停止中なら残り時間をそのまま返す(t *testing.T) {
+	s := &State{Timer: timer.StatePaused, Duration: 25 * time.Minute, Remaining: 7 * time.Minute}
+	if got := s.RemainingAt(time.Now(), time.Hour); got != 7*time.Minute {
+		t.Errorf("RemainingAt() = %v, want 7m", got)
+	}
+
+	now := time.Now()
+	s = &State{Timer: timer.StateRunning, Duration: 25 * time.Minute, EndsAt: now.Add(time.Hour + 3*time.Minute)}
+	if got := s.RemainingAt(now, time.Hour); got != 3*time.Minute {
+		t.Errorf("RemainingAt() with offset = %v, want 3m", got)
+	}
+}
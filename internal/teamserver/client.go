@@ -0,0 +1,360 @@
+package teamserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"pomodoro-cli/internal/history"
+)
+
+// OutboxFile は送信待ちの記録を保存するファイル名（設定ディレクトリに置く）
+const OutboxFile = "team-outbox.jsonl"
+
+// ----------------------------------------------------------------------------
+// クライアント
+// ----------------------------------------------------------------------------
+
+// Client はチームサーバーに記録を送る
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient は baseURL のサーバーへ token で記録を送る Client を作成する
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Push は記録を送り、サーバーが新しく保存した数を返す
+func (c *Client) Push(ctx context.Context, records []history.Record) (int, error) {
+	payload, err := json.Marshal(records)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode records: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/records", bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// 応答は読み終えているので、閉じるエラーは使わない
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return 0, errUnauthorized
+	case resp.StatusCode >= 300:
+		return 0, fmt.Errorf("team server returned %d: %s", resp.StatusCode, errorMessage(data))
+	}
+	var result recordsResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result.Accepted, nil
+}
+
+// ----------------------------------------------------------------------------
+// 送信待ち
+// ----------------------------------------------------------------------------
+
+// Outbox はまだサーバーに届いていない記録をファイル（JSON Lines）に貯める
+// タスク名などを含むので、ファイルは他のユーザーから読めないようにする
+// 同じファイルを使う他の pomodoro のプロセスとはファイルのロックで調整する
+// 送信に成功した記録だけを取り除くので、送信中に追加された記録は残る
+type Outbox struct {
+	path    string
+	mu      sync.Mutex // ファイルの読み書き
+	flushMu sync.Mutex // 同時に1つだけ送る
+}
+
+// NewOutbox は path に送信待ちを貯める Outbox を作成する
+func NewOutbox(path string) *Outbox {
+	return &Outbox{path: path}
+}
+
+// lockFile は suffix のロックファイルの排他ロックを取る（プロセス間で共有する）
+// 返された関数でロックを外す
+func (o *Outbox) lockFile(suffix string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(o.path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(o.path+suffix, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", f.Name(), err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
+
+// lock はファイルを読み書きする間のロックを取る
+func (o *Outbox) lock() (unlock func(), err error) {
+	o.mu.Lock()
+	unlockFile, err := o.lockFile(".lock")
+	if err != nil {
+		o.mu.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlockFile()
+		o.mu.Unlock()
+	}, nil
+}
+
+// Add は記録を送信待ちに追加する
+func (o *Outbox) Add(r history.Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	unlock, err := o.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(o.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Pending は送信待ちの記録を返す（古い順）
+func (o *Outbox) Pending() ([]history.Record, error) {
+	unlock, err := o.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return o.read()
+}
+
+// read は送信待ちのファイルを読み込む（lock を持って呼ぶ）
+func (o *Outbox) read() ([]history.Record, error) {
+	data, err := os.ReadFile(o.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []history.Record
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var r history.Record
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", o.path, line, err)
+		}
+		records = append(records, r)
+	}
+	return records, sc.Err()
+}
+
+// Flush は送信待ちの記録を client で送り、届いた記録を取り除く
+// 送った数（サーバーで重複とされた記録を含む）を返す
+// 読み込みから書き直しまで送信用のロックを持つので、他のプロセスと同時に送ることはない
+// 送信中も Add はできる
+func (o *Outbox) Flush(ctx context.Context, client *Client) (int, error) {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+	unlockFlush, err := o.lockFile(".flush.lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlockFlush()
+
+	records, err := o.Pending()
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+	if _, err := client.Push(ctx, records); err != nil {
+		return 0, err
+	}
+	return len(records), o.remove(records)
+}
+
+// remove は送った記録を送信待ちから取り除く
+// 記録は開始時刻と種類で見分けるので、ファイルが書き換えられていても送っていない記録は残る
+func (o *Outbox) remove(sent []history.Record) error {
+	unlock, err := o.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	current, err := o.read()
+	if err != nil {
+		return err
+	}
+	delivered := make(map[string]int)
+	for _, r := range sent {
+		delivered[recordKey(r)]++
+	}
+	var rest []history.Record
+	for _, r := range current {
+		if key := recordKey(r); delivered[key] > 0 {
+			delivered[key]--
+			continue
+		}
+		rest = append(rest, r)
+	}
+	if len(rest) == 0 {
+		if err := os.Remove(o.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, r := range rest {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+	}
+	tmp := o.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.path)
+}
+
+// ----------------------------------------------------------------------------
+// アップローダー
+// ----------------------------------------------------------------------------
+
+const (
+	// retryInterval は送信に失敗したあと再び送るまでの間隔
+	retryInterval = time.Minute
+	// flushTimeout は1回の送信にかける時間の上限
+	flushTimeout = 30 * time.Second
+	// closeTimeout は終了時の最後の送信にかける時間の上限
+	closeTimeout = 5 * time.Second
+)
+
+// Uploader は完了した作業セッションを送信待ちに貯めて、バックグラウンドでサーバーへ送る
+// 開始時・記録の追加時・失敗してから retryInterval ごとに送り直す
+type Uploader struct {
+	outbox  *Outbox
+	client  *Client
+	onError func(error)
+	retry   time.Duration
+	kick    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewUploader は outbox の記録を client で送る Uploader を開始する
+// onError は送信が失敗し始めたときに1回だけ呼ばれる（成功するとまた呼ばれるようになる）
+func NewUploader(outbox *Outbox, client *Client, onError func(error)) *Uploader {
+	return newUploader(outbox, client, onError, retryInterval)
+}
+
+func newUploader(outbox *Outbox, client *Client, onError func(error), retry time.Duration) *Uploader {
+	u := &Uploader{
+		outbox:  outbox,
+		client:  client,
+		onError: onError,
+		retry:   retry,
+		kick:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go u.run()
+	return u
+}
+
+// Add は完了した作業セッションの記録を送信待ちに追加して送る（それ以外の記録は無視する）
+func (u *Uploader) Add(r history.Record) error {
+	if !r.IsCompletedWork() {
+		return nil
+	}
+	if err := u.outbox.Add(r); err != nil {
+		return fmt.Errorf("failed to queue record for the team server: %w", err)
+	}
+	select {
+	case u.kick <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close は送信を止める。送信待ちが残っていれば最後に1回だけ送ってみる
+func (u *Uploader) Close() {
+	close(u.done)
+	<-u.stopped
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	_, _ = u.outbox.Flush(ctx, u.client)
+}
+
+func (u *Uploader) run() {
+	defer close(u.stopped)
+	ticker := time.NewTicker(u.retry)
+	defer ticker.Stop()
+	failing := false
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		go func() {
+			select {
+			case <-u.done:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		_, err := u.outbox.Flush(ctx, u.client)
+		cancel()
+		select {
+		case <-u.done:
+			return
+		default:
+		}
+		if err != nil && !failing && u.onError != nil {
+			u.onError(err)
+		}
+		failing = err != nil
+
+		select {
+		case <-u.done:
+			return
+		case <-u.kick:
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package teamserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"pomodoro-cli/internal/history"
+)
+
+// DefaultListen はチームサーバーのデフォルトの待ち受けアドレス
+const DefaultListen = ":7627"
+
+// maxUploadSize は1回に受け取る記録の最大サイズ
+const maxUploadSize = 4 << 20
+
+// Server は記録の受け取りとチームの集計を行う HTTP API を提供する
+type Server struct {
+	store *Store
+	now   func() time.Time
+}
+
+// NewServer は store を使う Server を作成する
+func NewServer(store *Store) *Server {
+	return &Server{store: store, now: time.Now}
+}
+
+// userKey はリクエストのコンテキストに認証したユーザー名を入れるキー
+type userKey struct{}
+
+// Handler はユーザーのトークンで認証する API ハンドラを返す
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/records", s.handleRecords)
+	mux.HandleFunc("GET /api/totals", s.handleTotals)
+	return s.authorize(mux)
+}
+
+// authorize は Bearer トークンのユーザーを確かめる
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		user, ok := s.store.Authenticate(token)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// recordsResponse は POST /api/records の応答
+type recordsResponse struct {
+	Accepted   int `json:"accepted"`
+	Duplicates int `json:"duplicates"`
+}
+
+// handleRecords は認証したユーザーの記録（history の JSON 形式の配列）を保存する
+func (s *Server) handleRecords(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(userKey{}).(string)
+	var records []history.Record
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxUploadSize)).Decode(&records); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid records: "+err.Error())
+		return
+	}
+	accepted, err := s.store.Add(user, records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, recordsResponse{Accepted: accepted, Duplicates: len(records) - accepted})
+}
+
+// handleTotals は period（day または week、デフォルトは day）と date（YYYY-MM-DD、デフォルトは今日）の
+// チームの集計を返す
+func (s *Server) handleTotals(w http.ResponseWriter, r *http.Request) {
+	period := Period(r.URL.Query().Get("period"))
+	if period == "" {
+		period = PeriodDay
+	}
+	date := s.now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		d, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid date (use YYYY-MM-DD)")
+			return
+		}
+		date = d
+	}
+	totals, err := s.store.Totals(period, date)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, totals)
+}
+
+// writeJSON はJSONレスポンスを書き込む
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError はエラーレスポンスを書き込む
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// errorMessage はエラーレスポンスの本文からメッセージを取り出す
+func errorMessage(body []byte) string {
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error == "" {
+		return strings.TrimSpace(string(body))
+	}
+	return resp.Error
+}
+
+// errUnauthorized はトークンが拒否されたことを表す（再送しても成功しない）
+var errUnauthorized = errors.New("the team server rejected the token")
@@ -0,0 +1,291 @@
+// Package teamserver はチームのメンバーが完了したセッションを集めるサーバーと、
+// そこへ記録を送るクライアント（オフラインの間は送信待ちに貯める）を提供する
+package teamserver
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"pomodoro-cli/internal/history"
+)
+
+// userName はユーザー名に使える文字（ファイル名にもなる）
+var userName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Store はユーザーのトークンと記録をディレクトリに保存する
+// トークンはハッシュだけを tokens.json に、記録はユーザーごとの records/NAME.jsonl に保存する
+type Store struct {
+	dir     string
+	mu      sync.Mutex
+	tokens  map[string]string // ユーザー名 → トークンの SHA-256
+	records map[string]*history.Store
+	// tokensInfo は読み込んだ tokens.json の情報（nil なら未読み込み）
+	// 実行中のサーバーにも pomodoro server add-user で追加・再発行したトークンを反映する
+	tokensInfo fs.FileInfo
+}
+
+// OpenStore は dir のデータを読み込んだ Store を返す（なければ作成する）
+func OpenStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "records"), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	s := &Store{dir: dir, tokens: make(map[string]string), records: make(map[string]*history.Store)}
+	if err := s.loadTokens(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// loadTokens は tokens.json を読み込み、新しいユーザーの記録を開く（s.mu を持って呼ぶ）
+// ファイルは置き換えて保存するので、前に読み込んだファイルから変わっていなければ何もしない
+func (s *Store) loadTokens() error {
+	info, err := os.Stat(s.tokensPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tokens: %w", err)
+	}
+	if s.tokensInfo != nil && os.SameFile(info, s.tokensInfo) &&
+		info.ModTime().Equal(s.tokensInfo.ModTime()) && info.Size() == s.tokensInfo.Size() {
+		return nil
+	}
+	data, err := os.ReadFile(s.tokensPath())
+	if err != nil {
+		return fmt.Errorf("failed to read tokens: %w", err)
+	}
+	tokens := make(map[string]string)
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", s.tokensPath(), err)
+	}
+	for user := range tokens {
+		if _, ok := s.records[user]; ok {
+			continue
+		}
+		store, err := history.Open(s.recordsPath(user))
+		if err != nil {
+			return fmt.Errorf("failed to open records of %s: %w", user, err)
+		}
+		s.records[user] = store
+	}
+	s.tokens = tokens
+	s.tokensInfo = info
+	return nil
+}
+
+func (s *Store) tokensPath() string {
+	return filepath.Join(s.dir, "tokens.json")
+}
+
+func (s *Store) recordsPath(user string) string {
+	return filepath.Join(s.dir, "records", user+".jsonl")
+}
+
+// AddUser はユーザーを登録して新しいトークンを返す（登録済みならトークンを作り直す）
+func (s *Store) AddUser(user string) (string, error) {
+	if !userName.MatchString(user) {
+		return "", fmt.Errorf("invalid user name %q (use letters, digits, '.', '_' or '-')", user)
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 他のプロセスで追加したユーザーを上書きしないように読み直す
+	if err := s.loadTokens(); err != nil {
+		return "", err
+	}
+	if _, ok := s.records[user]; !ok {
+		store, err := history.Open(s.recordsPath(user))
+		if err != nil {
+			return "", fmt.Errorf("failed to open records of %s: %w", user, err)
+		}
+		s.records[user] = store
+	}
+	s.tokens[user] = hashToken(token)
+	data, err := json.MarshalIndent(s.tokens, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tokens: %w", err)
+	}
+	// 実行中のサーバーが書きかけのファイルを読まないように、書き終えてから置き換える
+	tmp := s.tokensPath() + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return "", fmt.Errorf("failed to save tokens: %w", err)
+	}
+	if err := os.Rename(tmp, s.tokensPath()); err != nil {
+		return "", fmt.Errorf("failed to save tokens: %w", err)
+	}
+	if info, err := os.Stat(s.tokensPath()); err == nil {
+		s.tokensInfo = info
+	}
+	return token, nil
+}
+
+// Users は登録されているユーザー名を返す
+func (s *Store) Users() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := make([]string, 0, len(s.tokens))
+	for user := range s.tokens {
+		users = append(users, user)
+	}
+	slices.Sort(users)
+	return users
+}
+
+// Authenticate はトークンのユーザーを返す
+// tokens.json が更新されていれば読み直してから探す（再発行した古いトークンはすぐに使えなくなる）
+// 読み直せなければ認証しない
+func (s *Store) Authenticate(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	hash := hashToken(token)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadTokens(); err != nil {
+		return "", false
+	}
+	return s.lookup(hash)
+}
+
+// lookup はトークンのハッシュのユーザーを返す（s.mu を持って呼ぶ）
+func (s *Store) lookup(hash string) (string, bool) {
+	for user, h := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			return user, true
+		}
+	}
+	return "", false
+}
+
+// hashToken はトークンの SHA-256 を返す
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Add はユーザーの記録を保存する（同じ開始時刻と種類の記録は重複として保存しない）
+// 保存した数を返す
+func (s *Store) Add(user string, records []history.Record) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	store, ok := s.records[user]
+	if !ok {
+		return 0, fmt.Errorf("unknown user %q", user)
+	}
+	seen := make(map[string]bool)
+	for _, r := range store.Records() {
+		seen[recordKey(r)] = true
+	}
+	added := 0
+	for _, r := range records {
+		key := recordKey(r)
+		if seen[key] {
+			continue
+		}
+		if err := store.Append(r); err != nil {
+			return added, fmt.Errorf("failed to save record: %w", err)
+		}
+		seen[key] = true
+		added++
+	}
+	return added, nil
+}
+
+// recordKey は記録を見分けるキー（再送された記録を重複として扱うため）
+func recordKey(r history.Record) string {
+	text, _ := r.Type.MarshalText()
+	return r.StartedAt.UTC().Format(time.RFC3339Nano) + " " + string(text)
+}
+
+// ----------------------------------------------------------------------------
+// 集計
+// ----------------------------------------------------------------------------
+
+// Period は集計の期間の単位
+type Period string
+
+const (
+	PeriodDay  Period = "day"
+	PeriodWeek Period = "week"
+)
+
+// Range は day なら date の日、week なら date を含む週（月曜始まり）の期間を返す
+func (p Period) Range(date time.Time) (start, end time.Time, err error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch p {
+	case PeriodDay:
+		return day, day.AddDate(0, 0, 1), nil
+	case PeriodWeek:
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unknown period %q (use day or week)", p)
+}
+
+// UserTotal は1人分の集計
+type UserTotal struct {
+	User         string `json:"user"`
+	Pomodoros    int    `json:"pomodoros"`
+	FocusSeconds int    `json:"focus_seconds"`
+}
+
+// Totals はチームの集計
+type Totals struct {
+	Period       Period      `json:"period"`
+	Start        time.Time   `json:"start"`
+	End          time.Time   `json:"end"`
+	Users        []UserTotal `json:"users"`
+	Pomodoros    int         `json:"pomodoros"`
+	FocusSeconds int         `json:"focus_seconds"`
+}
+
+// Totals は期間に開始した完了済みの作業セッションをユーザーごとに集計する
+// 記録のないユーザーも 0 として含める
+func (s *Store) Totals(period Period, date time.Time) (Totals, error) {
+	start, end, err := period.Range(date)
+	if err != nil {
+		return Totals{}, err
+	}
+	totals := Totals{Period: period, Start: start, End: end, Users: []UserTotal{}}
+	for _, user := range s.Users() {
+		s.mu.Lock()
+		records := s.records[user].Records()
+		s.mu.Unlock()
+		t := UserTotal{User: user}
+		for _, r := range records {
+			if !r.IsCompletedWork() || r.StartedAt.Before(start) || !r.StartedAt.Before(end) {
+				continue
+			}
+			t.Pomodoros++
+			t.FocusSeconds += r.ActualSeconds
+		}
+		totals.Users = append(totals.Users, t)
+		totals.Pomodoros += t.Pomodoros
+		totals.FocusSeconds += t.FocusSeconds
+	}
+	slices.SortStableFunc(totals.Users, func(a, b UserTotal) int {
+		if a.Pomodoros != b.Pomodoros {
+			return b.Pomodoros - a.Pomodoros
+		}
+		return strings.Compare(a.User, b.User)
+	})
+	return totals, nil
+}
@@ -0,0 +1,445 @@
+package teamserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"pomodoro-cli/internal/history"
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// テスト用のヘルパー
+// =============================================================================
+
+// monday は週の集計の基準にする月曜日
+var monday = time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+
+func workRecord(startedAt time.Time, outcome history.Outcome) history.Record {
+	return history.Record{
+		Type:           timer.SessionWork,
+		StartedAt:      startedAt,
+		EndedAt:        startedAt.Add(25 * time.Minute),
+		PlannedSeconds: 1500,
+		ActualSeconds:  1500,
+		Outcome:        outcome,
+	}
+}
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := OpenStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	return store
+}
+
+func addUser(t *testing.T, store *Store, user string) string {
+	t.Helper()
+	token, err := store.AddUser(user)
+	if err != nil {
+		t.Fatalf("AddUser(%q) error = %v", user, err)
+	}
+	return token
+}
+
+func addToOutbox(t *testing.T, outbox *Outbox, r history.Record) {
+	t.Helper()
+	if err := outbox.Add(r); err != nil {
+		t.Fatalf("Outbox.Add() error = %v", err)
+	}
+}
+
+// startServer はテスト用にチームサーバーをローカルで起動する（今日は monday の水曜日）
+func startServer(t *testing.T, store *Store) *httptest.Server {
+	t.Helper()
+	s := NewServer(store)
+	s.now = func() time.Time { return monday.AddDate(0, 0, 2).Add(12 * time.Hour) }
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func getTotals(t *testing.T, srv *httptest.Server, token, query string) (Totals, int) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/totals"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/totals error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var totals Totals
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&totals); err != nil {
+			t.Fatalf("decode totals error = %v", err)
+		}
+	}
+	return totals, resp.StatusCode
+}
+
+// =============================================================================
+// Store
+// =============================================================================
+
+func Testトークンはハッシュだけを保存して再び開いても認証できる(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	token := addUser(t, store, "alice")
+
+	reopened, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	if user, ok := reopened.Authenticate(token); !ok || user != "alice" {
+		t.Errorf("Authenticate() = %q, %v, want alice, true", user, ok)
+	}
+	if _, ok := reopened.Authenticate("wrong"); ok {
+		t.Error("Authenticate(wrong) should fail")
+	}
+	if got := reopened.tokens["alice"]; got == token || got != hashToken(token) {
+		t.Errorf("stored token = %q, want only its hash", got)
+	}
+}
+
+func Testユーザーを登録し直すと古いトークンは使えなくなる(t *testing.T) {
+	store := openStore(t)
+	old := addUser(t, store, "alice")
+	addUser(t, store, "alice")
+	if _, ok := store.Authenticate(old); ok {
+		t.Error("old token should be revoked")
+	}
+	if got := store.Users(); len(got) != 1 {
+		t.Errorf("Users() = %v, want [alice]", got)
+	}
+}
+
+func Test不正なユーザー名は登録できない(t *testing.T) {
+	store := openStore(t)
+	for _, name := range []string{"", "../etc", "a b", ".hidden"} {
+		if _, err := store.AddUser(name); err == nil {
+			t.Errorf("AddUser(%q) should fail", name)
+		}
+	}
+}
+
+func Test実行中のサーバーは別のプロセスで追加したユーザーを認証する(t *testing.T) {
+	dir := t.TempDir()
+	running, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	alice := addUser(t, running, "alice")
+	srv := startServer(t, running)
+
+	// pomodoro server add-user は同じディレクトリを別に開いて書き込む
+	other, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	bob := addUser(t, other, "bob")
+
+	if _, status := getTotals(t, srv, bob, ""); status != http.StatusOK {
+		t.Errorf("GET /api/totals as bob status = %d, want 200", status)
+	}
+	if _, status := getTotals(t, srv, alice, ""); status != http.StatusOK {
+		t.Errorf("GET /api/totals as alice status = %d, want 200", status)
+	}
+
+	// 実行中のサーバーで追加しても、別のプロセスで追加したユーザーは消えない
+	carol := addUser(t, running, "carol")
+	for user, token := range map[string]string{"alice": alice, "bob": bob, "carol": carol} {
+		if got, ok := running.Authenticate(token); !ok || got != user {
+			t.Errorf("Authenticate(%s) = %q, %v, want %s, true", user, got, ok, user)
+		}
+	}
+}
+
+func Test実行中のサーバーは別のプロセスで再発行したトークンの古いほうを拒否する(t *testing.T) {
+	dir := t.TempDir()
+	running, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	old := addUser(t, running, "alice")
+	if _, ok := running.Authenticate(old); !ok {
+		t.Fatal("Authenticate(old) before rotation should succeed")
+	}
+
+	other, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	rotated := addUser(t, other, "alice")
+
+	if _, ok := running.Authenticate(old); ok {
+		t.Error("revoked token is still accepted by the running server")
+	}
+	if user, ok := running.Authenticate(rotated); !ok || user != "alice" {
+		t.Errorf("Authenticate(rotated) = %q, %v, want alice, true", user, ok)
+	}
+}
+
+func Test同じ記録を再送しても重複して保存しない(t *testing.T) {
+	store := openStore(t)
+	addUser(t, store, "alice")
+	r := workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted)
+
+	if n, err := store.Add("alice", []history.Record{r}); err != nil || n != 1 {
+		t.Fatalf("Add() = %d, %v, want 1, nil", n, err)
+	}
+	if n, err := store.Add("alice", []history.Record{r, r}); err != nil || n != 0 {
+		t.Errorf("Add(duplicate) = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func Test集計は期間内に開始した完了済みの作業セッションだけを数える(t *testing.T) {
+	store := openStore(t)
+	addUser(t, store, "alice")
+	addUser(t, store, "bob")
+	addUser(t, store, "carol")
+	if _, err := store.Add("alice", []history.Record{
+		workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted),
+		workRecord(monday.Add(10*time.Hour), history.OutcomeSkipped),
+		workRecord(monday.AddDate(0, 0, 1).Add(9*time.Hour), history.OutcomeCompleted),
+		workRecord(monday.AddDate(0, 0, 7).Add(9*time.Hour), history.OutcomeCompleted),
+	}); err != nil {
+		t.Fatalf("Add(alice) error = %v", err)
+	}
+	if _, err := store.Add("bob", []history.Record{
+		workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted),
+		workRecord(monday.Add(11*time.Hour), history.OutcomeCompleted),
+		workRecord(monday.Add(-time.Hour), history.OutcomeCompleted),
+	}); err != nil {
+		t.Fatalf("Add(bob) error = %v", err)
+	}
+
+	day, err := store.Totals(PeriodDay, monday.Add(15*time.Hour))
+	if err != nil {
+		t.Fatalf("Totals(day) error = %v", err)
+	}
+	want := []UserTotal{{"bob", 2, 3000}, {"alice", 1, 1500}, {"carol", 0, 0}}
+	if len(day.Users) != len(want) {
+		t.Fatalf("Users = %+v, want %+v", day.Users, want)
+	}
+	for i := range want {
+		if day.Users[i] != want[i] {
+			t.Errorf("Users[%d] = %+v, want %+v", i, day.Users[i], want[i])
+		}
+	}
+	if day.Pomodoros != 3 || day.FocusSeconds != 4500 {
+		t.Errorf("day totals = %d, %d, want 3, 4500", day.Pomodoros, day.FocusSeconds)
+	}
+
+	week, err := store.Totals(PeriodWeek, monday.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatalf("Totals(week) error = %v", err)
+	}
+	if !week.Start.Equal(monday) || !week.End.Equal(monday.AddDate(0, 0, 7)) {
+		t.Errorf("week = %v - %v, want the week of %v", week.Start, week.End, monday)
+	}
+	if week.Pomodoros != 4 {
+		t.Errorf("week Pomodoros = %d, want 4", week.Pomodoros)
+	}
+}
+
+// =============================================================================
+// HTTP API
+// =============================================================================
+
+func Test記録はトークンのユーザーとして保存され集計に反映される(t *testing.T) {
+	store := openStore(t)
+	alice := addUser(t, store, "alice")
+	bob := addUser(t, store, "bob")
+	srv := startServer(t, store)
+
+	n, err := NewClient(srv.URL, alice).Push(context.Background(), []history.Record{
+		workRecord(monday.AddDate(0, 0, 2).Add(9*time.Hour), history.OutcomeCompleted),
+		workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted),
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("Push() = %d, %v, want 2, nil", n, err)
+	}
+
+	day, status := getTotals(t, srv, bob, "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if day.Period != PeriodDay || day.Pomodoros != 1 || day.Users[0].User != "alice" {
+		t.Errorf("today = %+v, want 1 pomodoro by alice", day)
+	}
+	week, _ := getTotals(t, srv, bob, "?period=week")
+	if week.Pomodoros != 2 {
+		t.Errorf("week Pomodoros = %d, want 2", week.Pomodoros)
+	}
+	monday, _ := getTotals(t, srv, bob, "?date=2026-10-12")
+	if monday.Pomodoros != 1 {
+		t.Errorf("monday Pomodoros = %d, want 1", monday.Pomodoros)
+	}
+}
+
+func Testトークンがなければ401を返す(t *testing.T) {
+	store := openStore(t)
+	addUser(t, store, "alice")
+	srv := startServer(t, store)
+
+	if _, status := getTotals(t, srv, "wrong", ""); status != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", status)
+	}
+	_, err := NewClient(srv.URL, "wrong").Push(context.Background(), nil)
+	if !errors.Is(err, errUnauthorized) {
+		t.Errorf("Push() error = %v, want errUnauthorized", err)
+	}
+}
+
+func Test不正な期間や日付は400を返す(t *testing.T) {
+	store := openStore(t)
+	token := addUser(t, store, "alice")
+	srv := startServer(t, store)
+
+	for _, query := range []string{"?period=month", "?date=12/10/2026"} {
+		if _, status := getTotals(t, srv, token, query); status != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, status)
+		}
+	}
+}
+
+// =============================================================================
+// 送信待ち
+// =============================================================================
+
+func Testオフラインの間は記録を貯めて接続が戻ったら送る(t *testing.T) {
+	store := openStore(t)
+	token := addUser(t, store, "alice")
+	s := NewServer(store)
+	var mu sync.Mutex
+	online := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		up := online
+		mu.Unlock()
+		if !up {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		s.Handler().ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	outbox := NewOutbox(filepath.Join(t.TempDir(), OutboxFile))
+	errs := make(chan error, 10)
+	u := newUploader(outbox, NewClient(srv.URL, token), func(err error) { errs <- err }, 20*time.Millisecond)
+	defer u.Close()
+
+	for _, r := range []history.Record{
+		workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted),
+		workRecord(monday.Add(10*time.Hour), history.OutcomeSkipped),
+		workRecord(monday.Add(11*time.Hour), history.OutcomeCompleted),
+	} {
+		if err := u.Add(r); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	select {
+	case <-errs:
+	case <-time.After(2 * time.Second):
+		t.Fatal("onError was not called while offline")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(errs) != 0 {
+		t.Errorf("onError called %d more times, want once per outage", len(errs))
+	}
+	if pending, _ := outbox.Pending(); len(pending) != 2 {
+		t.Fatalf("Pending() = %d records, want 2 completed work sessions", len(pending))
+	}
+
+	mu.Lock()
+	online = true
+	mu.Unlock()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		pending, _ := outbox.Pending()
+		if len(pending) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Pending() = %d records after reconnecting, want 0", len(pending))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	totals, _ := store.Totals(PeriodDay, monday)
+	if totals.Pomodoros != 2 {
+		t.Errorf("server Pomodoros = %d, want 2", totals.Pomodoros)
+	}
+}
+
+func TestFlushは送信中に追加された記録を残す(t *testing.T) {
+	store := openStore(t)
+	token := addUser(t, store, "alice")
+	outbox := NewOutbox(filepath.Join(t.TempDir(), OutboxFile))
+	s := NewServer(store)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := outbox.Add(workRecord(monday.Add(12*time.Hour), history.OutcomeCompleted)); err != nil {
+			t.Errorf("Add() during upload error = %v", err)
+		}
+		s.Handler().ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	addToOutbox(t, outbox, workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted))
+	if n, err := outbox.Flush(context.Background(), NewClient(srv.URL, token)); err != nil || n != 1 {
+		t.Fatalf("Flush() = %d, %v, want 1, nil", n, err)
+	}
+	pending, _ := outbox.Pending()
+	if len(pending) != 1 || !pending[0].StartedAt.Equal(monday.Add(12*time.Hour)) {
+		t.Errorf("Pending() = %+v, want the record added during the upload", pending)
+	}
+	if info, err := os.Stat(outbox.path); err != nil {
+		t.Errorf("Stat() error = %v", err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("outbox mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestFlushは送信中にファイルが書き換えられても送った記録だけを取り除く(t *testing.T) {
+	store := openStore(t)
+	token := addUser(t, store, "alice")
+	path := filepath.Join(t.TempDir(), OutboxFile)
+	outbox := NewOutbox(path)
+	s := NewServer(store)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 別のプロセスが送信待ちを送り終えて、新しい記録を1つだけ追加した状態にする
+		other := NewOutbox(path)
+		if err := os.Remove(path); err != nil {
+			t.Errorf("Remove() error = %v", err)
+		}
+		if err := other.Add(workRecord(monday.Add(12*time.Hour), history.OutcomeCompleted)); err != nil {
+			t.Errorf("Add() from the other process error = %v", err)
+		}
+		s.Handler().ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	addToOutbox(t, outbox, workRecord(monday.Add(9*time.Hour), history.OutcomeCompleted))
+	addToOutbox(t, outbox, workRecord(monday.Add(10*time.Hour), history.OutcomeCompleted))
+	if n, err := outbox.Flush(context.Background(), NewClient(srv.URL, token)); err != nil || n != 2 {
+		t.Fatalf("Flush() = %d, %v, want 2, nil", n, err)
+	}
+	pending, _ := outbox.Pending()
+	if len(pending) != 1 || !pending[0].StartedAt.Equal(monday.Add(12*time.Hour)) {
+		t.Errorf("Pending() = %+v, want only the record the other process added", pending)
+	}
+}
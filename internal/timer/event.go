@@ -0,0 +1,189 @@
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType はタイマーイベントの種類を表す
+type EventType int
+
+const (
+	EventStarted EventType = iota
+	EventPaused
+	EventResumed
+	EventStopped
+	EventCompleted
+	// EventExtended は実行中または一時停止中のセッションを延長したことを表す
+	EventExtended
+)
+
+// String はイベント種類の名前を返す
+func (e EventType) String() string {
+	switch e {
+	case EventStarted:
+		return "started"
+	case EventPaused:
+		return "paused"
+	case EventResumed:
+		return "resumed"
+	case EventStopped:
+		return "stopped"
+	case EventCompleted:
+		return "completed"
+	case EventExtended:
+		return "extended"
+	default:
+		return "unknown"
+	}
+}
+
+// Event はタイマーの状態変化を表す
+type Event struct {
+	Type          EventType
+	Session       Session
+	CompletedWork int
+	At            time.Time
+	// TimerState はイベントの後のタイマーの状態（延長の後も実行中か一時停止中かを区別する）
+	TimerState TimerState
+}
+
+// eventBufferSize は購読者ごとのイベントバッファ数
+const eventBufferSize = 16
+
+// eventQueueLimit は購読者ごとに送信待ちにしておけるイベントの上限
+// 接続したまま読まなくなった購読者（SSE のクライアントなど）でメモリが増え続けないようにする
+const eventQueueLimit = 256
+
+// subscriber は購読者ごとの送信待ちのイベントを持つ
+// タイマーは queue に積むだけで、受信を待つのは購読者ごとの goroutine（pump）
+type subscriber struct {
+	ch    chan Event
+	mu    sync.Mutex
+	queue []Event
+	wake  chan struct{}
+	done  chan struct{}
+	// stopped は pump が終了したときに閉じられる
+	stopped chan struct{}
+}
+
+// Subscribe はタイマーイベントの購読を開始する
+// 受信が追いつかなくてもタイマーは止めず、送信待ちが上限を超えたら古いイベントから破棄する
+// 返された関数を呼ぶと購読を解除してチャンネルを閉じる
+func (t *Timer) Subscribe() (<-chan Event, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &subscriber{
+		ch:      make(chan Event, eventBufferSize),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if t.subscribers == nil {
+		t.subscribers = make(map[*subscriber]struct{})
+	}
+	t.subscribers[s] = struct{}{}
+	go s.pump()
+
+	unsubscribe := func() {
+		t.mu.Lock()
+		_, ok := t.subscribers[s]
+		delete(t.subscribers, s)
+		t.mu.Unlock()
+		if ok {
+			s.close()
+		}
+	}
+	return s.ch, unsubscribe
+}
+
+// emit は購読者にイベントを送信する（t.mu を保持した状態で呼ぶこと）
+func (t *Timer) emit(eventType EventType) {
+	if len(t.subscribers) == 0 || t.state.CurrentSession == nil {
+		return
+	}
+	event := Event{
+		Type:          eventType,
+		Session:       *t.state.CurrentSession,
+		CompletedWork: t.state.CompletedWork,
+		At:            time.Now(),
+		TimerState:    t.state.TimerState,
+	}
+	for s := range t.subscribers {
+		s.push(event)
+	}
+}
+
+// push はイベントを送信待ちに積む（上限に達していたら最も古いイベントを捨てる）
+func (s *subscriber) push(event Event) {
+	s.mu.Lock()
+	if len(s.queue) >= eventQueueLimit {
+		s.queue = s.queue[1:]
+	}
+	s.queue = append(s.queue, event)
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pump は送信待ちのイベントを順にチャンネルへ送る
+func (s *subscriber) pump() {
+	defer close(s.stopped)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		// 送信中に push が古いイベントを捨てても取り違えないよう、先に取り出しておく
+		event := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- event:
+		case <-s.done:
+			s.mu.Lock()
+			s.queue = append([]Event{event}, s.queue...)
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// close は pump を止め、残りのイベントをバッファに入るだけ送ってからチャンネルを閉じる
+// （受信済みのイベントは閉じた後も読み出せる）
+func (s *subscriber) close() {
+	close(s.done)
+	<-s.stopped
+	for _, event := range s.queue {
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+	close(s.ch)
+}
+
+// Attach はイベントを処理する関数を別goroutineで実行する
+// 返された関数は購読を解除し、受信済みのイベントの処理が終わるまで待つ
+func (t *Timer) Attach(handle func(events <-chan Event)) (detach func()) {
+	events, unsubscribe := t.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handle(events)
+	}()
+	return func() {
+		unsubscribe()
+		<-done
+	}
+}
@@ -1,6 +1,9 @@
 package timer
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // SessionType はポモドーロセッションの種類を表す
 type SessionType int
@@ -25,6 +28,35 @@ func (s SessionType) String() string {
 	}
 }
 
+// MarshalText はセッション種類を識別子（work, short_break, long_break）に変換する
+func (s SessionType) MarshalText() ([]byte, error) {
+	switch s {
+	case SessionWork:
+		return []byte("work"), nil
+	case SessionShortBreak:
+		return []byte("short_break"), nil
+	case SessionLongBreak:
+		return []byte("long_break"), nil
+	default:
+		return nil, fmt.Errorf("unknown session type: %d", int(s))
+	}
+}
+
+// UnmarshalText は識別子からセッション種類を復元する
+func (s *SessionType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "work":
+		*s = SessionWork
+	case "short_break":
+		*s = SessionShortBreak
+	case "long_break":
+		*s = SessionLongBreak
+	default:
+		return fmt.Errorf("unknown session type: %q", string(text))
+	}
+	return nil
+}
+
 // TimerState はタイマーの状態を表す
 type TimerState int
 
@@ -35,13 +67,59 @@ const (
 	StateCompleted
 )
 
+// String はタイマー状態の名前を返す
+func (s TimerState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateRunning:
+		return "running"
+	case StatePaused:
+		return "paused"
+	case StateCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText はタイマー状態を名前（idle, running, paused, completed）に変換する
+func (s TimerState) MarshalText() ([]byte, error) {
+	if s < StateIdle || s > StateCompleted {
+		return nil, fmt.Errorf("unknown timer state: %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText は名前からタイマー状態を復元する
+func (s *TimerState) UnmarshalText(text []byte) error {
+	for state := StateIdle; state <= StateCompleted; state++ {
+		if state.String() == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown timer state: %q", string(text))
+}
+
 // Session は1つのポモドーロセッションを表す
 type Session struct {
-	Type      SessionType
-	Duration  time.Duration
-	Remaining time.Duration
-	StartedAt time.Time
-	PausedAt  time.Time
+	Type          SessionType
+	Duration      time.Duration
+	Remaining     time.Duration
+	StartedAt     time.Time
+	PausedAt      time.Time
+	PausedTotal   time.Duration // 一時停止していた合計時間
+	Interruptions int           // 一時停止した回数
+	IdleKept      time.Duration // 離席中の時間のうち作業時間として数えた時間
+	IdleDiscarded time.Duration // 離席中の時間のうち作業時間から除いた時間
+	Task          string
+	Tags          []string
+}
+
+// Elapsed は実際に計測された（一時停止を除く）経過時間を返す
+func (s *Session) Elapsed() time.Duration {
+	return s.Duration - s.Remaining
 }
 
 // PomodoroState はポモドーロ全体の進行状態を追跡する
@@ -56,8 +134,8 @@ func (p *PomodoroState) NextSessionType(sessionsUntilLong int) SessionType {
 	if p.CurrentSession == nil || p.CurrentSession.Type != SessionWork {
 		return SessionWork
 	}
-	// 作業後は休憩を決定
-	if (p.CompletedWork+1)%sessionsUntilLong == 0 {
+	// 作業後は休憩を決定（sessionsUntilLong が0以下なら長い休憩は取らない）
+	if sessionsUntilLong > 0 && (p.CompletedWork+1)%sessionsUntilLong == 0 {
 		return SessionLongBreak
 	}
 	return SessionShortBreak
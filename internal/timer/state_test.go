@@ -33,6 +33,7 @@ func TestNextSessionType(t *testing.T) {
 		{"after work, session 4 of 4 (long break)", SessionWork, 3, 4, SessionLongBreak},
 		{"after short break", SessionShortBreak, 1, 4, SessionWork},
 		{"after long break", SessionLongBreak, 4, 4, SessionWork},
+		{"zero sessions until long break", SessionWork, 3, 0, SessionShortBreak},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -50,3 +51,63 @@ func TestNextSessionType(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionTypeTextRoundTrip(t *testing.T) {
+	for _, st := range []SessionType{SessionWork, SessionShortBreak, SessionLongBreak} {
+		text, err := st.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) error = %v", st, err)
+		}
+		var got SessionType
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) error = %v", text, err)
+		}
+		if got != st {
+			t.Errorf("round trip = %v, want %v", got, st)
+		}
+	}
+
+	var st SessionType
+	if err := st.UnmarshalText([]byte("nap")); err == nil {
+		t.Error("UnmarshalText(\"nap\") error = nil, want error")
+	}
+}
+
+func TestTimerStateString(t *testing.T) {
+	tests := []struct {
+		input    TimerState
+		expected string
+	}{
+		{StateIdle, "idle"},
+		{StateRunning, "running"},
+		{StatePaused, "paused"},
+		{StateCompleted, "completed"},
+		{TimerState(99), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.input.String(); got != tt.expected {
+			t.Errorf("TimerState(%d).String() = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTimerStateMarshalText(t *testing.T) {
+	for _, st := range []TimerState{StateIdle, StateRunning, StatePaused, StateCompleted} {
+		text, err := st.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v) error = %v", st, err)
+		}
+		var got TimerState
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) error = %v", text, err)
+		}
+		if got != st {
+			t.Errorf("round trip = %v, want %v", got, st)
+		}
+	}
+
+	var st TimerState
+	if err := st.UnmarshalText([]byte("sleeping")); err == nil {
+		t.Error("UnmarshalText(\"sleeping\") error = nil, want error")
+	}
+}
@@ -14,12 +14,17 @@ type Timer struct {
 	state  *PomodoroState
 	mu     sync.RWMutex
 	cancel context.CancelFunc
+	task   string
+	tags   []string
+
+	subscribers map[*subscriber]struct{}
 }
 
 // New は新しいTimerを作成する
 func New(cfg *config.Config) *Timer {
 	return &Timer{
 		config: cfg,
+		tags:   cfg.Tags,
 		state: &PomodoroState{
 			TimerState: StateIdle,
 		},
@@ -42,33 +47,65 @@ func (t *Timer) Start(sessionType SessionType) {
 		Duration:  duration,
 		Remaining: duration,
 		StartedAt: time.Now(),
+		Task:      t.task,
+		Tags:      t.tags,
 	}
 	t.state.TimerState = StateRunning
 
 	ctx, cancel := context.WithCancel(context.Background())
 	t.cancel = cancel
+	t.emit(EventStarted)
 
 	go t.run(ctx)
 }
 
 // Pause は現在のセッションを一時停止する
 func (t *Timer) Pause() {
+	t.pause(true)
+}
+
+// PauseAway は離席を検出したときに一時停止する（中断の回数に数えない）
+func (t *Timer) PauseAway() {
+	t.pause(false)
+}
+
+// pause は実行中のセッションを一時停止する（interruption なら中断として数える）
+func (t *Timer) pause(interruption bool) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if t.state.TimerState == StateRunning {
 		t.state.TimerState = StatePaused
 		t.state.CurrentSession.PausedAt = time.Now()
+		if interruption {
+			t.state.CurrentSession.Interruptions++
+		}
+		t.emit(EventPaused)
 	}
 }
 
 // Resume は一時停止中のセッションを再開する
 func (t *Timer) Resume() {
+	t.resume(true)
+}
+
+// ResumeAway は離席から戻ったときに再開する
+// 離席中の時間は ResolveIdle で IdleKept か IdleDiscarded に記録するので、一時停止の時間に数えない
+func (t *Timer) ResumeAway() {
+	t.resume(false)
+}
+
+// resume は一時停止中のセッションを再開する（countPaused なら一時停止の時間を PausedTotal に足す）
+func (t *Timer) resume(countPaused bool) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if t.state.TimerState == StatePaused {
 		t.state.TimerState = StateRunning
+		if countPaused {
+			t.state.CurrentSession.PausedTotal += time.Since(t.state.CurrentSession.PausedAt)
+		}
+		t.emit(EventResumed)
 	}
 }
 
@@ -81,9 +118,76 @@ func (t *Timer) Stop() {
 		t.cancel()
 		t.cancel = nil
 	}
+	switch t.state.TimerState {
+	case StatePaused:
+		t.state.CurrentSession.PausedTotal += time.Since(t.state.CurrentSession.PausedAt)
+		t.emit(EventStopped)
+	case StateRunning:
+		t.emit(EventStopped)
+	}
 	t.state.TimerState = StateIdle
 }
 
+// SetTask は作業中のタスク名を設定する
+// 以降に開始するセッションと現在のセッションに反映される
+func (t *Timer) SetTask(task string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.task = task
+	if t.state.CurrentSession != nil {
+		t.state.CurrentSession.Task = task
+	}
+}
+
+// SetTags はセッションのタグを設定する（デフォルトは設定の tags）
+// 以降に開始するセッションと現在のセッションに反映される
+func (t *Timer) SetTags(tags []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tags = tags
+	if t.state.CurrentSession != nil {
+		t.state.CurrentSession.Tags = tags
+	}
+}
+
+// Extend は実行中または一時停止中のセッションを d だけ延長する
+// 延長できるセッションがなければ false を返す
+func (t *Timer) Extend(d time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state.TimerState != StateRunning && t.state.TimerState != StatePaused {
+		return false
+	}
+	t.state.CurrentSession.Duration += d
+	t.state.CurrentSession.Remaining += d
+	t.emit(EventExtended)
+	return true
+}
+
+// ResolveIdle は離席で一時停止したセッションの離席時間 idle の扱いを決める
+// counted は一時停止するまでに経過時間として数えてしまった分
+// keep なら一時停止中も含めて idle 全体を経過時間とし、そうでなければ counted を取り消す
+func (t *Timer) ResolveIdle(idle, counted time.Duration, keep bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	session := t.state.CurrentSession
+	if session == nil {
+		return
+	}
+	if keep {
+		session.Remaining -= idle - counted
+		session.IdleKept += idle
+	} else {
+		session.Remaining += counted
+		session.IdleDiscarded += idle
+	}
+	session.Remaining = min(max(session.Remaining, 0), session.Duration)
+}
+
 // State は現在の状態のコピーを返す（スレッドセーフ）
 func (t *Timer) State() *PomodoroState {
 	t.mu.RLock()
@@ -131,6 +235,7 @@ func (t *Timer) complete() {
 		t.state.CompletedWork++
 	}
 	t.state.TimerState = StateCompleted
+	t.emit(EventCompleted)
 }
 
 // getDuration はセッション種類に応じた時間を返す
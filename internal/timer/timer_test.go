@@ -91,6 +91,150 @@ func TestResumeはタイマーを再開する(t *testing.T) {
 	tmr.Stop()
 }
 
+func TestPauseは中断回数と一時停止時間を記録する(t *testing.T) {
+	cfg := config.Default()
+	cfg.WorkDuration = 1 * time.Hour
+	tmr := New(cfg)
+
+	tmr.Start(SessionWork)
+	tmr.Pause()
+	time.Sleep(20 * time.Millisecond)
+	tmr.Resume()
+	tmr.Pause()
+	defer tmr.Stop()
+
+	session := tmr.State().CurrentSession
+	if session.Interruptions != 2 {
+		t.Errorf("Interruptions = %d, want 2", session.Interruptions)
+	}
+	if session.PausedTotal < 20*time.Millisecond {
+		t.Errorf("PausedTotal = %v, want >= 20ms", session.PausedTotal)
+	}
+}
+
+// =============================================================================
+// SetTask - タスクの設定
+// =============================================================================
+
+func TestSetTaskは現在と以降のセッションにタスクを設定する(t *testing.T) {
+	cfg := config.Default()
+	cfg.WorkDuration = 1 * time.Hour
+	tmr := New(cfg)
+
+	tmr.Start(SessionWork)
+	tmr.SetTask("docs")
+	if got := tmr.State().CurrentSession.Task; got != "docs" {
+		t.Errorf("current Task = %q, want docs", got)
+	}
+
+	tmr.Start(SessionShortBreak)
+	defer tmr.Stop()
+	if got := tmr.State().CurrentSession.Task; got != "docs" {
+		t.Errorf("next Task = %q, want docs", got)
+	}
+}
+
+func TestExtendは現在のセッションの時間と残り時間を延ばす(t *testing.T) {
+	cfg := config.Default()
+	tmr := New(cfg)
+	if tmr.Extend(5 * time.Minute) {
+		t.Error("Extend() before Start = true, want false")
+	}
+
+	tmr.Start(SessionWork)
+	defer tmr.Stop()
+	tmr.Pause()
+	if !tmr.Extend(5 * time.Minute) {
+		t.Fatal("Extend() while paused = false, want true")
+	}
+	session := tmr.State().CurrentSession
+	if session.Duration != 30*time.Minute || session.Remaining != 30*time.Minute {
+		t.Errorf("Duration = %v, Remaining = %v, want 30m", session.Duration, session.Remaining)
+	}
+}
+
+func TestExtendは延長後のセッションをイベントで知らせる(t *testing.T) {
+	cfg := config.Default()
+	tmr := New(cfg)
+	tmr.Start(SessionWork)
+	defer tmr.Stop()
+
+	events, unsubscribe := tmr.Subscribe()
+	defer unsubscribe()
+	tmr.Pause()
+	tmr.Extend(5 * time.Minute)
+
+	expected := []EventType{EventPaused, EventExtended}
+	for _, want := range expected {
+		select {
+		case event := <-events:
+			if event.Type != want {
+				t.Fatalf("event = %v, want %v", event.Type, want)
+			}
+			if want == EventExtended {
+				if event.Session.Remaining != 30*time.Minute {
+					t.Errorf("extended Remaining = %v, want 30m", event.Session.Remaining)
+				}
+				if event.TimerState != StatePaused {
+					t.Errorf("extended TimerState = %v, want StatePaused", event.TimerState)
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %v was not received", want)
+		}
+	}
+}
+
+func TestResolveIdleは離席時間を残り時間に反映する(t *testing.T) {
+	tests := []struct {
+		name          string
+		keep          bool
+		wantRemaining time.Duration
+	}{
+		// 5分経過（うち2分は離席中）した後、一時停止中にさらに8分離席した
+		{"keep は一時停止中の離席時間も経過時間にする", true, 12 * time.Minute},
+		{"discard は一時停止前の離席時間を取り消す", false, 22 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmr := New(config.Default())
+			tmr.Start(SessionWork)
+			defer tmr.Stop()
+			tmr.Pause()
+			tmr.mu.Lock()
+			tmr.state.CurrentSession.Remaining = 20 * time.Minute
+			tmr.mu.Unlock()
+
+			tmr.ResolveIdle(10*time.Minute, 2*time.Minute, tt.keep)
+
+			session := tmr.State().CurrentSession
+			if session.Remaining != tt.wantRemaining {
+				t.Errorf("Remaining = %v, want %v", session.Remaining, tt.wantRemaining)
+			}
+			if tt.keep && session.IdleKept != 10*time.Minute || !tt.keep && session.IdleDiscarded != 10*time.Minute {
+				t.Errorf("IdleKept = %v, IdleDiscarded = %v", session.IdleKept, session.IdleDiscarded)
+			}
+		})
+	}
+}
+
+func TestSetTagsは設定のデフォルトタグを上書きする(t *testing.T) {
+	cfg := config.Default()
+	cfg.Tags = []string{"default"}
+	tmr := New(cfg)
+
+	tmr.Start(SessionWork)
+	defer tmr.Stop()
+	if got := tmr.State().CurrentSession.Tags; len(got) != 1 || got[0] != "default" {
+		t.Errorf("Tags = %v, want [default]", got)
+	}
+
+	tmr.SetTags([]string{"docs", "review"})
+	if got := tmr.State().CurrentSession.Tags; len(got) != 2 || got[0] != "docs" {
+		t.Errorf("Tags = %v, want [docs review]", got)
+	}
+}
+
 // =============================================================================
 // Stop - タイマーの停止
 // =============================================================================
@@ -110,6 +254,150 @@ func TestStopはタイマーをアイドル状態に戻す(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Subscribe - イベントの購読
+// =============================================================================
+
+func TestSubscribeは操作ごとのイベントを受け取る(t *testing.T) {
+	cfg := config.Default()
+	cfg.WorkDuration = 1 * time.Hour
+	tmr := New(cfg)
+
+	events, unsubscribe := tmr.Subscribe()
+	defer unsubscribe()
+
+	tmr.Start(SessionWork)
+	tmr.Pause()
+	tmr.Resume()
+	tmr.Stop()
+
+	expected := []EventType{EventStarted, EventPaused, EventResumed, EventStopped}
+	for _, want := range expected {
+		select {
+		case event := <-events:
+			if event.Type != want {
+				t.Errorf("event = %v, want %v", event.Type, want)
+			}
+			if event.Session.Type != SessionWork {
+				t.Errorf("event session = %v, want SessionWork", event.Session.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %v was not received", want)
+		}
+	}
+}
+
+func TestSubscribeは受信が遅れても上限まではイベントを破棄しない(t *testing.T) {
+	cfg := config.Default()
+	cfg.WorkDuration = 1 * time.Hour
+	tmr := New(cfg)
+
+	events, unsubscribe := tmr.Subscribe()
+	defer unsubscribe()
+
+	// バッファを超える数のイベントを、受信する前に送る
+	tmr.Start(SessionWork)
+	for range eventBufferSize {
+		tmr.Pause()
+		tmr.Resume()
+	}
+	tmr.Stop()
+
+	want := 2 + 2*eventBufferSize
+	var last EventType
+	for i := range want {
+		select {
+		case event := <-events:
+			last = event.Type
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events, want %d", i, want)
+		}
+	}
+	if last != EventStopped {
+		t.Errorf("last event = %v, want EventStopped", last)
+	}
+}
+
+func TestSubscribeは受信しない購読者のイベントを古いものから破棄する(t *testing.T) {
+	cfg := config.Default()
+	cfg.WorkDuration = 1 * time.Hour
+	tmr := New(cfg)
+
+	events, unsubscribe := tmr.Subscribe()
+	defer unsubscribe()
+
+	// 送信待ちの上限を大きく超える数のイベントを、受信する前に送る
+	tmr.Start(SessionWork)
+	for range eventQueueLimit {
+		tmr.Pause()
+		tmr.Resume()
+	}
+	tmr.Stop()
+
+	// 最後のイベントは残り、受け取る数はバッファと送信待ちの上限（と送信中の1件）までになる
+	received := 0
+	for stopped := false; !stopped; {
+		select {
+		case event := <-events:
+			received++
+			stopped = event.Type == EventStopped
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events without EventStopped", received)
+		}
+	}
+	if limit := eventBufferSize + eventQueueLimit + 1; received > limit {
+		t.Errorf("received %d events, want at most %d", received, limit)
+	}
+}
+
+func TestUnsubscribe後はチャンネルが閉じられる(t *testing.T) {
+	tmr := New(config.Default())
+
+	events, unsubscribe := tmr.Subscribe()
+	unsubscribe()
+	unsubscribe()
+
+	tmr.Start(SessionWork)
+	defer tmr.Stop()
+
+	if _, ok := <-events; ok {
+		t.Error("channel should be closed after unsubscribe")
+	}
+}
+
+func TestAttachのdetachは受信済みイベントの処理を待つ(t *testing.T) {
+	tmr := New(config.Default())
+
+	var received []EventType
+	detach := tmr.Attach(func(events <-chan Event) {
+		for event := range events {
+			received = append(received, event.Type)
+		}
+	})
+
+	tmr.Start(SessionWork)
+	tmr.Stop()
+	detach()
+
+	if len(received) != 2 || received[0] != EventStarted || received[1] != EventStopped {
+		t.Errorf("received = %v, want [started stopped]", received)
+	}
+}
+
+func TestStopはアイドル状態ではイベントを送信しない(t *testing.T) {
+	tmr := New(config.Default())
+	events, unsubscribe := tmr.Subscribe()
+	defer unsubscribe()
+
+	tmr.Stop()
+
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %v", event.Type)
+	default:
+	}
+}
+
 // =============================================================================
 // Completion - タイマー完了
 // =============================================================================
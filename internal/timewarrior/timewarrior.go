@@ -0,0 +1,65 @@
+package timewarrior
+
+import (
+	"os/exec"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// CommandRunner は外部コマンドを実行する（テストで差し替える）
+type CommandRunner func(name string, args ...string) error
+
+// execCommand はコマンドを実行する
+func execCommand(name string, args ...string) error {
+	return exec.Command(name, args...).Run()
+}
+
+// Tags はTimewarriorのタグ（タスク名とセッションのタグ）を返す
+func Tags(task string, tags []string) []string {
+	var result []string
+	if task != "" {
+		result = append(result, task)
+	}
+	return append(result, tags...)
+}
+
+// Hook は作業セッションに合わせてTimewarriorのインターバルを開始・停止する
+type Hook struct {
+	run CommandRunner
+}
+
+// NewHook は timew コマンドを実行するHookを作成する
+func NewHook() *Hook {
+	return &Hook{run: execCommand}
+}
+
+// NewHookWithRunner はコマンドの実行方法を指定してHookを作成する
+func NewHookWithRunner(run CommandRunner) *Hook {
+	return &Hook{run: run}
+}
+
+// Run はイベントチャンネルが閉じられるまでイベントを処理する
+func (h *Hook) Run(events <-chan timer.Event, onError func(error)) {
+	for event := range events {
+		if err := h.Handle(event); err != nil {
+			onError(err)
+		}
+	}
+}
+
+// Handle は1件のイベントを処理する
+// 作業セッションの開始・再開でインターバルを開始し、一時停止・停止・完了で止める
+// 休憩セッションは記録しない
+func (h *Hook) Handle(event timer.Event) error {
+	if event.Session.Type != timer.SessionWork {
+		return nil
+	}
+	switch event.Type {
+	case timer.EventStarted, timer.EventResumed:
+		args := append([]string{"start"}, Tags(event.Session.Task, event.Session.Tags)...)
+		return h.run("timew", args...)
+	case timer.EventPaused, timer.EventStopped, timer.EventCompleted:
+		return h.run("timew", "stop")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package timewarrior
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"pomodoro-cli/internal/timer"
+)
+
+// =============================================================================
+// Hook - ライブモード
+// =============================================================================
+
+func TestHookは作業セッションに合わせてtimewを実行する(t *testing.T) {
+	var calls []string
+	hook := NewHookWithRunner(func(name string, args ...string) error {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		return nil
+	})
+
+	session := timer.Session{Type: timer.SessionWork, Task: "docs", Tags: []string{"writing"}}
+	for _, eventType := range []timer.EventType{
+		timer.EventStarted, timer.EventPaused, timer.EventResumed, timer.EventCompleted,
+	} {
+		if err := hook.Handle(timer.Event{Type: eventType, Session: session}); err != nil {
+			t.Fatalf("Handle(%v) error = %v", eventType, err)
+		}
+	}
+
+	expected := []string{
+		"timew start docs writing",
+		"timew stop",
+		"timew start docs writing",
+		"timew stop",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %q, want %q", calls, expected)
+	}
+}
+
+func TestHookは休憩セッションを記録しない(t *testing.T) {
+	hook := NewHookWithRunner(func(name string, args ...string) error {
+		t.Errorf("unexpected command: %s %v", name, args)
+		return nil
+	})
+
+	session := timer.Session{Type: timer.SessionShortBreak}
+	if err := hook.Handle(timer.Event{Type: timer.EventStarted, Session: session}); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+}
+
+func TestRunはコマンドのエラーを通知する(t *testing.T) {
+	hook := NewHookWithRunner(func(name string, args ...string) error {
+		return errors.New("timew not found")
+	})
+
+	events := make(chan timer.Event, 1)
+	events <- timer.Event{Type: timer.EventStarted, Session: timer.Session{Type: timer.SessionWork}}
+	close(events)
+
+	var got []error
+	hook.Run(events, func(err error) { got = append(got, err) })
+
+	if len(got) != 1 {
+		t.Errorf("errors = %v, want 1 error", got)
+	}
+}
+
+func TestTagsはタスク名を先頭にする(t *testing.T) {
+	if got := Tags("docs", []string{"a", "b"}); !reflect.DeepEqual(got, []string{"docs", "a", "b"}) {
+		t.Errorf("Tags() = %v", got)
+	}
+	if got := Tags("", []string{"a"}); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Tags() without task = %v", got)
+	}
+}
@@ -2,13 +2,22 @@ package ui
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"math"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"pomodoro-cli/internal/activity"
 	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/goal"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/plan"
 	"pomodoro-cli/internal/timer"
 )
 
@@ -26,31 +35,10 @@ func printLine(s string) {
 // flag.PrintDefaults()と合わせるためstderrに出力
 // ----------------------------------------------------------------------------
 
-// ShowUsage はヘルプメッセージを表示する
-func ShowUsage() {
-	fmt.Fprintln(os.Stderr, "Usage: pomodoro [options] [command]")
-	fmt.Fprintln(os.Stderr)
-	fmt.Fprintln(os.Stderr, "Commands:")
-	fmt.Fprintln(os.Stderr, "  start              Start pomodoro timer (default)")
-	fmt.Fprintln(os.Stderr, "  config             Show current configuration")
-	fmt.Fprintln(os.Stderr, "  init               Create default config file")
-	fmt.Fprintln(os.Stderr)
-	fmt.Fprintln(os.Stderr, "Options:")
-	fmt.Fprintln(os.Stderr, "  -w, --work         Work duration (e.g., -w 25m)")
-	fmt.Fprintln(os.Stderr, "  -s, --short-break  Short break duration (e.g., -s 5m)")
-	fmt.Fprintln(os.Stderr, "  -l, --long-break   Long break duration (e.g., -l 15m)")
-	fmt.Fprintln(os.Stderr, "  -n, --sessions     Sessions until long break (e.g., -n 4)")
-	fmt.Fprintln(os.Stderr, "      --no-sound     Disable notification sound")
-	fmt.Fprintln(os.Stderr, "      --no-notify    Disable system notifications")
-	fmt.Fprintln(os.Stderr, "      --no-auto-break  Disable auto-start breaks")
-	fmt.Fprintln(os.Stderr, "      --no-auto-work   Disable auto-start work")
-	fmt.Fprintln(os.Stderr, "  -v, --version      Show version")
-	fmt.Fprintln(os.Stderr, "  -h, --help         Show help")
-}
-
-// ShowUnknownCommand は不明なコマンドのエラーを表示する
-func ShowUnknownCommand(command string) {
-	fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
+// ShowUsageError はコマンドラインの誤りとヘルプの参照先を表示する
+func ShowUsageError(err error, command string) {
+	fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+	fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", command)
 }
 
 // ShowError はエラーメッセージを表示する
@@ -58,15 +46,33 @@ func ShowError(msg string) {
 	fmt.Fprintln(os.Stderr, msg)
 }
 
+// ShowConfigProblems は設定ファイルの問題を一覧表示する
+func ShowConfigProblems(problems []error) {
+	fmt.Fprintln(os.Stderr, "Invalid configuration:")
+	fromFile := false
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, "  "+p.Error())
+		var envErr *config.EnvError
+		if !errors.As(p, &envErr) {
+			fromFile = true
+		}
+	}
+	if !fromFile {
+		fmt.Fprintln(os.Stderr, "Fix or unset the POMODORO_* environment variables above.")
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Run 'pomodoro config check' after fixing the file, or 'pomodoro init' to recreate it.")
+}
+
+// ShowWarning は警告メッセージを表示する
+func ShowWarning(msg string) {
+	fmt.Fprintln(os.Stderr, "Warning: "+msg)
+}
+
 // ----------------------------------------------------------------------------
 // rawモード前に使用（stdout + \n）
 // ----------------------------------------------------------------------------
 
-// ShowVersion はバージョンを表示する
-func ShowVersion(version string) {
-	fmt.Printf("pomodoro version %s\n", version)
-}
-
 // ShowConfig は設定を表示する
 func ShowConfig(cfg *config.Config) {
 	fmt.Println()
@@ -82,13 +88,216 @@ func ShowConfig(cfg *config.Config) {
 	fmt.Println("  │  Behavior                                   │")
 	fmt.Printf("  │    Auto-start breaks:  %-20v│\n", boolToYesNo(cfg.AutoStartBreaks))
 	fmt.Printf("  │    Auto-start work:    %-20v│\n", boolToYesNo(cfg.AutoStartWork))
+	fmt.Printf("  │    Strict mode:        %-20v│\n", strictMode(cfg))
+	fmt.Printf("  │    Idle detection:     %-20v│\n", idleDetection(cfg))
+	fmt.Printf("  │    Screen lock:        %-20v│\n", screenLock(cfg))
+	fmt.Printf("  │    Guided break:       %-20v│\n", guidedBreak(cfg))
 	fmt.Println("  ├─────────────────────────────────────────────┤")
 	fmt.Println("  │  Notifications                              │")
 	fmt.Printf("  │    Sound enabled:      %-20v│\n", boolToYesNo(cfg.SoundEnabled))
 	fmt.Printf("  │    Notify enabled:     %-20v│\n", boolToYesNo(cfg.NotifyEnabled))
+	fmt.Println("  ├─────────────────────────────────────────────┤")
+	fmt.Println("  │  Goals                                      │")
+	fmt.Printf("  │    Daily goal:         %-20v│\n", goalOrOff(cfg.DailyGoal))
+	fmt.Printf("  │    Weekly goal:        %-20v│\n", goalOrOff(cfg.WeeklyGoal))
+	for _, day := range weekdays {
+		if g, ok := cfg.WeekdayGoals[strings.ToLower(day.String())]; ok {
+			fmt.Printf("  │    %-20s%-20v│\n", day.String()+":", goalOrOff(g))
+		}
+	}
+	fmt.Println("  ├─────────────────────────────────────────────┤")
+	fmt.Println("  │  Integrations                               │")
+	fmt.Printf("  │    Timewarrior:        %-20v│\n", boolToYesNo(cfg.TimewarriorLive))
+	fmt.Printf("  │    Do not disturb:     %-20v│\n", dndProvider(cfg))
+	fmt.Printf("  │    Chat status:        %-20v│\n", chatStatus(cfg))
+	fmt.Printf("  │    Team server:        %-20v│\n", teamServer(cfg))
+	if cfg.Project != "" {
+		fmt.Println("  ├─────────────────────────────────────────────┤")
+		fmt.Printf("  │  Project:              %-20v│\n", cfg.Project)
+	}
+	if len(cfg.Profiles) > 0 {
+		fmt.Println("  ├─────────────────────────────────────────────┤")
+		fmt.Println("  │  Profiles                                   │")
+		fmt.Printf("  │    Active:             %-20v│\n", profileOrNone(cfg.Profile))
+		for _, name := range cfg.ProfileNames() {
+			marker := " "
+			if name == cfg.DefaultProfile {
+				marker = "*"
+			}
+			fmt.Printf("  │   %s%-40s│\n", marker, name)
+		}
+	}
 	fmt.Println("  └─────────────────────────────────────────────┘")
 }
 
+// strictMode は strict モードの対象を表示用に変換する
+func strictMode(cfg *config.Config) string {
+	switch {
+	case !cfg.Strict:
+		return "No"
+	case cfg.StrictBreaks:
+		return "Work and breaks"
+	default:
+		return "Work sessions"
+	}
+}
+
+// idleDetection は離席の検出方法と時間を表示用に変換する（例: auto after 5m）
+func idleDetection(cfg *config.Config) string {
+	if cfg.IdleDetection == "" || cfg.IdleDetection == "off" {
+		return "Off"
+	}
+	return fmt.Sprintf("%s after %s", cfg.IdleDetection, formatHours(cfg.IdleThresholdOrDefault()))
+}
+
+// screenLock は画面ロック時の扱いを表示用に変換する（例: pause, lock breaks）
+func screenLock(cfg *config.Config) string {
+	mode := cfg.ScreenLock
+	if mode == "" || mode == "off" {
+		mode = "Off"
+	}
+	if cfg.LockOnLongBreak {
+		return mode + ", lock breaks"
+	}
+	return mode
+}
+
+// guidedBreak は短い休憩に表示するガイドを表示用に変換する
+func guidedBreak(cfg *config.Config) string {
+	if cfg.GuidedBreak == "" || cfg.GuidedBreak == "off" {
+		return "Off"
+	}
+	return cfg.GuidedBreak
+}
+
+// dndProvider は作業中に通知を止める方法を表示用に変換する
+func dndProvider(cfg *config.Config) string {
+	if cfg.DND == "" || cfg.DND == "off" {
+		return "Off"
+	}
+	return cfg.DND
+}
+
+// chatStatus は作業中にステータスを表示するチャットを表示用に変換する
+func chatStatus(cfg *config.Config) string {
+	if cfg.ChatStatus == "" || cfg.ChatStatus == "off" {
+		return "Off"
+	}
+	return cfg.ChatStatus
+}
+
+// teamServer は記録を送るチームサーバーを表示用に変換する（長い URL はホスト名だけにする）
+func teamServer(cfg *config.Config) string {
+	if cfg.TeamServer == "" {
+		return "Off"
+	}
+	if u, err := url.Parse(cfg.TeamServer); err == nil && len(cfg.TeamServer) > 20 {
+		return u.Host
+	}
+	return cfg.TeamServer
+}
+
+// profileOrNone はプロファイル名を返す（未使用なら "none"）
+func profileOrNone(name string) string {
+	if name == "" {
+		return "none"
+	}
+	return name
+}
+
+// weekdays は月曜始まりの曜日の一覧
+var weekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// goalOrOff は目標数を表示用に変換する（0 は Off）
+func goalOrOff(n int) string {
+	if n <= 0 {
+		return "Off"
+	}
+	return strconv.Itoa(n)
+}
+
+// ShowGoal は目標の進捗と連続日数を表示する
+func ShowGoal(p goal.Progress) {
+	fmt.Println()
+	for _, line := range goalLines(p) {
+		fmt.Println(line)
+	}
+}
+
+// goalLines は目標の進捗の表示を行ごとに返す（rawモードの前後で共有する）
+func goalLines(p goal.Progress) []string {
+	lines := []string{
+		"  ┌─────────────────────────────────────────────┐",
+		"  │         GOAL PROGRESS                       │",
+		"  ├─────────────────────────────────────────────┤",
+		fmt.Sprintf("  │    Today:              %-20s│", formatCount(p.Today, p.DailyGoal)),
+		fmt.Sprintf("  │    This week:          %-20s│", formatCount(p.Week, p.WeeklyGoal)),
+		fmt.Sprintf("  │    Streak:             %-20s│", formatStreak(p.Streak)),
+	}
+	if p.Strict > 0 || p.Abandoned > 0 {
+		lines = append(lines,
+			fmt.Sprintf("  │    Strict today:       %-20s│", fmt.Sprintf("%d (%d abandoned)", p.Strict, p.Abandoned)))
+	}
+	lines = append(lines, "  └─────────────────────────────────────────────┘")
+	if p.DailyGoal > 0 && p.Today >= p.DailyGoal {
+		lines = append(lines, "  Daily goal reached. Nice work!")
+	}
+	return lines
+}
+
+// ShowPlan はセッションの予定表と作業・休憩の合計時間を表示する
+func ShowPlan(p plan.Plan) {
+	fmt.Println()
+	fmt.Printf("  Plan for %s  (%s - %s)\n", p.Start().Format("Mon Jan 2"), p.Start().Format("15:04"), p.End().Format("15:04"))
+	fmt.Println()
+	fmt.Println("     #  Start  End    Session")
+	for _, slot := range p.Slots {
+		number := ""
+		if slot.Number > 0 {
+			number = strconv.Itoa(slot.Number)
+		}
+		fmt.Printf("  %4s  %s  %s  %s (%s)\n", number, slot.Start.Format("15:04"), slot.End.Format("15:04"),
+			slot.Type, formatHours(slot.Duration()))
+	}
+	fmt.Println()
+	fmt.Printf("  Focus:  %-8s (%d sessions)\n", formatHours(p.Focus()), p.Sessions())
+	fmt.Printf("  Breaks: %s\n", formatHours(p.Breaks()))
+	fmt.Printf("  Total:  %s\n", formatHours(p.End().Sub(p.Start())))
+	fmt.Println()
+}
+
+// ShowServing はAPIサーバーの起動メッセージを表示する
+func ShowServing(addr, tokenPath string) {
+	fmt.Println()
+	fmt.Printf("  Serving pomodoro API on http://%s\n", addr)
+	fmt.Printf("  Token file: %s\n", tokenPath)
+	fmt.Println("  Press Ctrl+C to stop.")
+	fmt.Println()
+}
+
+// ShowTeamServing はチームサーバーの起動メッセージを表示する
+func ShowTeamServing(addr, dataDir string, users int) {
+	fmt.Println()
+	fmt.Printf("  Serving team history on http://%s (%d users)\n", addr, users)
+	fmt.Printf("  Data directory: %s\n", dataDir)
+	if users == 0 {
+		fmt.Println("  Add a user with: pomodoro server add-user NAME")
+	}
+	fmt.Println("  Press Ctrl+C to stop.")
+	fmt.Println()
+}
+
+// ShowTeamUserAdded は登録したユーザーのトークンと設定方法を表示する
+func ShowTeamUserAdded(user, token string) {
+	fmt.Printf("Token for %s (shown only once):\n\n", user)
+	fmt.Printf("  %s\n\n", token)
+	fmt.Println("Set it on the member's machine with:")
+	fmt.Println("  pomodoro config set team_server http://HOST:7627")
+	fmt.Printf("  pomodoro config set team_token %s\n", token)
+}
+
 // boolToYesNo はboolをYes/Noに変換する
 func boolToYesNo(b bool) string {
 	if b {
@@ -97,6 +306,34 @@ func boolToYesNo(b bool) string {
 	return "No"
 }
 
+// ShowConfigExplain は各設定値とその出どころを表示する
+func ShowConfigExplain(cfg *config.Config, path string, settings []config.Setting) {
+	fmt.Println()
+	fmt.Println("  Precedence: defaults < file < project < profile < env < flags")
+	fmt.Printf("  Config file: %s\n", path)
+	if cfg.Project != "" {
+		fmt.Printf("  Project: %s\n", cfg.Project)
+	}
+	if cfg.Profile != "" {
+		fmt.Printf("  Profile: %s\n", cfg.Profile)
+	}
+	fmt.Println()
+
+	keyWidth, valueWidth := 0, 0
+	for _, s := range settings {
+		keyWidth = max(keyWidth, len(s.Key))
+		valueWidth = max(valueWidth, len(s.Value))
+	}
+	for _, s := range settings {
+		value := s.Value
+		if value == "" {
+			value = "-"
+		}
+		fmt.Printf("  %-*s  %-*s  %s\n", keyWidth, s.Key, valueWidth, value, s.Source)
+	}
+	fmt.Println()
+}
+
 // ShowConfigCreated は設定ファイル作成成功メッセージを表示する
 func ShowConfigCreated(path string) {
 	fmt.Println()
@@ -107,12 +344,62 @@ func ShowConfigCreated(path string) {
 	fmt.Println()
 }
 
+// ShowConfigCheck は設定ファイルの検証結果を表示する
+func ShowConfigCheck(path string, problems []error) {
+	if len(problems) == 0 {
+		fmt.Printf("  ✓ %s is valid\n", path)
+		return
+	}
+	fmt.Printf("  ✗ %s has %d problem(s):\n", path, len(problems))
+	for _, p := range problems {
+		fmt.Printf("    %s\n", p)
+	}
+}
+
+// ShowConfigSet は設定値を書き換えたことを表示する
+func ShowConfigSet(key, value, path string) {
+	fmt.Printf("  ✓ %s = %s (%s)\n", key, value, path)
+}
+
+// ShowConfigUnset は設定値を取り除いたことを表示する
+func ShowConfigUnset(key, path string, removed bool) {
+	if !removed {
+		fmt.Printf("  %s is not set in %s\n", key, path)
+		return
+	}
+	fmt.Printf("  ✓ %s reset to default (%s)\n", key, path)
+}
+
+// ShowConfigValue は設定値をそのまま表示する（スクリプトで使えるよう装飾しない）
+func ShowConfigValue(value string) {
+	if value != "" {
+		fmt.Println(value)
+	}
+}
+
+// ShowConfigPath は設定ファイルのパスを表示する
+func ShowConfigPath(path string) {
+	fmt.Println(path)
+}
+
+// ShowConfigMissing は設定ファイルがないことを表示する
+func ShowConfigMissing(path string) {
+	fmt.Printf("  No config file at %s; using defaults.\n", path)
+	fmt.Println("  Run 'pomodoro init' to create one.")
+}
+
 // ShowInitHeader はinit開始時のヘッダーを表示する
-func ShowInitHeader() {
+// profile が空でなければ編集するプロファイル名を表示する
+func ShowInitHeader(profile string) {
 	fmt.Println()
 	fmt.Println("  ╔═════════════════════════════════════════════╗")
 	fmt.Println("  ║       POMODORO CONFIGURATION SETUP          ║")
 	fmt.Println("  ╠═════════════════════════════════════════════╣")
+	if profile != "" {
+		fmt.Printf("  ║  %-43s║\n", "Profile: "+profile)
+		fmt.Println("  ║  Only values that differ from the base      ║")
+		fmt.Println("  ║  configuration are saved to the profile.    ║")
+	}
 	fmt.Println("  ║  Press Enter to keep current values.        ║")
 	fmt.Println("  ╚═════════════════════════════════════════════╝")
 	fmt.Println()
@@ -166,6 +453,26 @@ func PromptInt(label string, currentVal int, example int) int {
 	return n
 }
 
+// Confirm は [Y/n] 形式で確認する（Enter のみなら defaultYes）
+func Confirm(label string, defaultYes bool) bool {
+	choices := "y/N"
+	if defaultYes {
+		choices = "Y/n"
+	}
+	fmt.Printf("%s [%s]: ", label, choices)
+	if !scanner.Scan() {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+	case "":
+		return defaultYes
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
 // PromptBool はyes/noを入力させる
 func PromptBool(label string, currentVal bool, example bool) bool {
 	currentStr := "n"
@@ -190,7 +497,7 @@ func PromptBool(label string, currentVal bool, example bool) bool {
 // ----------------------------------------------------------------------------
 
 // RenderTimer はタイマーの状態を表示する
-func RenderTimer(session *timer.Session, state timer.TimerState) {
+func RenderTimer(session *timer.Session, state timer.TimerState, goalProgress goal.Progress) {
 	if session == nil {
 		return
 	}
@@ -207,11 +514,16 @@ func RenderTimer(session *timer.Session, state timer.TimerState) {
 		stateStr = "⏸"
 	}
 
-	fmt.Printf("\r%s %s [%s] %02d:%02d", stateStr, session.Type.String(), bar, minutes, seconds)
+	goalStr := ""
+	if goalProgress.DailyGoal > 0 {
+		goalStr = fmt.Sprintf("  %d/%d today", goalProgress.Today, goalProgress.DailyGoal)
+	}
+
+	fmt.Printf("\r%s %s [%s] %02d:%02d%s", stateStr, session.Type.String(), bar, minutes, seconds, goalStr)
 }
 
 // ShowWelcome はウェルカムメッセージを表示する
-func ShowWelcome(work, shortBreak, longBreak time.Duration) {
+func ShowWelcome(work, shortBreak, longBreak time.Duration, progress goal.Progress, bindings keys.Bindings) {
 	printLine("")
 	printLine("  ╔══════════════════════════════════════════════════════════════════════════╗")
 	printLine("  ║                                                                          ║")
@@ -226,22 +538,137 @@ func ShowWelcome(work, shortBreak, longBreak time.Duration) {
 	printLine("")
 	printLine("  ┌────────────────────────────────────────────────────────────────────────┐")
 	printLine(fmt.Sprintf("  │  Work: %-10v   Short Break: %-10v   Long Break: %-10v │", work, shortBreak, longBreak))
+	if progress.DailyGoal > 0 || progress.WeeklyGoal > 0 {
+		goalLine := fmt.Sprintf("Today: %-8s   This week: %-8s   Streak: %s",
+			formatCount(progress.Today, progress.DailyGoal),
+			formatCount(progress.Week, progress.WeeklyGoal),
+			formatStreak(progress.Streak))
+		printLine(fmt.Sprintf("  │  %-70s│", goalLine))
+	}
 	printLine("  └────────────────────────────────────────────────────────────────────────┘")
 	printLine("")
 	printLine("  ┌─ Keyboard Shortcuts ───────────────────────────────────────────────────┐")
-	printLine("  │  [Space] Pause/Resume    [s] Skip    [r] Reset    [q] Quit             │")
+	printLine(fmt.Sprintf("  │  %-70s│", formatShortcuts(bindings)))
+	printLine("  └────────────────────────────────────────────────────────────────────────┘")
+	printLine("")
+}
+
+// welcomeActions はウェルカム画面のショートカットに表示するアクション（残りは ? のヘルプで見られる）
+var welcomeActions = []keys.Action{keys.ActionPause, keys.ActionSkip, keys.ActionReset, keys.ActionQuit, keys.ActionHelp}
+
+// formatShortcuts はアクションごとに最初に割り当てられたキーを "[Space] Pause/Resume" の形で並べる
+// 割り当てのないアクションは表示しない
+func formatShortcuts(bindings keys.Bindings) string {
+	var items []string
+	for _, info := range keys.Actions {
+		if !slices.Contains(welcomeActions, info.Action) {
+			continue
+		}
+		if bound := bindings.KeysFor(info.Action); len(bound) > 0 {
+			items = append(items, fmt.Sprintf("[%s] %s", bound[0].Display(), info.Label))
+		}
+	}
+	return strings.Join(items, "   ")
+}
+
+// paletteCommands はアクション以外にコマンドパレットで使えるコマンド
+var paletteCommands = []struct{ usage, description string }{
+	{":task NAME", "Set the task of the session"},
+	{":extend DURATION", "Add time to the current session"},
+}
+
+// ShowHelp はアクションと割り当てられたキー、コマンドパレットのコマンドを一覧表示する
+func ShowHelp(bindings keys.Bindings) {
+	row := func(keyNames, command, description string) {
+		printLine(fmt.Sprintf("  │  %-14s %-18s %-36s│", keyNames, command, description))
+	}
+	printLine("")
+	printLine("  ┌─ Help ─────────────────────────────────────────────────────────────────┐")
+	row("Key", "Command", "Description")
+	for _, info := range keys.Actions {
+		var names []string
+		for _, k := range bindings.KeysFor(info.Action) {
+			names = append(names, k.Display())
+		}
+		keyNames := strings.Join(names, ", ")
+		if keyNames == "" {
+			keyNames = "-"
+		}
+		command := ":" + string(info.Action)
+		if info.Action == keys.ActionCommand {
+			command = ""
+		}
+		row(keyNames, command, info.Description)
+	}
+	for _, c := range paletteCommands {
+		row("", c.usage, c.description)
+	}
 	printLine("  └────────────────────────────────────────────────────────────────────────┘")
+}
+
+// ShowGoalInSession はセッション中に目標の進捗を表示する
+func ShowGoalInSession(p goal.Progress) {
+	printLine("")
+	for _, line := range goalLines(p) {
+		printLine(line)
+	}
+}
+
+// RenderPalette はタイマーの行をコマンドパレットなどの入力欄に置き換えて表示する
+func RenderPalette(prompt, input string) {
+	fmt.Printf("\r\x1b[K%s%s", prompt, input)
+}
+
+// ClosePalette は入力欄を消す
+func ClosePalette() {
+	fmt.Print("\r\x1b[K")
+}
+
+// ShowTaskSet はタスクを変更したことを表示する
+func ShowTaskSet(task string) {
+	printLine("")
+	if task == "" {
+		printLine("  >> Task cleared")
+		return
+	}
+	printLine(fmt.Sprintf("  >> Task: %s", task))
+}
+
+// ShowExtended はセッションを延長したことを表示する
+func ShowExtended(d, remaining time.Duration) {
 	printLine("")
+	printLine(fmt.Sprintf("  >> Extended by %s (%s left)", formatHours(d), FormatDuration(remaining)))
+}
+
+// ShowCommandError はコマンドパレットに入力されたコマンドの誤りを表示する
+func ShowCommandError(err error) {
+	printLine("")
+	printLine(fmt.Sprintf("  !! %v", err))
 }
 
 // ShowSessionComplete はセッション完了メッセージを表示する
-func ShowSessionComplete(sessionType timer.SessionType) {
+// 作業セッションでは休憩の過ごし方の提案（空なら決まり文句）を添え、目標に達した場合は達成を祝う
+func ShowSessionComplete(sessionType timer.SessionType, progress goal.Progress, suggestion string) {
 	printLine("")
 	switch sessionType {
 	case timer.SessionWork:
 		printLine("  ╔════════════════════════════════════════════╗")
 		printLine("  ║  ✓ Work session complete!                  ║")
-		printLine("  ║    Time for a well-deserved break.         ║")
+		if suggestion == "" {
+			printLine("  ║    Time for a well-deserved break.         ║")
+		} else {
+			for _, line := range wrapWords("Break idea: "+suggestion, 40) {
+				printLine(fmt.Sprintf("  ║    %-40s║", line))
+			}
+		}
+		if progress.DailyGoalReached() {
+			msg := fmt.Sprintf("★ Daily goal reached! (%d/%d)", progress.Today, progress.DailyGoal)
+			printLine(fmt.Sprintf("  ║  %-42s║", msg))
+		}
+		if progress.WeeklyGoalReached() {
+			msg := fmt.Sprintf("★ Weekly goal reached! (%d/%d)", progress.Week, progress.WeeklyGoal)
+			printLine(fmt.Sprintf("  ║  %-42s║", msg))
+		}
 		printLine("  ╚════════════════════════════════════════════╝")
 	case timer.SessionShortBreak, timer.SessionLongBreak:
 		printLine("  ╔════════════════════════════════════════════╗")
@@ -251,6 +678,21 @@ func ShowSessionComplete(sessionType timer.SessionType) {
 	}
 }
 
+// RenderGuide はタイマーの行の代わりにガイドの手順と残り秒数を表示する
+// 手順の経過は1秒ごとに ● で表す
+func RenderGuide(g activity.Guide, step activity.Step, round int, left time.Duration) {
+	seconds := int(step.Duration.Seconds())
+	rest := int(math.Ceil(left.Seconds()))
+	dots := strings.Repeat("●", seconds-rest+1) + strings.Repeat("○", max(rest-1, 0))
+	fmt.Printf("\r\x1b[K  ~ %s  %-32s %s %2ds  (%d/%d)", g.Title, step.Text, dots, rest, round, g.Rounds)
+}
+
+// ShowGuideDone はガイドが終わったことを表示する
+func ShowGuideDone(g activity.Guide) {
+	fmt.Print("\r\x1b[K")
+	printLine(fmt.Sprintf("  ✓ %s done. Enjoy the rest of your break.", g.Title))
+}
+
 // ShowStartSession はセッション開始メッセージを表示する
 func ShowStartSession(sessionType timer.SessionType) {
 	printLine("")
@@ -281,6 +723,106 @@ func ShowReset() {
 	printLine("  <> Reset")
 }
 
+// ShowStrictRefused は strict モードでアクションを実行しなかった理由を表示する
+func ShowStrictRefused(reason string) {
+	printLine("")
+	printLine("  ✗ Strict mode: " + reason)
+}
+
+// ShowAbandoned は strict モードで作業セッションを放棄として記録したことを表示する
+func ShowAbandoned() {
+	printLine("")
+	printLine("  ✗ Work session recorded as abandoned")
+}
+
+// ShowAway は離席を検出して一時停止したことを表示する
+func ShowAway(idle time.Duration) {
+	printLine("")
+	printLine(fmt.Sprintf("  || Paused: no activity for %s", FormatDuration(idle)))
+}
+
+// ShowIdleResolved は離席中の時間を作業時間に含めたか除いたかを表示する
+func ShowIdleResolved(idle time.Duration, kept bool) {
+	printLine("")
+	if kept {
+		printLine(fmt.Sprintf("  >> Resumed. %s away counted as focus time", FormatDuration(idle)))
+		return
+	}
+	printLine(fmt.Sprintf("  >> Resumed. %s away discarded", FormatDuration(idle)))
+}
+
+// ShowScreenLocked は画面のロックで作業セッションを止めたことを表示する
+// interrupted なら中断として記録し、ロック解除後に最初からやり直す
+func ShowScreenLocked(interrupted bool) {
+	printLine("")
+	if interrupted {
+		printLine("  ✗ Screen locked: pomodoro recorded as interrupted, starting over on unlock")
+		return
+	}
+	printLine("  || Paused: screen locked")
+}
+
+// ShowDNDRestored は前回の実行で止めたままだった通知を元に戻したことを表示する
+func ShowDNDRestored() {
+	printLine("  Restored notifications left silenced by a previous run.")
+}
+
+// ShowHosting はチームのホストとして待ち受けているアドレスを表示する
+func ShowHosting(addr string) {
+	printLine("")
+	printLine(fmt.Sprintf("  Hosting a team pomodoro on %s (pomodoro join ADDR)", addr))
+}
+
+// ShowMemberJoined は参加者が接続したことを表示する
+func ShowMemberJoined(name string, members int) {
+	printLine("")
+	printLine(fmt.Sprintf("  + %s joined (%d connected)", name, members))
+}
+
+// ShowMemberLeft は参加者の接続が切れたことを表示する
+func ShowMemberLeft(name string, members int) {
+	printLine("")
+	printLine(fmt.Sprintf("  - %s left (%d connected)", name, members))
+}
+
+// ShowProposalDecided は参加者の提案をホストが承認したか却下したかを表示する
+func ShowProposalDecided(name, action string, approved bool) {
+	printLine("")
+	if approved {
+		printLine(fmt.Sprintf("  ✓ Approved %s's proposal to %s", name, action))
+		return
+	}
+	printLine(fmt.Sprintf("  ✗ Declined %s's proposal to %s", name, action))
+}
+
+// ShowProposalSent は参加者がホストに提案を送ったことを表示する
+func ShowProposalSent(action string) {
+	printLine("")
+	printLine(fmt.Sprintf("  ?? Proposed to %s, waiting for the host", action))
+}
+
+// ShowConnected はホストに接続したことを表示する
+func ShowConnected(addr string) {
+	printLine("")
+	printLine(fmt.Sprintf("  Connected to %s", addr))
+}
+
+// ShowDisconnected はホストとの接続が切れて再接続を待っていることを表示する
+func ShowDisconnected(err error) {
+	printLine("")
+	if err == nil {
+		printLine("  !! Connection to the host lost, reconnecting...")
+		return
+	}
+	printLine(fmt.Sprintf("  !! Connection to the host lost (%v), reconnecting...", err))
+}
+
+// ShowIdleUnavailable は離席を検出できないため検出をやめたことを表示する
+func ShowIdleUnavailable(provider string, err error) {
+	printLine("")
+	printLine(fmt.Sprintf("  !! Idle detection (%s) turned off: %v", provider, err))
+}
+
 // ShowExit は終了メッセージを表示する
 func ShowExit() {
 	printLine("")
@@ -294,6 +836,27 @@ func ShowExit() {
 // ヘルパー関数
 // ----------------------------------------------------------------------------
 
+// wrapWords は文章を width 文字以内の行に単語の区切りで折り返す（長すぎる単語はそのまま）
+func wrapWords(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 // progressBar はプログレスバーを生成する
 func progressBar(progress float64, width int) string {
 	filled := int(progress * float64(width))
@@ -301,6 +864,34 @@ func progressBar(progress float64, width int) string {
 	return strings.Repeat("█", filled) + strings.Repeat("░", empty)
 }
 
+// formatCount は完了数を目標付きで表示用に変換する（例: 3/8）
+func formatCount(n, goal int) string {
+	if goal <= 0 {
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf("%d/%d", n, goal)
+}
+
+// formatStreak は連続日数を表示用に変換する
+func formatStreak(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// formatHours は時間を "45m" や "3h20m" の形に変換する
+func formatHours(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	if m%60 == 0 {
+		return fmt.Sprintf("%dh", m/60)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
+
 // FormatDuration は時間を人間が読みやすい形式に変換する
 func FormatDuration(d time.Duration) string {
 	m := int(d.Minutes())
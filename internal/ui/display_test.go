@@ -3,12 +3,16 @@ package ui
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"pomodoro-cli/internal/config"
+	"pomodoro-cli/internal/goal"
+	"pomodoro-cli/internal/keys"
+	"pomodoro-cli/internal/plan"
 	"pomodoro-cli/internal/timer"
 )
 
@@ -74,13 +78,83 @@ func TestFormatDurationTruncatesSeconds(t *testing.T) {
 
 func TestShowInitHeaderDisplaysSetupInstructions(t *testing.T) {
 	output := captureStdout(t, func() {
-		ShowInitHeader()
+		ShowInitHeader("")
 	})
 
 	assertContains(t, output, "CONFIGURATION SETUP")
 	assertContains(t, output, "Press Enter")
 }
 
+func TestShowConfigCheckListsProblems(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowConfigCheck("/test/config.json", []error{errors.New("config.json:3:3: work_duration: must be positive")})
+	})
+
+	assertContains(t, output, "has 1 problem(s)")
+	assertContains(t, output, "work_duration: must be positive")
+}
+
+func TestShowConfigCheckReportsValidFile(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowConfigCheck("/test/config.json", nil)
+	})
+
+	assertContains(t, output, "/test/config.json is valid")
+}
+
+func TestShowConfigUnsetReportsRemovedKey(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowConfigUnset("work_duration", "/test/config.toml", true)
+	})
+
+	assertContains(t, output, "work_duration reset to default (/test/config.toml)")
+}
+
+func TestShowConfigUnsetReportsMissingKey(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowConfigUnset("work_duration", "/test/config.toml", false)
+	})
+
+	assertContains(t, output, "work_duration is not set in /test/config.toml")
+}
+
+func TestShowConfigValuePrintsValueWithoutDecoration(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowConfigValue("25m")
+		ShowConfigValue("")
+	})
+
+	if output != "25m\n" {
+		t.Errorf("ShowConfigValue() output = %q, want %q", output, "25m\n")
+	}
+}
+
+func TestShowConfigExplainDisplaysValuesAndSources(t *testing.T) {
+	output := captureStdout(t, func() {
+		cfg := config.Default()
+		cfg.Project, cfg.Profile = "website", "deep"
+		ShowConfigExplain(cfg, "/test/config.toml", []config.Setting{
+			{Key: "work_duration", Value: "50m", Source: config.Source{Kind: config.SourceProfile, Name: "deep"}},
+			{Key: "tags", Source: config.Source{}},
+		})
+	})
+
+	assertContains(t, output, "defaults < file < project < profile < env < flags")
+	assertContains(t, output, "Config file: /test/config.toml")
+	assertContains(t, output, "Project: website")
+	assertContains(t, output, "Profile: deep")
+	assertContains(t, output, "work_duration  50m  profile deep")
+	assertContains(t, output, "tags           -    default")
+}
+
+func TestShowInitHeaderDisplaysProfileName(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowInitHeader("deep")
+	})
+
+	assertContains(t, output, "Profile: deep")
+}
+
 func TestShowConfigCreatedDisplaysFilePath(t *testing.T) {
 	output := captureStdout(t, func() {
 		ShowConfigCreated("/test/path/config.json")
@@ -91,56 +165,41 @@ func TestShowConfigCreatedDisplaysFilePath(t *testing.T) {
 }
 
 // =============================================================================
-// Help and Usage - ヘルプとUsageの表示
+// Errors - エラーの表示
 // =============================================================================
 
-func TestShowUsageDisplaysAllCommandsAndOptions(t *testing.T) {
+func TestShowUsageErrorDisplaysErrorAndHelpHint(t *testing.T) {
 	output := captureStderr(t, func() {
-		ShowUsage()
+		ShowUsageError(errors.New(`unknown command "unknown" for "pomodoro"`), "pomodoro")
 	})
 
-	expectedStrings := []string{
-		"Usage: pomodoro",
-		"Commands:",
-		"start", "config", "init",
-		"Options:",
-		"-w, --work",
-		"-s, --short-break",
-		"-l, --long-break",
-		"-n, --sessions",
-		"--no-sound",
-		"--no-notify",
-		"-v, --version",
-		"-h, --help",
-	}
-
-	for _, expected := range expectedStrings {
-		assertContains(t, output, expected)
-	}
+	assertContains(t, output, `Error: unknown command "unknown" for "pomodoro"`)
+	assertContains(t, output, "Run 'pomodoro --help' for usage.")
 }
 
-func TestShowUnknownCommandDisplaysErrorMessage(t *testing.T) {
+func TestShowErrorDisplaysMessage(t *testing.T) {
 	output := captureStderr(t, func() {
-		ShowUnknownCommand("unknown")
+		ShowError("test error message")
 	})
 
-	assertContains(t, output, "Unknown command: unknown")
+	assertContains(t, output, "test error message")
 }
 
-func TestShowErrorDisplaysMessage(t *testing.T) {
+func TestShowWarningDisplaysPrefixedMessage(t *testing.T) {
 	output := captureStderr(t, func() {
-		ShowError("test error message")
+		ShowWarning("unknown config key")
 	})
 
-	assertContains(t, output, "test error message")
+	assertContains(t, output, "Warning: unknown config key")
 }
 
-func TestShowVersionDisplaysVersionString(t *testing.T) {
+func TestShowServingDisplaysAddressAndTokenPath(t *testing.T) {
 	output := captureStdout(t, func() {
-		ShowVersion("1.0.0")
+		ShowServing("127.0.0.1:7625", "/tmp/pomodoro/token")
 	})
 
-	assertContains(t, output, "pomodoro version 1.0.0")
+	assertContains(t, output, "http://127.0.0.1:7625")
+	assertContains(t, output, "/tmp/pomodoro/token")
 }
 
 // =============================================================================
@@ -173,6 +232,9 @@ func TestShowConfigDisplaysAllSettings(t *testing.T) {
 		"Auto-start work:",
 		"Sound enabled:",
 		"Notify enabled:",
+		"Daily goal:",
+		"Weekly goal:",
+		"Timewarrior:",
 		"Yes",
 		"No",
 	}
@@ -182,12 +244,38 @@ func TestShowConfigDisplaysAllSettings(t *testing.T) {
 	}
 }
 
+func TestShowConfigDisplaysProject(t *testing.T) {
+	cfg := config.Default()
+	cfg.Project = "website"
+	output := captureStdout(t, func() {
+		ShowConfig(cfg)
+	})
+
+	assertContains(t, output, "Project:")
+	assertContains(t, output, "website")
+}
+
+func TestShowConfigDisplaysProfiles(t *testing.T) {
+	cfg := config.Default()
+	cfg.DefaultProfile = "deep"
+	cfg.Profiles = map[string]config.Profile{"deep": {}, "study": {}}
+	cfg.Profile = "study"
+
+	output := captureStdout(t, func() {
+		ShowConfig(cfg)
+	})
+
+	assertContains(t, output, "Active:             study")
+	assertContains(t, output, "*deep")
+	assertContains(t, output, " study")
+}
+
 // =============================================================================
 // Timer Display - タイマーの表示
 // =============================================================================
 
 func TestRenderTimerDoesNotPanicWithNilSession(t *testing.T) {
-	RenderTimer(nil, timer.StateRunning)
+	RenderTimer(nil, timer.StateRunning, goal.Progress{})
 }
 
 func TestRenderTimerDisplaysSessionTypeAndTime(t *testing.T) {
@@ -198,7 +286,7 @@ func TestRenderTimerDisplaysSessionTypeAndTime(t *testing.T) {
 	}
 
 	output := captureStdout(t, func() {
-		RenderTimer(session, timer.StateRunning)
+		RenderTimer(session, timer.StateRunning, goal.Progress{})
 	})
 
 	assertContains(t, output, "Work")
@@ -213,19 +301,33 @@ func TestRenderTimerShowsPauseIconWhenPaused(t *testing.T) {
 	}
 
 	output := captureStdout(t, func() {
-		RenderTimer(session, timer.StatePaused)
+		RenderTimer(session, timer.StatePaused, goal.Progress{})
 	})
 
 	assertContains(t, output, "⏸")
 }
 
+func TestRenderTimerShowsDailyGoalProgress(t *testing.T) {
+	session := &timer.Session{
+		Type:      timer.SessionWork,
+		Duration:  25 * time.Minute,
+		Remaining: 24 * time.Minute,
+	}
+
+	output := captureStdout(t, func() {
+		RenderTimer(session, timer.StateRunning, goal.Progress{Today: 3, DailyGoal: 8})
+	})
+
+	assertContains(t, output, "3/8 today")
+}
+
 // =============================================================================
 // Welcome Screen - ウェルカム画面
 // =============================================================================
 
 func TestShowWelcomeDisplaysSettingsAndShortcuts(t *testing.T) {
 	output := captureStdout(t, func() {
-		ShowWelcome(25*time.Minute, 5*time.Minute, 15*time.Minute)
+		ShowWelcome(25*time.Minute, 5*time.Minute, 15*time.Minute, goal.Progress{}, keys.DefaultBindings())
 	})
 
 	expectedStrings := []string{
@@ -243,24 +345,154 @@ func TestShowWelcomeDisplaysSettingsAndShortcuts(t *testing.T) {
 	}
 }
 
+func TestShowWelcomeDisplaysCustomBindings(t *testing.T) {
+	bindings, err := keys.NewBindings(map[string]string{"p": "pause", "space": "none", "ctrl+s": "skip", "s": "none", "S": "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := captureStdout(t, func() {
+		ShowWelcome(25*time.Minute, 5*time.Minute, 15*time.Minute, goal.Progress{}, bindings)
+	})
+
+	assertContains(t, output, "[p] Pause/Resume")
+	assertContains(t, output, "[Ctrl+S] Skip")
+	if strings.Contains(output, "[Space]") {
+		t.Errorf("unbound key is shown:\n%s", output)
+	}
+}
+
+func TestShowWelcomeDisplaysGoalProgress(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowWelcome(25*time.Minute, 5*time.Minute, 15*time.Minute, goal.Progress{
+			Today: 3, DailyGoal: 8, Week: 12, WeeklyGoal: 40, Streak: 5,
+		}, keys.DefaultBindings())
+	})
+
+	assertContains(t, output, "Today: 3/8")
+	assertContains(t, output, "This week: 12/40")
+	assertContains(t, output, "Streak: 5 days")
+}
+
+// =============================================================================
+// Help and Command Palette - ヘルプとコマンドパレット
+// =============================================================================
+
+func TestShowHelpListsActionsWithBindingsAndCommands(t *testing.T) {
+	bindings, err := keys.NewBindings(map[string]string{"g": "none", "ctrl+s": "skip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := captureStdout(t, func() {
+		ShowHelp(bindings)
+	})
+
+	for _, expected := range []string{
+		"Help",
+		"Space",
+		":pause",
+		"s, S, Ctrl+S",
+		"q, Q, Ctrl+C",
+		"Show goal progress",
+		":task NAME",
+		":extend DURATION",
+	} {
+		assertContains(t, output, expected)
+	}
+	// 割り当てのないアクションは "-"
+	for line := range strings.SplitSeq(output, "\n") {
+		if strings.Contains(line, ":goal") && !strings.Contains(line, "│  -") {
+			t.Errorf("unbound goal line = %q, want key column -", line)
+		}
+	}
+}
+
+func TestShowExtendedDisplaysExtensionAndRemaining(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowExtended(5*time.Minute, 12*time.Minute)
+	})
+
+	assertContains(t, output, "Extended by 5m (12m left)")
+}
+
+// =============================================================================
+// Goal Display - 目標の表示
+// =============================================================================
+
+func TestShowGoalDisplaysProgressAndStreak(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowGoal(goal.Progress{Today: 2, DailyGoal: 8, Week: 2, Streak: 1})
+	})
+
+	assertContains(t, output, "GOAL PROGRESS")
+	assertContains(t, output, "2/8")
+	assertContains(t, output, "1 day")
+}
+
+func TestShowPlanDisplaysTimetableAndTotals(t *testing.T) {
+	start := time.Date(2026, 1, 5, 13, 0, 0, 0, time.Local)
+	p := plan.Plan{Slots: []plan.Slot{
+		{Type: timer.SessionWork, Start: start, End: start.Add(25 * time.Minute), Number: 1},
+		{Type: timer.SessionLongBreak, Start: start.Add(25 * time.Minute), End: start.Add(40 * time.Minute)},
+		{Type: timer.SessionWork, Start: start.Add(40 * time.Minute), End: start.Add(65 * time.Minute), Number: 2},
+	}}
+
+	output := captureStdout(t, func() {
+		ShowPlan(p)
+	})
+
+	assertContains(t, output, "Plan for Mon Jan 5  (13:00 - 14:05)")
+	assertContains(t, output, "   1  13:00  13:25  Work (25m)")
+	assertContains(t, output, "      13:25  13:40  Long Break (15m)")
+	assertContains(t, output, "Focus:  50m      (2 sessions)")
+	assertContains(t, output, "Breaks: 15m")
+	assertContains(t, output, "Total:  1h05m")
+}
+
 // =============================================================================
 // Session Messages - セッションメッセージ
 // =============================================================================
 
 func TestShowSessionCompleteDisplaysWorkComplete(t *testing.T) {
 	output := captureStdout(t, func() {
-		ShowSessionComplete(timer.SessionWork)
+		ShowSessionComplete(timer.SessionWork, goal.Progress{}, "")
 	})
 	assertContains(t, output, "Work session complete")
 }
 
+func TestShowSessionCompleteSuggestsBreakActivity(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowSessionComplete(timer.SessionWork, goal.Progress{}, "20-20-20: look at something 20 feet (6 m) away for 20 seconds")
+	})
+	assertContains(t, output, "║    Break idea: 20-20-20: look at something ║")
+	assertContains(t, output, "║    20 feet (6 m) away for 20 seconds       ║")
+	if strings.Contains(output, "well-deserved") {
+		t.Error("the suggestion should replace the default message")
+	}
+}
+
 func TestShowSessionCompleteDisplaysBreakOver(t *testing.T) {
 	output := captureStdout(t, func() {
-		ShowSessionComplete(timer.SessionShortBreak)
+		ShowSessionComplete(timer.SessionShortBreak, goal.Progress{}, "")
 	})
 	assertContains(t, output, "Break over")
 }
 
+func TestShowSessionCompleteCelebratesDailyGoal(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowSessionComplete(timer.SessionWork, goal.Progress{Today: 8, DailyGoal: 8}, "")
+	})
+	assertContains(t, output, "Daily goal reached! (8/8)")
+}
+
+func TestShowSessionCompleteDoesNotCelebrateBeyondGoal(t *testing.T) {
+	output := captureStdout(t, func() {
+		ShowSessionComplete(timer.SessionWork, goal.Progress{Today: 9, DailyGoal: 8}, "")
+	})
+	if strings.Contains(output, "goal reached") {
+		t.Error("goal should only be celebrated when it is first reached")
+	}
+}
+
 func TestShowStartSessionDisplaysSessionType(t *testing.T) {
 	output := captureStdout(t, func() {
 		ShowStartSession(timer.SessionWork)
@@ -388,6 +620,31 @@ func TestPromptIntReturnsCurrentValueOnInvalidInput(t *testing.T) {
 	}
 }
 
+func TestConfirmReturnsDefaultOnEmptyInput(t *testing.T) {
+	for _, defaultYes := range []bool{true, false} {
+		var got bool
+		withInput("\n", func() {
+			got = Confirm("Test", defaultYes)
+		})
+		if got != defaultYes {
+			t.Errorf("Confirm(default %v) = %v", defaultYes, got)
+		}
+	}
+}
+
+func TestConfirmParsesAnswer(t *testing.T) {
+	tests := map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "later\n": false, "": false}
+	for input, want := range tests {
+		var got bool
+		withInput(input, func() {
+			got = Confirm("Test", true)
+		})
+		if got != want {
+			t.Errorf("Confirm(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
 func TestPromptBoolReturnsCurrentValueOnEmptyInput(t *testing.T) {
 	var got bool
 	withInput("\n", func() {
@@ -5,24 +5,14 @@ import (
 	"os"
 
 	"golang.org/x/term"
-)
-
-// KeyEvent はキーボードイベントを表す
-type KeyEvent int
 
-const (
-	KeyNone KeyEvent = iota
-	KeySpace
-	KeyQ
-	KeyS
-	KeyR
-	KeyUnknown
+	"pomodoro-cli/internal/keys"
 )
 
 // 入力用のグローバル状態（パッケージ内で管理）
 var (
 	oldTermState *term.State
-	keyChan      chan KeyEvent
+	keyChan      chan keys.Key
 )
 
 // InitInput はターミナルをrawモードに設定し、キー入力の監視を開始する
@@ -32,7 +22,7 @@ func InitInput() error {
 		return err
 	}
 	oldTermState = oldState
-	keyChan = make(chan KeyEvent, 1)
+	keyChan = make(chan keys.Key, 8)
 
 	go readLoop()
 	return nil
@@ -47,47 +37,40 @@ func RestoreInput() {
 	}
 }
 
-// ReadKey はキー入力を読み取る（ノンブロッキング）
-func ReadKey() KeyEvent {
+// ReadKey はキー入力を読み取る（ノンブロッキング、入力がなければ keys.Unknown）
+func ReadKey() keys.Key {
 	select {
 	case key := <-keyChan:
 		return key
 	default:
-		return KeyNone
+		return keys.Unknown
 	}
 }
 
 // KeyChan はキーイベントのチャンネルを返す（select文で使用）
-func KeyChan() <-chan KeyEvent {
+func KeyChan() <-chan keys.Key {
 	return keyChan
 }
 
 // readLoop はバックグラウンドでキー入力を読み取る
+// エスケープシーケンスやマルチバイト文字は Decoder が1つのキーにまとめる
 func readLoop() {
-	buf := make([]byte, 1)
+	var decoder keys.Decoder
+	buf := make([]byte, 256)
 	for {
 		n, err := os.Stdin.Read(buf)
 		if err != nil || n == 0 {
 			continue
 		}
 
-		var key KeyEvent
-		switch buf[0] {
-		case ' ':
-			key = KeySpace
-		case 'q', 'Q', 3: // 3 = Ctrl+C
-			key = KeyQ
-		case 's', 'S':
-			key = KeyS
-		case 'r', 'R':
-			key = KeyR
-		default:
-			key = KeyUnknown
-		}
-
-		select {
-		case keyChan <- key:
-		default:
+		for _, key := range decoder.Feed(buf[:n]) {
+			if key == keys.Unknown {
+				continue
+			}
+			select {
+			case keyChan <- key:
+			default:
+			}
 		}
 	}
 }
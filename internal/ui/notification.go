@@ -3,20 +3,27 @@ package ui
 import (
 	"os/exec"
 	"runtime"
+	"strings"
 
 	"pomodoro-cli/internal/timer"
 )
 
-// NotifySessionComplete はセッション完了通知を送信する
-func NotifySessionComplete(sessionType timer.SessionType) error {
-	return notify("Pomodoro", sessionType.String()+" completed")
+// NotifySessionComplete はセッション完了通知を送信する（suggestion は休憩の過ごし方の提案、空なら省略）
+func NotifySessionComplete(sessionType timer.SessionType, suggestion string) error {
+	message := sessionType.String() + " completed"
+	if suggestion != "" {
+		message += ". Break idea: " + suggestion
+	}
+	return notify("Pomodoro", message)
 }
 
 // notify はシステム通知を送信する
 func notify(title, message string) error {
 	switch runtime.GOOS {
 	case "darwin":
-		script := `display notification "` + message + `" with title "` + title + `"`
+		// 設定に書かれた提案などに含まれる引用符で AppleScript が壊れないようにする
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+		script := `display notification "` + quote.Replace(message) + `" with title "` + quote.Replace(title) + `"`
 		return exec.Command("osascript", "-e", script).Run()
 	case "linux":
 		return exec.Command("notify-send", title, message).Run()